@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/infrakit/cmd/infrakit/base"
+
+	"github.com/docker/infrakit/pkg/cli"
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/rpc/audit"
+	"github.com/docker/infrakit/pkg/run/local"
+	"github.com/docker/infrakit/pkg/run/scope"
+	"github.com/docker/infrakit/pkg/store/file"
+	"github.com/spf13/cobra"
+)
+
+var log = logutil.New("module", "cli/audit")
+
+func init() {
+	base.Register(Command)
+}
+
+// Command is the entrypoint
+func Command(scope scope.Scope) *cobra.Command {
+
+	///////////////////////////////////////////////////////////////////////////////////
+	// audit
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit log of mutating operations",
+	}
+
+	dir := cmd.PersistentFlags().String("dir", local.Getenv(local.EnvAuditDir, ""),
+		"Directory of the audit records")
+	quiet := cmd.PersistentFlags().BoolP("quiet", "q", false, "Print rows without column headers")
+
+	outputFlags, output := cli.Output()
+	pluginName := ""
+	failed := false
+	list := &cobra.Command{
+		Use:   "ls",
+		Short: "List audit records",
+		RunE: func(c *cobra.Command, args []string) error {
+
+			if len(args) != 0 {
+				c.Usage()
+				os.Exit(1)
+			}
+
+			if *dir == "" {
+				return fmt.Errorf("no audit directory. Set --dir or %s", local.EnvAuditDir)
+			}
+
+			all, err := audit.List(file.NewStore(audit.TypeName, *dir))
+			if err != nil {
+				return err
+			}
+
+			records := []audit.Record{}
+			for _, r := range all {
+				if pluginName != "" && r.Plugin != pluginName {
+					continue
+				}
+				if failed && r.Succeeded() {
+					continue
+				}
+				records = append(records, r)
+			}
+
+			return output(os.Stdout, records,
+				func(w io.Writer, v interface{}) error {
+					if !*quiet {
+						fmt.Fprintf(w, "%-30s\t%-20s\t%-20s\t%-30s\t%-16s\t%s\n",
+							"TIMESTAMP", "CALLER", "PLUGIN", "METHOD", "DIGEST", "RESULT")
+					}
+					for _, r := range records {
+						result := "ok"
+						if !r.Succeeded() {
+							result = r.Error
+						}
+						digest := r.Digest
+						if len(digest) > 16 {
+							digest = digest[:16]
+						}
+						fmt.Fprintf(w, "%-30s\t%-20s\t%-20s\t%-30s\t%-16s\t%s\n",
+							r.Timestamp.Format("2006-01-02T15:04:05.000Z07:00"),
+							r.Caller, r.Plugin, r.Interface.Name+"."+r.Method, digest, result)
+					}
+					return nil
+				})
+		},
+	}
+	list.Flags().StringVar(&pluginName, "plugin", pluginName, "Only show calls to the plugin")
+	list.Flags().BoolVar(&failed, "failed", failed, "Only show failed calls")
+	list.Flags().AddFlagSet(outputFlags)
+
+	cmd.AddCommand(list)
+	return cmd
+}
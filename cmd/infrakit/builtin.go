@@ -6,6 +6,7 @@ import (
 	_ "github.com/docker/infrakit/pkg/run/v0/combo"
 	_ "github.com/docker/infrakit/pkg/run/v0/enrollment"
 	_ "github.com/docker/infrakit/pkg/run/v0/file"
+	_ "github.com/docker/infrakit/pkg/run/v0/forwarder"
 	_ "github.com/docker/infrakit/pkg/run/v0/gc"
 	_ "github.com/docker/infrakit/pkg/run/v0/group"
 	_ "github.com/docker/infrakit/pkg/run/v0/image"
@@ -14,6 +15,7 @@ import (
 	_ "github.com/docker/infrakit/pkg/run/v0/manager"
 	_ "github.com/docker/infrakit/pkg/run/v0/pool"
 	_ "github.com/docker/infrakit/pkg/run/v0/resource"
+	_ "github.com/docker/infrakit/pkg/run/v0/schedule"
 	_ "github.com/docker/infrakit/pkg/run/v0/selector"
 	_ "github.com/docker/infrakit/pkg/run/v0/simulator"
 	_ "github.com/docker/infrakit/pkg/run/v0/swarm"
@@ -0,0 +1,155 @@
+package fsm // import "github.com/docker/infrakit/cmd/infrakit/fsm"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/docker/infrakit/cmd/infrakit/base"
+
+	"github.com/docker/infrakit/pkg/cli"
+	"github.com/docker/infrakit/pkg/controller/gc"
+	"github.com/docker/infrakit/pkg/fsm"
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/run/scope"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	// builtin models for gc
+	_ "github.com/docker/infrakit/pkg/controller/gc/model/kubernetes"
+	_ "github.com/docker/infrakit/pkg/controller/gc/model/swarm"
+)
+
+var log = logutil.New("module", "cli/fsm")
+
+func init() {
+	base.Register(Command)
+}
+
+// Command is the entrypoint
+func Command(scope scope.Scope) *cobra.Command {
+
+	services := cli.NewServices(scope)
+
+	///////////////////////////////////////////////////////////////////////////////////
+	// fsm
+	cmd := &cobra.Command{
+		Use:   "fsm",
+		Short: "Work with the specs of finite state machines",
+	}
+
+	///////////////////////////////////////////////////////////////////////////////////
+	// fsm render
+	render := &cobra.Command{
+		Use:   "render [<url>]",
+		Short: "Render the fsm spec (YAML or JSON) at given url as a graph.  If url is '-', read from stdin",
+		Long: fmt.Sprintf(`Render the fsm spec (YAML or JSON) at given url as a graph.  If url is '-', read from stdin.
+
+With --model, the built-in model is rendered instead.  The built-in models are: %s`,
+			strings.Join(builtinModels(), ", ")),
+	}
+
+	outputFile := render.Flags().StringP("output", "o", "", "Output filename")
+	format := render.Flags().String("format", "dot", "Output format: dot, yaml or json")
+	name := render.Flags().String("name", "fsm", "Name of the graph")
+	model := render.Flags().String("model", "", "Built-in model to render instead of the spec at url, e.g. gc/swarm")
+	render.Flags().AddFlagSet(services.ProcessTemplateFlags)
+
+	render.RunE = func(c *cobra.Command, args []string) error {
+
+		var config fsm.Config
+
+		switch {
+		case *model != "" && len(args) == 0:
+			spec, err := builtinSpec(*model)
+			if err != nil {
+				return err
+			}
+			config = spec.Config()
+
+		case *model == "" && len(args) == 1:
+			url := args[0]
+			if url == "-" {
+				buff, err := ioutil.ReadAll(os.Stdin)
+				if err != nil {
+					return err
+				}
+				url = fmt.Sprintf("str://%s", string(buff))
+			}
+
+			view, err := services.ProcessTemplate(url)
+			if err != nil {
+				return err
+			}
+
+			config, err = fsm.ParseConfig([]byte(view))
+			if err != nil {
+				return err
+			}
+
+			if err := config.Validate(); err != nil {
+				return err
+			}
+
+		default:
+			c.Usage()
+			os.Exit(1)
+		}
+
+		log.Debug("Rendering", "config", config)
+
+		var out []byte
+		switch strings.ToLower(*format) {
+		case "dot":
+			out = []byte(config.Dot(*name))
+		case "yaml", "json":
+			// normalize by compiling the spec so all the indexes are included
+			spec, err := config.Define(config.NoopActions())
+			if err != nil {
+				return err
+			}
+			if strings.ToLower(*format) == "json" {
+				out, err = json.MarshalIndent(spec.Config(), "", "  ")
+			} else {
+				out, err = yaml.Marshal(spec.Config())
+			}
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown format %s", *format)
+		}
+
+		if *outputFile != "" {
+			return ioutil.WriteFile(*outputFile, out, 0644)
+		}
+
+		fmt.Print(string(out))
+		return nil
+	}
+
+	cmd.AddCommand(render)
+
+	return cmd
+}
+
+// builtinModels returns the names of the built-in models, as <controller>/<model>
+func builtinModels() []string {
+	names := []string{}
+	for _, model := range gc.Models() {
+		names = append(names, "gc/"+model)
+	}
+	return names
+}
+
+// builtinSpec returns the spec of the built-in model, named as <controller>/<model>
+func builtinSpec(name string) (*fsm.Spec, error) {
+	for _, builtin := range builtinModels() {
+		if builtin == name {
+			return gc.ModelSpec(strings.TrimPrefix(name, "gc/"))
+		}
+	}
+	return nil, fmt.Errorf("unknown model %s, the built-in models are: %s", name, strings.Join(builtinModels(), ", "))
+}
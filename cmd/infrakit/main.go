@@ -18,12 +18,15 @@ import (
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/plugin"
 	rpc "github.com/docker/infrakit/pkg/rpc/client"
+	"github.com/docker/infrakit/pkg/rpc/trace"
 	"github.com/docker/infrakit/pkg/run/local"
 	"github.com/docker/infrakit/pkg/run/scope"
 	"github.com/docker/infrakit/pkg/template"
 	"github.com/spf13/cobra"
 
 	// CLI commands
+	_ "github.com/docker/infrakit/cmd/infrakit/audit"
+	_ "github.com/docker/infrakit/cmd/infrakit/fsm"
 	_ "github.com/docker/infrakit/cmd/infrakit/manager"
 	_ "github.com/docker/infrakit/cmd/infrakit/playbook"
 	_ "github.com/docker/infrakit/cmd/infrakit/plugin"
@@ -86,6 +89,10 @@ func main() {
 		PersistentPreRunE: func(c *cobra.Command, args []string) error {
 			logutil.Configure(logOptions)
 
+			if err := trace.Configure(os.Getenv(local.EnvTraceFile), os.Getenv(local.EnvTraceCollector)); err != nil {
+				return err
+			}
+
 			if logOptions.Level == 5 {
 				// Debug level. Start pprof
 				go func() {
@@ -172,6 +179,7 @@ func main() {
 		cmd.SetArgs(os.Args[1:2])
 	}
 	err = cmd.Execute()
+	trace.Flush()
 	if err != nil {
 		log.Crit("error executing", "cmd", cmd.Use, "err", err)
 		fmt.Println(err.Error())
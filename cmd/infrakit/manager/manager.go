@@ -10,6 +10,7 @@ import (
 	"github.com/docker/infrakit/cmd/infrakit/manager/schema"
 
 	"github.com/docker/infrakit/pkg/cli"
+	manager_cli "github.com/docker/infrakit/pkg/cli/v0/manager"
 	group_types "github.com/docker/infrakit/pkg/controller/group/types"
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/plugin"
@@ -40,6 +41,8 @@ func Command(scope scope.Scope) *cobra.Command {
 	var groupPlugin group.Plugin
 	var groupPluginName string
 
+	var stackPlugin stack.Interface
+
 	var updatablePlugin metadata.Updatable
 	var updatablePluginName string
 
@@ -73,6 +76,7 @@ func Command(scope scope.Scope) *cobra.Command {
 					if isLeader {
 
 						pn := plugin.Name(name)
+						stackPlugin = m
 						groupPlugin = group_rpc.Adapt(pn, rpcClient)
 						groupPluginName = name
 
@@ -224,7 +228,17 @@ func Command(scope scope.Scope) *cobra.Command {
 		},
 	}
 
-	cmd.AddCommand(commit, inspect, leader)
+	///////////////////////////////////////////////////////////////////////////////////
+	// history, diff, rollback, leader step-down
+	leaderStack := func() (stack.Interface, error) { return stackPlugin, nil }
+
+	leader.AddCommand(manager_cli.StepDownCommand(services, leaderStack))
+
+	cmd.AddCommand(commit, inspect, leader,
+		manager_cli.HistoryCommand(services, leaderStack),
+		manager_cli.DiffCommand(services, leaderStack),
+		manager_cli.RollbackCommand(services, leaderStack),
+	)
 
 	return cmd
 }
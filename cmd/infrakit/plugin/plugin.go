@@ -2,6 +2,7 @@ package plugin // import "github.com/docker/infrakit/cmd/infrakit/plugin"
 
 import (
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -13,6 +14,7 @@ import (
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/plugin"
 	"github.com/docker/infrakit/pkg/rpc/client"
+	"github.com/docker/infrakit/pkg/run/local"
 	"github.com/docker/infrakit/pkg/run/manager"
 	"github.com/docker/infrakit/pkg/run/scope"
 	group_kind "github.com/docker/infrakit/pkg/run/v0/group"
@@ -113,9 +115,19 @@ func Command(scope scope.Scope) *cobra.Command {
 	start := &cobra.Command{
 		Use:   "start",
 		Short: "Start named plugins. Args are a list of plugin names",
+		Long: `Start named plugins. Args are a list of plugin names.
+
+Plugins listening on tcp serve https when a TLS certificate is given, and require clients to present
+certificates signed by the CA if a CA certificate is given.`,
 	}
 
 	configURL := start.Flags().String("config-url", "", "URL for the startup configs")
+	tlsCAFile := start.Flags().String("tls-ca", local.Getenv(local.EnvTLSCAFile, ""),
+		"CA certificate for verifying client certificates")
+	tlsCertFile := start.Flags().String("tls-cert", local.Getenv(local.EnvTLSCertFile, ""),
+		"TLS certificate; enables https for plugins listening on tcp")
+	tlsKeyFile := start.Flags().String("tls-key", local.Getenv(local.EnvTLSKeyFile, ""),
+		"TLS private key")
 
 	services := cli.NewServices(scope)
 	start.Flags().AddFlagSet(services.ProcessTemplateFlags)
@@ -123,6 +135,18 @@ func Command(scope scope.Scope) *cobra.Command {
 	start.RunE = func(c *cobra.Command, args []string) error {
 
 		log.Info("config", "url", *configURL)
+
+		// The plugins started, in process or not, read the TLS options from the environment
+		for env, value := range map[string]string{
+			local.EnvTLSCAFile:   *tlsCAFile,
+			local.EnvTLSCertFile: *tlsCertFile,
+			local.EnvTLSKeyFile:  *tlsKeyFile,
+		} {
+			if err := os.Setenv(env, value); err != nil {
+				return err
+			}
+		}
+
 		pluginManager, err := cli.PluginManager(scope, services, *configURL)
 		if err != nil {
 			return err
@@ -4,10 +4,13 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/docker/go-connections/tlsconfig"
 	"github.com/docker/infrakit/pkg/discovery"
 	"github.com/docker/infrakit/pkg/leader"
 	"github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/rpc/auth"
 	"github.com/docker/infrakit/pkg/rpc/mux"
+	"github.com/docker/infrakit/pkg/run/local"
 	"github.com/docker/infrakit/pkg/run/scope"
 	"github.com/spf13/cobra"
 )
@@ -23,6 +26,10 @@ type config struct {
 	plugins      func() discovery.Plugins
 	poller       *leader.Poller
 	store        leader.Store
+	tlsCAFile    *string
+	tlsCertFile  *string
+	tlsKeyFile   *string
+	authPolicy   *string
 }
 
 // Command returns the cobra command
@@ -39,6 +46,14 @@ func Command(scp scope.Scope) *cobra.Command {
 	interval := cmd.PersistentFlags().DurationP("scan", "s", 1*time.Minute, "Scan interval to check for plugins")
 	pollInterval := cmd.PersistentFlags().DurationP("poll-interval", "p", 5*time.Second, "Leader polling interval")
 	locateURL := cmd.Flags().StringP("locate-url", "u", "", "Locate URL of this node, eg. http://public_ip:24864")
+	tlsCAFile := cmd.PersistentFlags().String("tls-ca", local.Getenv(local.EnvTLSCAFile, ""),
+		"CA certificate for verifying client certificates")
+	tlsCertFile := cmd.PersistentFlags().String("tls-cert", local.Getenv(local.EnvTLSCertFile, ""),
+		"TLS certificate; enables https")
+	tlsKeyFile := cmd.PersistentFlags().String("tls-key", local.Getenv(local.EnvTLSKeyFile, ""),
+		"TLS private key")
+	authPolicy := cmd.PersistentFlags().String("auth-policy", local.Getenv(local.EnvAuthPolicyFile, ""),
+		"Authorization policy file")
 
 	config := &config{
 		location:     locateURL,
@@ -47,6 +62,10 @@ func Command(scp scope.Scope) *cobra.Command {
 		autoStop:     autoStop,
 		interval:     interval,
 		pollInterval: pollInterval,
+		tlsCAFile:    tlsCAFile,
+		tlsCertFile:  tlsCertFile,
+		tlsKeyFile:   tlsKeyFile,
+		authPolicy:   authPolicy,
 	}
 
 	cmd.RunE = func(c *cobra.Command, args []string) error {
@@ -80,11 +99,31 @@ func runMux(config *config) error {
 	if err != nil {
 		return err
 	}
-	logger.Info("Starting mux server", "listen", *config.listen)
+	var tlsOptions *tlsconfig.Options
+	if *config.tlsCertFile != "" {
+		tlsOptions = &tlsconfig.Options{
+			CAFile:   *config.tlsCAFile,
+			CertFile: *config.tlsCertFile,
+			KeyFile:  *config.tlsKeyFile,
+		}
+	}
+
+	var policy *auth.Policy
+	if *config.authPolicy != "" {
+		policy, err = auth.LoadPolicy(*config.authPolicy)
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.Info("Starting mux server", "listen", *config.listen, "tls", tlsOptions != nil,
+		"policy", *config.authPolicy)
 	server, err := mux.NewServer(*config.listen, advertise.Host, config.plugins,
 		mux.Options{
 			Leadership: leadership,
 			Registry:   config.store,
+			TLS:        tlsOptions,
+			Policy:     policy,
 		})
 	if err != nil {
 		return err
@@ -67,7 +67,7 @@ func osEnvironment(cfg *config) *cobra.Command {
 			return err
 		}
 
-		cfg.poller = poller
+		cfg.poller = poller.Poller
 		cfg.store = file.NewStore(*leaderLocation)
 
 		return runMux(cfg)
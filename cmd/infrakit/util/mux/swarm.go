@@ -34,7 +34,7 @@ func swarmEnvironment(cfg *config) *cobra.Command {
 		}
 		defer dockerClient.Close()
 
-		cfg.poller = swarm.NewDetector(*cfg.pollInterval, dockerClient)
+		cfg.poller = swarm.NewDetector(*cfg.pollInterval, dockerClient).Poller
 		cfg.store = swarm.NewStore(dockerClient)
 
 		return runMux(cfg)
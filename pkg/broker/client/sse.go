@@ -3,12 +3,14 @@ package client // import "github.com/docker/infrakit/pkg/broker/client"
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
+	"time"
 
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/types"
@@ -16,8 +18,10 @@ import (
 
 var (
 	headerData = []byte("data:")
+	headerID   = []byte("id:")
 
-	log = logutil.New("module", "broker/client")
+	log    = logutil.New("module", "broker/client")
+	debugV = logutil.V(500)
 )
 
 // Options contain client options
@@ -27,6 +31,16 @@ type Options struct {
 
 	// Path is the URL path, if the server's handler is at the mux path (e.g. /events)
 	Path string
+
+	// LastEventID is the id of the last event received.  The stream resumes after this event.
+	LastEventID string
+
+	// Reconnect is the time to wait before reconnecting when the stream is lost.  The client
+	// resumes from the last event received.  No reconnects if 0.
+	Reconnect time.Duration
+
+	// Query are additional parameters of the subscription, e.g. a filter of the events
+	Query url.Values
 }
 
 func processEvent(msg []byte) []byte {
@@ -116,85 +130,187 @@ func Subscribe(url, topic string, opt Options) (
 		u.Path = path.Join(u.Path, opt.Path)
 	}
 
-	req, err := http.NewRequest("GET", u.String(), nil)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
 	// Setup request, specify stream to connect to
-	query := req.URL.Query()
+	query := u.Query()
 	if query["topic"] == nil {
 		query.Add("topic", topic)
-		req.URL.RawQuery = query.Encode()
+	}
+	for k, v := range opt.Query {
+		query[k] = v
+	}
+	u.RawQuery = query.Encode()
+
+	s := &stream{
+		url:        u.String(),
+		connection: connection,
+		tsport:     tsport,
+		lastID:     opt.LastEventID,
+		streamCh:   make(chan *types.Any),
+		doneCh:     make(chan struct{}),
+		errCh:      make(chan error),
 	}
 
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Connection", "keep-alive")
-
-	streamCh := make(chan *types.Any)
-	doneCh := make(chan struct{})
-	errCh := make(chan error)
+	// make sure the url is good before starting
+	if _, err := s.request(); err != nil {
+		return nil, nil, nil, err
+	}
 
+	// cancel the request in flight when done is closed
+	ctx, cancel := context.WithCancel(context.Background())
+	s.ctx = ctx
 	go func() {
-
-		resp, err := connection.Do(req)
-		if err != nil {
-			errCh <- err
-			close(errCh)
-			close(streamCh)
-			return
+		select {
+		case <-s.doneCh:
+		case <-ctx.Done():
 		}
+		cancel()
+	}()
 
+	go func() {
 		defer func() {
-			resp.Body.Close()
-			log.Debug("canceling request", "req", req)
-			tsport.CancelRequest(req)
-			close(streamCh)
-			close(errCh)
+			cancel()
+			close(s.streamCh)
+			close(s.errCh)
 		}()
 
-		if resp.StatusCode != http.StatusOK {
-			errCh <- fmt.Errorf("http-status:%v", resp.StatusCode)
-			return
-		}
+		for {
+			connected, err := s.read()
+			if err == nil {
+				return // done
+			}
 
-		reader := bufio.NewReader(resp.Body)
+			if opt.Reconnect == 0 {
+				s.errCh <- err
+				return
+			}
 
-		for {
-			select {
+			// Report only the loss of the stream, not the failed attempts to reconnect.
+			if connected {
+				select {
+				case s.errCh <- err:
+				case <-s.doneCh:
+					return
+				}
+			} else {
+				log.Debug("cannot connect", "url", s.url, "err", err, "V", debugV)
+			}
 
-			case <-doneCh:
-				log.Info("close", "connection", connection)
+			select {
+			case <-s.doneCh:
 				return
+			case <-time.After(opt.Reconnect):
+				log.Info("reconnecting", "url", s.url, "lastEventID", s.lastID)
+			}
+		}
+	}()
+
+	return s.streamCh, s.errCh, s.doneCh, nil
+}
+
+type stream struct {
+	ctx        context.Context
+	url        string
+	connection *http.Client
+	tsport     *http.Transport
+
+	// lastID is the id of the last event received
+	lastID string
+
+	streamCh chan *types.Any
+	doneCh   chan struct{}
+	errCh    chan error
+}
+
+func (s *stream) request() (*http.Request, error) {
+	req, err := http.NewRequest("GET", s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.ctx != nil {
+		req = req.WithContext(s.ctx)
+	}
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Connection", "keep-alive")
+	if s.lastID != "" {
+		req.Header.Set("Last-Event-ID", s.lastID)
+	}
+	return req, nil
+}
+
+// read connects and reads the stream until done is closed, in which case the error is nil.
+func (s *stream) read() (connected bool, err error) {
+	req, err := s.request()
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := s.connection.Do(req)
+	if err != nil {
+		select {
+		case <-s.doneCh:
+			return false, nil // closed by the subscriber
+		default:
+		}
+		return false, err
+	}
+
+	defer func() {
+		resp.Body.Close()
+		log.Debug("canceling request", "req", req)
+		s.tsport.CancelRequest(req)
+	}()
 
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("http-status:%v", resp.StatusCode)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+
+	for {
+		select {
+
+		case <-s.doneCh:
+			log.Info("close", "connection", s.connection)
+			return true, nil
+
+		default:
+		}
+
+		// Read each new line and process the type of event
+		line, err := reader.ReadBytes('\n')
+
+		if err != nil {
+			select {
+			case <-s.doneCh:
+				return true, nil // closed by the subscriber
 			default:
 			}
+			return true, err
+		}
 
-			// Read each new line and process the type of event
-			line, err := reader.ReadBytes('\n')
+		if bytes.HasPrefix(line, headerID) {
 
-			if err != nil {
-				errCh <- err
-				return
-			}
-			if bytes.Contains(line, headerData) {
+			s.lastID = string(bytes.TrimSpace(line[len(headerID):]))
 
-				if data := trimHeader(len(headerData), line); len(data) > 0 {
+		} else if bytes.Contains(line, headerData) {
 
-					streamCh <- types.AnyBytes(data)
+			if data := trimHeader(len(headerData), line); len(data) > 0 {
 
-				} else {
+				select {
+				case s.streamCh <- types.AnyBytes(data):
+				case <-s.doneCh:
+					return true, nil
+				}
 
-					select {
-					case errCh <- fmt.Errorf("no data: %s", string(line)):
-					default:
-					}
+			} else {
 
+				select {
+				case s.errCh <- fmt.Errorf("no data: %s", string(line)):
+				default:
 				}
+
 			}
 		}
-	}()
-
-	return streamCh, errCh, doneCh, nil
+	}
 }
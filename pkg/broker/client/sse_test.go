@@ -3,7 +3,10 @@ package client // import "github.com/docker/infrakit/pkg/broker/client"
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -160,8 +163,10 @@ func TestBrokerMultiSubscriberCustomObject(t *testing.T) {
 		for {
 			select {
 
-			case e := <-errs1:
-				panic(e)
+			case e, ok := <-errs1:
+				if ok {
+					panic(e)
+				}
 			case m, ok := <-topic1:
 				if ok {
 					var val event
@@ -169,6 +174,7 @@ func TestBrokerMultiSubscriberCustomObject(t *testing.T) {
 					received1 <- val
 				} else {
 					close(received1)
+					return
 				}
 			}
 		}
@@ -180,8 +186,10 @@ func TestBrokerMultiSubscriberCustomObject(t *testing.T) {
 		for {
 			select {
 
-			case e := <-errs2:
-				panic(e)
+			case e, ok := <-errs2:
+				if ok {
+					panic(e)
+				}
 			case m, ok := <-topic2:
 				if ok {
 					var val event
@@ -189,6 +197,7 @@ func TestBrokerMultiSubscriberCustomObject(t *testing.T) {
 					received2 <- val
 				} else {
 					close(received2)
+					return
 				}
 			}
 		}
@@ -249,8 +258,10 @@ func TestBrokerMultiSubscriberPartialMatchTopic(t *testing.T) {
 
 		for {
 			select {
-			case e := <-errs1:
-				t.Log("!!!!!!!!!!!!!!!!! FLAKY TEST !!!!!!!!!!!!", e)
+			case e, ok := <-errs1:
+				if ok {
+					t.Log("!!!!!!!!!!!!!!!!! FLAKY TEST !!!!!!!!!!!!", e)
+				}
 			case m, ok := <-topic1:
 				if ok {
 					var val event
@@ -258,6 +269,7 @@ func TestBrokerMultiSubscriberPartialMatchTopic(t *testing.T) {
 					received1 <- val
 				} else {
 					close(received1)
+					return
 				}
 			}
 		}
@@ -273,8 +285,10 @@ func TestBrokerMultiSubscriberPartialMatchTopic(t *testing.T) {
 
 		for {
 			select {
-			case e := <-errs2:
-				t.Log("!!!!!!!!!!!!!!!!! FLAKY TEST !!!!!!!!!!!!", e)
+			case e, ok := <-errs2:
+				if ok {
+					t.Log("!!!!!!!!!!!!!!!!! FLAKY TEST !!!!!!!!!!!!", e)
+				}
 			case m, ok := <-topic2:
 				if ok {
 					var val event
@@ -282,6 +296,7 @@ func TestBrokerMultiSubscriberPartialMatchTopic(t *testing.T) {
 					received2 <- val
 				} else {
 					close(received2)
+					return
 				}
 			}
 		}
@@ -338,8 +353,10 @@ func TestBrokerSubscriberExactMatchTopic(t *testing.T) {
 	go func() {
 		for {
 			select {
-			case e := <-errs1:
-				t.Log("!!!!!!!!!!!!!!!!! FLAKY TEST !!!!!!!!!!!!", e)
+			case e, ok := <-errs1:
+				if ok {
+					t.Log("!!!!!!!!!!!!!!!!! FLAKY TEST !!!!!!!!!!!!", e)
+				}
 			case m, ok := <-topic1:
 				if ok {
 					var val event
@@ -360,8 +377,10 @@ func TestBrokerSubscriberExactMatchTopic(t *testing.T) {
 	go func() {
 		for {
 			select {
-			case e := <-errs2:
-				t.Log("!!!!!!!!!!!!!!!!! FLAKY TEST !!!!!!!!!!!!", e)
+			case e, ok := <-errs2:
+				if ok {
+					t.Log("!!!!!!!!!!!!!!!!! FLAKY TEST !!!!!!!!!!!!", e)
+				}
 			case m, ok := <-topic2:
 				if ok {
 					var val event
@@ -382,8 +401,10 @@ func TestBrokerSubscriberExactMatchTopic(t *testing.T) {
 	go func() {
 		for {
 			select {
-			case e := <-errs3:
-				t.Log("!!!!!!!!!!!!!!!!! FLAKY TEST !!!!!!!!!!!!", e)
+			case e, ok := <-errs3:
+				if ok {
+					t.Log("!!!!!!!!!!!!!!!!! FLAKY TEST !!!!!!!!!!!!", e)
+				}
 			case m, ok := <-topic3:
 				if ok {
 					var val event
@@ -473,8 +494,10 @@ func TestBrokerMultiSubscriberCustomObjectConnectAtURLPrefix(t *testing.T) {
 		for {
 			select {
 
-			case e := <-errs2:
-				panic(e)
+			case e, ok := <-errs2:
+				if ok {
+					panic(e)
+				}
 			case m := <-topic2:
 				var val event
 				require.NoError(t, m.Decode(&val))
@@ -503,3 +526,105 @@ func TestBrokerMultiSubscriberCustomObjectConnectAtURLPrefix(t *testing.T) {
 	broker.Stop()
 
 }
+
+func TestSubscribeReconnect(t *testing.T) {
+
+	broker := server.NewBroker()
+	srv := httptest.NewServer(broker)
+	defer srv.Close()
+	defer broker.Stop()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, broker.Publish("local/tick", i))
+	}
+
+	// start from the first event
+	opts := Options{LastEventID: "0", Reconnect: 10 * time.Millisecond}
+	messages, errs, done, err := Subscribe(srv.URL, "local/tick", opts)
+	require.NoError(t, err)
+	defer close(done)
+
+	next := func() int {
+		var val int
+		select {
+		case m := <-messages:
+			require.NoError(t, m.Decode(&val))
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout")
+		}
+		return val
+	}
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, i, next())
+	}
+
+	// drop the connection and publish while the client is away
+	srv.CloseClientConnections()
+	require.Error(t, <-errs)
+
+	for i := 3; i < 6; i++ {
+		require.NoError(t, broker.Publish("local/tick", i))
+	}
+
+	// no gaps after reconnecting
+	for i := 3; i < 6; i++ {
+		require.Equal(t, i, next())
+	}
+}
+
+func TestSubscribeReconnectBeforeEvents(t *testing.T) {
+
+	broker := server.NewBroker()
+	flushed := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		broker.ServeHTTP(&flushNotifier{ResponseWriter: rw, flushed: flushed}, req)
+	}))
+	defer srv.Close()
+	defer broker.Stop()
+
+	require.NoError(t, broker.Publish("local/tick", -1)) // before the subscription; not replayed
+
+	// no Last-Event-ID: start from now
+	opts := Options{Reconnect: 500 * time.Millisecond}
+	messages, errs, done, err := Subscribe(srv.URL, "local/tick", opts)
+	require.NoError(t, err)
+	defer close(done)
+
+	<-flushed // connected and the starting id is sent
+
+	// drop the connection before any event and publish while the client is away
+	srv.CloseClientConnections()
+	require.Error(t, <-errs)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, broker.Publish("local/tick", i))
+	}
+
+	for i := 0; i < 3; i++ {
+		var val int
+		select {
+		case m := <-messages:
+			require.NoError(t, m.Decode(&val))
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout")
+		}
+		require.Equal(t, i, val)
+	}
+}
+
+// flushNotifier signals when the broker flushes the start of a stream
+type flushNotifier struct {
+	http.ResponseWriter
+	flushed chan struct{}
+	once    sync.Once
+}
+
+func (f *flushNotifier) Flush() {
+	f.ResponseWriter.(http.Flusher).Flush()
+	f.once.Do(func() { f.flushed <- struct{}{} })
+}
+
+func (f *flushNotifier) CloseNotify() <-chan bool {
+	return f.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
@@ -0,0 +1,148 @@
+package server // import "github.com/docker/infrakit/pkg/broker/server"
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const logFileExt = ".events"
+
+// entry is an event in the log of a topic
+type entry struct {
+	ID    uint64
+	Topic string
+	Data  json.RawMessage
+}
+
+// topicLog is the bounded log of the events of a topic, in the order of their ids
+type topicLog struct {
+	topic   string
+	entries []entry
+
+	// file is the file the log is persisted in.  Not persisted if empty.
+	file string
+
+	// written is the number of entries in the file
+	written int
+}
+
+func (l *topicLog) append(e entry, retain int) error {
+	l.entries = append(l.entries, e)
+	if len(l.entries) > retain {
+		l.entries = l.entries[len(l.entries)-retain:]
+	}
+
+	if l.file == "" {
+		return nil
+	}
+
+	// compact the file once it has twice the entries retained
+	if l.written >= 2*retain {
+		return l.compact()
+	}
+
+	f, err := os.OpenFile(l.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buff, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(buff, '\n')); err != nil {
+		return err
+	}
+	l.written++
+	return nil
+}
+
+// compact rewrites the file with only the entries retained
+func (l *topicLog) compact() error {
+	tmp := l.file + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	for _, e := range l.entries {
+		buff, err := json.Marshal(e)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := f.Write(append(buff, '\n')); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	l.written = len(l.entries)
+	return os.Rename(tmp, l.file)
+}
+
+// since returns the entries after the given id
+func (l *topicLog) since(id uint64) []entry {
+	i := sort.Search(len(l.entries), func(i int) bool { return l.entries[i].ID > id })
+	return append([]entry{}, l.entries[i:]...)
+}
+
+// logFile returns the name of the file of the log of the topic
+func logFile(dir, topic string) string {
+	return filepath.Join(dir, url.QueryEscape(topic)+logFileExt)
+}
+
+// loadLogs loads the logs persisted in the directory
+func loadLogs(dir string, retain int) ([]*topicLog, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := []*topicLog{}
+	for _, info := range files {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), logFileExt) {
+			continue
+		}
+		topic, err := url.QueryUnescape(strings.TrimSuffix(info.Name(), logFileExt))
+		if err != nil {
+			log.Warn("Skipping file", "name", info.Name(), "err", err)
+			continue
+		}
+
+		l := &topicLog{topic: topic, file: filepath.Join(dir, info.Name())}
+		f, err := os.Open(l.file)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 16*1024*1024)
+		for scanner.Scan() {
+			e := entry{}
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				log.Warn("Skipping bad entry", "file", l.file, "err", err)
+				continue
+			}
+			l.entries = append(l.entries, e)
+			l.written++
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+
+		if len(l.entries) > retain {
+			l.entries = l.entries[len(l.entries)-retain:]
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
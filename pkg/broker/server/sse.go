@@ -2,72 +2,107 @@ package server // import "github.com/docker/infrakit/pkg/broker/server"
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/armon/go-radix"
 	"github.com/docker/infrakit/pkg/types"
 )
 
-// the amount of time to wait when pushing a message to
-// a slow client or a client that closed after `range clients` started.
-const patience time.Duration = time.Second * 1
+// DefaultRetain is the number of events retained per topic by default
+const DefaultRetain = 100
 
-type subscription struct {
-	topic      string
-	exactMatch bool
-	ch         chan []byte
-}
+// Options are the options of the broker
+type Options struct {
+	// Retain is the number of events retained per topic for the clients that resume their streams
+	Retain int
+
+	// Dir is the directory where the events are persisted.  Events are kept in memory only if empty.
+	Dir string
 
-type event struct {
-	topic string
-	data  []byte
+	// Filters returns the filter of a subscription from the query of its request.  All events are
+	// written to the stream if the filter is nil.
+	Filters func(query url.Values) (Filter, error)
 }
 
-// Broker is the event message broker
+// Filter returns true if the data of an event is to be written to the stream of a subscription
+type Filter func(data []byte) bool
+
+// Broker is the event message broker.  Events published are appended to the log of their topics
+// and assigned monotonically increasing ids.  Clients resume their streams after the id in the
+// Last-Event-ID header as long as the events are still retained.
 type Broker struct {
+	options Options
 
 	// Close this to stop
 	stop chan struct{}
+	once sync.Once
 
-	// Close this to stop the run loop
-	finish chan struct{}
-
-	// Events are pushed to this channel by the main events-gathering routine
-	notifier chan *event
-
-	// New client connections
-	newClients chan subscription
+	// logs of the topics
+	logs *radix.Tree
 
-	// Closed client connections
-	closingClients chan subscription
+	// seq is the id of the last event
+	seq uint64
 
-	// Client connections registry
-	clients *radix.Tree
+	// updated is closed and replaced on each event appended
+	updated chan struct{}
 
-	// how many clients
-	count int
+	lock sync.RWMutex
 }
 
-// NewBroker returns an instance of the broker
+// NewBroker returns an instance of the broker that keeps the events in memory
 func NewBroker() *Broker {
+	b, _ := NewBrokerWithOptions(Options{})
+	return b
+}
+
+// NewBrokerWithOptions returns an instance of the broker.  Events persisted in the directory
+// of the options are loaded so they can be replayed.
+func NewBrokerWithOptions(options Options) (*Broker, error) {
+	if options.Retain <= 0 {
+		options.Retain = DefaultRetain
+	}
+
 	b := &Broker{
-		stop:           make(chan struct{}),
-		finish:         make(chan struct{}),
-		notifier:       make(chan *event, 1),
-		newClients:     make(chan subscription),
-		closingClients: make(chan subscription),
-		clients:        radix.New(),
+		options: options,
+		stop:    make(chan struct{}),
+		logs:    radix.New(),
+		updated: make(chan struct{}),
 	}
-	go b.run()
-	return b
+
+	// Start with the clock so the ids are still increasing if nothing was persisted
+	b.seq = uint64(time.Now().UnixNano())
+
+	if options.Dir != "" {
+		if err := os.MkdirAll(options.Dir, 0700); err != nil {
+			return nil, err
+		}
+		logs, err := loadLogs(options.Dir, options.Retain)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range logs {
+			b.logs.Insert(l.topic, l)
+			if n := len(l.entries); n > 0 && l.entries[n-1].ID > b.seq {
+				b.seq = l.entries[n-1].ID
+			}
+		}
+		log.Info("Loaded events", "dir", options.Dir, "topics", len(logs), "seq", b.seq)
+	}
+	return b, nil
 }
 
-// Stop stops the broker and exits the goroutine
+// Stop stops the broker and disconnects the clients
 func (b *Broker) Stop() {
-	close(b.stop)
+	b.once.Do(func() { close(b.stop) })
 }
 
 func clean(topic string) string {
@@ -86,7 +121,8 @@ func checkExactMatch(topic string) bool {
 	return strings.LastIndex(topic, "/") != len(topic)-1
 }
 
-// Publish publishes a message at the topic
+// Publish publishes a message at the topic.  The message is appended to the log of the topic
+// so the timeout is no longer used; it's kept for compatibility.
 func (b *Broker) Publish(topic string, data interface{}, optionalTimeout ...time.Duration) error {
 	any, err := types.AnyValue(data)
 	if err != nil {
@@ -95,19 +131,86 @@ func (b *Broker) Publish(topic string, data interface{}, optionalTimeout ...time
 
 	topic = clean(topic)
 
-	if len(optionalTimeout) > 0 {
-		select {
-		case b.notifier <- &event{topic: topic, data: any.Bytes()}:
-		case <-time.After(optionalTimeout[0]):
-			return fmt.Errorf("timeout sending %v", topic)
-		}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var l *topicLog
+	if v, has := b.logs.Get(topic); has {
+		l = v.(*topicLog)
 	} else {
-		b.notifier <- &event{topic: topic, data: any.Bytes()}
+		l = &topicLog{topic: topic}
+		if b.options.Dir != "" {
+			l.file = logFile(b.options.Dir, topic)
+		}
+		b.logs.Insert(topic, l)
+	}
+
+	b.seq++
+
+	// Remove any \n because it's meaningful in SSE spec.
+	// We could use base64 encode, but it hurts interoperability with browser/ javascript clients.
+	e := entry{
+		ID:    b.seq,
+		Topic: topic,
+		Data:  json.RawMessage(bytes.Replace(any.Bytes(), []byte("\n"), nil, -1)),
 	}
+	err = l.append(e, b.options.Retain)
 
+	// wake up the clients
+	close(b.updated)
+	b.updated = make(chan struct{})
+
+	if err != nil {
+		log.Warn("Cannot persist event", "topic", topic, "err", err)
+	}
 	return nil
 }
 
+// since returns the events after the id at the topic, in the order of their ids, as well as the
+// channel closed when more events are appended.
+func (b *Broker) since(topic string, exactMatch bool, id uint64) ([]entry, <-chan struct{}) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	entries := []entry{}
+	if exactMatch {
+		if v, has := b.logs.Get(topic); has {
+			entries = v.(*topicLog).since(id)
+		}
+		return entries, b.updated
+	}
+
+	b.logs.WalkPrefix(topic,
+		func(key string, value interface{}) bool {
+			entries = append(entries, value.(*topicLog).since(id)...)
+			return false
+		})
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, b.updated
+}
+
+// cursor returns the id of the last event the client received, from the Last-Event-ID header.
+// Without the header the client gets only the events from now on.
+func (b *Broker) cursor(req *http.Request) uint64 {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	last := req.Header.Get("Last-Event-ID")
+	if last == "" {
+		return b.seq
+	}
+	id, err := strconv.ParseUint(last, 10, 64)
+	if err != nil {
+		log.Warn("Bad Last-Event-ID", "id", last, "err", err)
+		return b.seq
+	}
+	if id > b.seq {
+		// the id is from another broker; replay what we have
+		return 0
+	}
+	return id
+}
+
 // ServerHTTP implements the HTTP handler
 func (b *Broker) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	defer func() {
@@ -117,6 +220,17 @@ func (b *Broker) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}()
 
 	topic := clean(req.URL.Query().Get("topic"))
+	exactMatch := checkExactMatch(topic)
+
+	var filter Filter
+	if b.options.Filters != nil {
+		f, err := b.options.Filters(req.URL.Query())
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter = f
+	}
 
 	// flusher is required for streaming
 	flusher, ok := rw.(http.Flusher)
@@ -130,136 +244,45 @@ func (b *Broker) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	rw.Header().Set("Connection", "keep-alive")
 	rw.Header().Set("Access-Control-Allow-Origin", "*")
 
-	// Each connection registers its own message channel with the Broker's connections registry
-	messageChan := make(chan []byte)
+	cursor := b.cursor(req)
+	log.Info("Connected", "topic", topic, "cursor", cursor)
+	defer log.Info("Disconnected", "topic", topic, "cursor", cursor)
 
-	// Signal the broker that we have a new connection
-	b.newClients <- subscription{topic: topic, exactMatch: checkExactMatch(topic), ch: messageChan}
+	// Send the headers now so the client knows it's connected.  The starting cursor is sent as
+	// the id of an event without data so the client can resume from it even if the connection
+	// drops before any event is written.
+	rw.WriteHeader(http.StatusOK)
+	fmt.Fprintf(rw, "id: %d\n\n", cursor)
+	flusher.Flush()
 
-	// Remove this client from the map of connected clients
-	// when this handler exits.
-	defer func() {
-		b.closingClients <- subscription{topic: topic, ch: messageChan}
-	}()
-
-	// Listen to connection close and un-register messageChan
+	// Listen to connection close
 	notify := rw.(http.CloseNotifier).CloseNotify()
 
 	for {
-		select {
-		case <-b.stop:
-			close(b.finish)
-			return
-
-		case <-notify:
-			return
-		default:
+		entries, updated := b.since(topic, exactMatch, cursor)
+		written := 0
+		for _, e := range entries {
+			cursor = e.ID
+			if filter != nil && !filter(e.Data) {
+				continue
+			}
+			written++
 
 			// Write to the ResponseWriter
 			// Server Sent Events compatible
-			fmt.Fprintf(rw, "data: %s\n\n", <-messageChan)
-
+			fmt.Fprintf(rw, "id: %d\ndata: %s\n\n", e.ID, e.Data)
+		}
+		if written > 0 {
 			// Flush the data immediatly instead of buffering it for later.
 			flusher.Flush()
 		}
-	}
-}
 
-func (b *Broker) run() {
-	for {
 		select {
-		case <-b.finish:
-
-			// Disconnect all clients
-			b.clients.Walk(
-				func(key string, value interface{}) bool {
-					chset, ok := value.(map[chan []byte]bool)
-					if !ok {
-						panic("assert-failed")
-					}
-					for ch := range chset {
-						log.Info("Closing client connection", "key", key, "ch", ch)
-						close(ch)
-					}
-					return false
-				})
-
-			log.Info("Broker finished")
+		case <-b.stop:
 			return
-
-		case subscription := <-b.newClients:
-
-			// A new client has connected.
-			// Register their message channel
-			subs := map[chan []byte]bool{subscription.ch: subscription.exactMatch}
-			v, has := b.clients.Get(subscription.topic)
-			if has {
-				if v, ok := v.(map[chan []byte]bool); !ok {
-					panic("assert-failed: not a map of channels")
-				} else {
-					v[subscription.ch] = subscription.exactMatch
-					subs = v
-				}
-			}
-
-			b.clients.Insert(subscription.topic, subs)
-			b.count++
-			log.Info("Connected", "topic", subscription.topic, "clients", b.count, "subCh", subscription.ch)
-
-		case subscription := <-b.closingClients:
-
-			// A client has dettached and we want to stop sending messages
-			if v, has := b.clients.Get(subscription.topic); has {
-				if subs, ok := v.(map[chan []byte]bool); !ok {
-					panic("assert-failed: not a map of channels")
-
-				} else {
-
-					delete(subs, subscription.ch)
-
-					if len(subs) == 0 {
-						b.clients.Delete(subscription.topic)
-					} else {
-						b.clients.Insert(subscription.topic, subs)
-					}
-
-					b.count--
-					log.Info("Disconnected", "topic", subscription.topic, "clients", b.count, "subCh", subscription.ch)
-				}
-			}
-
-		case event, open := <-b.notifier:
-
-			if !open {
-				log.Info("Stopping broker")
-				return
-			}
-
-			// Remove any \n because it's meaningful in SSE spec.
-			// We could use base64 encode, but it hurts interoperability with browser/ javascript clients.
-			data := bytes.Replace(event.data, []byte("\n"), nil, -1)
-
-			b.clients.WalkPath(event.topic,
-
-				func(key string, value interface{}) bool {
-					chset, ok := value.(map[chan []byte]bool)
-					if !ok {
-						panic("assert-failed")
-					}
-
-					for ch, exact := range chset {
-						if exact && event.topic != key {
-							return false
-						}
-						select {
-						case ch <- data:
-						case <-time.After(patience):
-							log.Warn("Skipping client.")
-						}
-					}
-					return false
-				})
+		case <-notify:
+			return
+		case <-updated:
 		}
 	}
-
 }
@@ -1,7 +1,12 @@
 package server // import "github.com/docker/infrakit/pkg/broker/server"
 
 import (
+	"bufio"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -187,3 +192,83 @@ func TestBrokerNoSubscribers(t *testing.T) {
 
 	}
 }
+
+func TestBrokerReplayLastEventID(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "infrakit-test-events-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	broker, err := NewBrokerWithOptions(Options{Retain: 3, Dir: dir})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(broker)
+	defer server.Close()
+
+	// more than twice the retained so the files are compacted
+	for i := 0; i < 8; i++ {
+		require.NoError(t, broker.Publish("local/time/tick", i))
+		require.NoError(t, broker.Publish("local/other", 100+i))
+	}
+
+	// reads the events after the given id until the count
+	read := func(topic, last string, count int) (ids []string, data []string) {
+		req, err := http.NewRequest("GET", server.URL+"/?topic="+topic, nil)
+		require.NoError(t, err)
+		if last != "" {
+			req.Header.Set("Last-Event-ID", last)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		reader := bufio.NewReader(resp.Body)
+
+		// the starting cursor is sent first, without data
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(line, "id: "))
+
+		id := ""
+		for len(data) < count {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimSpace(line[len("id: "):])
+			case strings.HasPrefix(line, "data: "):
+				ids = append(ids, id)
+				data = append(data, strings.TrimSpace(line[len("data: "):]))
+			}
+		}
+		return
+	}
+
+	// from the beginning, only the retained events
+	ids, data := read("local/time/tick", "0", 3)
+	require.Equal(t, []string{"5", "6", "7"}, data)
+
+	// resume after the second
+	_, data = read("local/time/tick", ids[1], 1)
+	require.Equal(t, []string{"7"}, data)
+
+	// both topics under local/, in order
+	_, data = read("local/", ids[1], 3)
+	require.Equal(t, []string{"106", "7", "107"}, data)
+
+	broker.Stop()
+
+	// a new broker loads the events persisted
+	restarted, err := NewBrokerWithOptions(Options{Retain: 3, Dir: dir})
+	require.NoError(t, err)
+	require.True(t, restarted.seq >= broker.seq)
+
+	entries, _ := restarted.since("/local/time/tick", true, 0)
+	require.Equal(t, 3, len(entries))
+	require.Equal(t, ids[2], fmt.Sprintf("%d", entries[2].ID))
+
+	require.NoError(t, restarted.Publish("local/time/tick", 8))
+	entries, _ = restarted.since("/local/time/tick", true, entries[2].ID)
+	require.Equal(t, 1, len(entries))
+	require.Equal(t, "8", string(entries[0].Data))
+}
@@ -0,0 +1,35 @@
+package cli // import "github.com/docker/infrakit/pkg/cli"
+
+import (
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// DiffLines returns a line by line diff where removed lines are prefixed with - and added lines with +
+func DiffLines(before, after string) string {
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToChars(before, after)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	out := ""
+	for _, d := range diffs {
+		prefix := "  "
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			prefix = "+ "
+		case diffmatchpatch.DiffDelete:
+			prefix = "- "
+		}
+		for _, line := range strings.SplitAfter(d.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			out += prefix + line
+			if !strings.HasSuffix(line, "\n") {
+				out += "\n"
+			}
+		}
+	}
+	return out
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/docker/infrakit/pkg/rpc/client"
 	"github.com/docker/infrakit/pkg/run/local"
 	"github.com/docker/infrakit/pkg/types"
 	"github.com/docker/infrakit/pkg/util/ssh"
@@ -33,6 +35,21 @@ type Remote struct {
 	Endpoints HostList
 	SSH       string // The bastion host
 	User      string
+
+	// TLS is the optional TLS configuration for connecting to the endpoints over https.
+	// The cert / key pair, if set, is presented to the remote for client authentication.
+	TLS *tlsconfig.Options `json:",omitempty" yaml:",omitempty"`
+
+	// Token is the optional bearer token presented to the endpoints for authorization
+	Token string `json:",omitempty" yaml:",omitempty"`
+}
+
+// scheme returns the default scheme of the endpoints
+func (r Remote) scheme() string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
 }
 
 // parse the , delimited string into a url list
@@ -41,7 +58,7 @@ func (r Remote) endpoints() ([]*url.URL, error) {
 	for _, h := range strings.Split(string(r.Endpoints), ",") {
 		addProtocol := false
 		if !strings.Contains(h, "://") {
-			h = "http://" + h
+			h = r.scheme() + "://" + h
 			addProtocol = true
 		}
 		u, err := url.Parse(h)
@@ -49,13 +66,24 @@ func (r Remote) endpoints() ([]*url.URL, error) {
 			return nil, err
 		}
 		if addProtocol {
-			u.Scheme = "http"
+			u.Scheme = r.scheme()
 		}
+		r.registerTLS(u)
 		ulist = append(ulist, u)
 	}
 	return ulist, nil
 }
 
+// registerTLS registers the TLS options and token of this remote with the rpc client for the given endpoint
+func (r Remote) registerTLS(u *url.URL) {
+	if r.TLS != nil {
+		client.RegisterTLS(u.Host, *r.TLS)
+	}
+	if r.Token != "" {
+		client.RegisterToken(u.Host, r.Token)
+	}
+}
+
 // HostList is a comma-delimited list of protocol://host:port
 type HostList string
 
@@ -183,10 +211,12 @@ func (r *site) startTunnels() ([]*url.URL, error) {
 		}
 
 		r.tunnels = append(r.tunnels, tunnel)
-		r.urls = append(r.urls, &url.URL{
-			Scheme: "http",
+		tunneled := &url.URL{
+			Scheme: u.Scheme,
 			Host:   string(tunnel.Local),
-		})
+		}
+		r.rule.registerTLS(tunneled)
+		r.urls = append(r.urls, tunneled)
 	}
 	return r.urls, nil
 }
@@ -14,8 +14,12 @@ func init() {
 		[]cli.CmdBuilder{
 			//Controller,
 			Describe,
+			Plan,
 			Commit,
 			Free,
+			Claim,
+			Release,
+			Leases,
 		})
 }
 
@@ -28,8 +32,12 @@ func Controller(name string, services *cli.Services) *cobra.Command {
 
 	controller.AddCommand(
 		Describe(name, services),
+		Plan(name, services),
 		Commit(name, services),
 		Free(name, services),
+		Claim(name, services),
+		Release(name, services),
+		Leases(name, services),
 	)
 
 	return controller
@@ -0,0 +1,140 @@
+package controller // import "github.com/docker/infrakit/pkg/cli/v0/controller"
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/docker/infrakit/pkg/cli"
+	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// leasing returns the leasing interface of the named controller and the search for the collection,
+// which is the type of the plugin name, as in pool/workers.
+func leasing(name string, services *cli.Services) (controller.Leasing, types.Metadata, error) {
+	search := types.Metadata{
+		Name: plugin.Name(name).Type(),
+	}
+	if search.Name == "" {
+		return nil, search, fmt.Errorf("no collection in %v", name)
+	}
+
+	c, err := services.Scope.Controller(name)
+	if err != nil {
+		return nil, search, err
+	}
+	cli.MustNotNil(c, "controller not found", "name", name)
+
+	leasing, is := c.(controller.Leasing)
+	if !is {
+		return nil, search, controller.ErrLeasingNotSupported
+	}
+	return leasing, search, nil
+}
+
+func printLeases(w io.Writer, leases []controller.Lease) {
+	format := "%-20s  %-20s  %-30s  %-25s\n"
+	fmt.Fprintf(w, format, "ID", "CONSUMER", "INSTANCE", "EXPIRES")
+	for _, l := range leases {
+		fmt.Fprintf(w, format, l.ID, l.Consumer, l.Instance.ID, l.Expires.Format(time.RFC3339))
+	}
+}
+
+// Claim returns the claim command
+func Claim(name string, services *cli.Services) *cobra.Command {
+	claim := &cobra.Command{
+		Use:   "claim",
+		Short: "Claim a ready instance, leased to the consumer until released or the lease expires",
+	}
+	claim.Flags().AddFlagSet(services.OutputFlags)
+
+	consumer := claim.Flags().String("consumer", "", "Name of the consumer")
+	ttl := claim.Flags().Duration("ttl", 0, "Duration of the lease. Uses the TTL of the pool if 0")
+
+	claim.RunE = func(cmd *cobra.Command, args []string) error {
+
+		if len(args) != 0 {
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		c, search, err := leasing(name, services)
+		if err != nil {
+			return err
+		}
+
+		lease, err := c.Claim(search, *consumer, *ttl)
+		if err != nil {
+			return err
+		}
+
+		return services.Output(os.Stdout, lease,
+			func(w io.Writer, v interface{}) error {
+				printLeases(w, []controller.Lease{lease})
+				return nil
+			})
+	}
+	return claim
+}
+
+// Release returns the release command
+func Release(name string, services *cli.Services) *cobra.Command {
+	release := &cobra.Command{
+		Use:   "release <id>",
+		Short: "Release a lease before it expires",
+	}
+
+	release.RunE = func(cmd *cobra.Command, args []string) error {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		c, search, err := leasing(name, services)
+		if err != nil {
+			return err
+		}
+
+		return c.Release(search, args[0])
+	}
+	return release
+}
+
+// Leases returns the leases command
+func Leases(name string, services *cli.Services) *cobra.Command {
+	leases := &cobra.Command{
+		Use:   "leases",
+		Short: "List the current leases",
+	}
+	leases.Flags().AddFlagSet(services.OutputFlags)
+
+	leases.RunE = func(cmd *cobra.Command, args []string) error {
+
+		if len(args) != 0 {
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		c, search, err := leasing(name, services)
+		if err != nil {
+			return err
+		}
+
+		list, err := c.Leases(search)
+		if err != nil {
+			return err
+		}
+
+		return services.Output(os.Stdout, list,
+			func(w io.Writer, v interface{}) error {
+				printLeases(w, list)
+				return nil
+			})
+	}
+	return leases
+}
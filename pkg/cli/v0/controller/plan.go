@@ -0,0 +1,119 @@
+package controller // import "github.com/docker/infrakit/pkg/cli/v0/controller"
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/infrakit/pkg/cli"
+	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// Plan returns the plan command
+func Plan(name string, services *cli.Services) *cobra.Command {
+	plan := &cobra.Command{
+		Use:   "plan <configuration url>",
+		Short: "Show the changes a commit would make, without committing. Read from stdin if url is '-'",
+	}
+	plan.Flags().AddFlagSet(services.ProcessTemplateFlags)
+	plan.Flags().AddFlagSet(services.OutputFlags)
+
+	destroy := plan.Flags().Bool("destroy-all", false, "Plan the destruction of all resources under management.")
+
+	plan.RunE = func(cmd *cobra.Command, args []string) error {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		c, err := services.Scope.Controller(name)
+		if err != nil {
+			return nil
+		}
+		cli.MustNotNil(c, "controller not found", "name", name)
+
+		view, err := services.ReadFromStdinIfElse(
+			func() bool { return args[0] == "-" },
+			func() (string, error) { return services.ProcessTemplate(args[0]) },
+			services.ToJSON,
+		)
+		if err != nil {
+			return err
+		}
+
+		spec := types.Spec{}
+		if err := types.AnyString(view).Decode(&spec); err != nil {
+			return err
+		}
+
+		op := controller.Enforce
+		if *destroy {
+			op = controller.Destroy
+		}
+
+		_, p, err := c.Plan(op, spec)
+		if err != nil {
+			return err
+		}
+
+		return services.Output(os.Stdout, p,
+			func(w io.Writer, v interface{}) error {
+				return renderPlan(w, p)
+			})
+	}
+	return plan
+}
+
+// renderPlan writes the plan as a diff of the specs followed by the changes in order
+func renderPlan(w io.Writer, plan controller.Plan) error {
+	for _, m := range plan.Message {
+		fmt.Fprintln(w, m)
+	}
+
+	before, err := specYAML(plan.Before)
+	if err != nil {
+		return err
+	}
+	after, err := specYAML(plan.After)
+	if err != nil {
+		return err
+	}
+	if before != after {
+		fmt.Fprintln(w)
+		fmt.Fprint(w, cli.DiffLines(before, after))
+	}
+
+	if len(plan.Changes) == 0 {
+		fmt.Fprintln(w, "\nNo changes to resources")
+		return nil
+	}
+
+	fmt.Fprintln(w)
+	format := "%-5v  %-8v  %-30v  %-20v  %v\n"
+	fmt.Fprintf(w, format, "STEP", "CHANGE", "ID", "LOGICAL ID", "DETAILS")
+	for _, c := range plan.Changes {
+		id, logicalID := c.ID, c.LogicalID
+		if id == "" {
+			id = "-"
+		}
+		if logicalID == "" {
+			logicalID = "-"
+		}
+		fmt.Fprintf(w, format, c.Step, c.Type, id, logicalID, c.Message)
+	}
+	return nil
+}
+
+func specYAML(spec *types.Spec) (string, error) {
+	if spec == nil {
+		return "", nil
+	}
+	buff, err := types.AnyValueMust(spec).MarshalYAML()
+	if err != nil {
+		return "", err
+	}
+	return string(buff), nil
+}
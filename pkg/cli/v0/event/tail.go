@@ -1,6 +1,7 @@
 package event // import "github.com/docker/infrakit/pkg/cli/v0/event"
 
 import (
+	"encoding/json"
 	"fmt"
 	"path"
 	"strings"
@@ -22,6 +23,18 @@ func Tail(name string, services *cli.Services) *cobra.Command {
 	globals := []string{}
 	templateURL := "str://{{jsonDecode .Data}}"
 	tail.Flags().StringVar(&templateURL, "view", templateURL, "URL for view template")
+
+	// filters, evaluated by the server
+	eventTypes := []string{}
+	matches := []string{}
+	filter := event.Filter{}
+	tail.Flags().StringSliceVar(&eventTypes, "type", eventTypes, "Types of the events")
+	tail.Flags().StringSliceVar(&matches, "match", matches,
+		"Values of fields in the data of the events, as pointer=value, e.g. /Status=running")
+	tail.Flags().StringVar(&filter.Expression, "expr", "",
+		"Template that renders to true for the events, e.g. '{{ gt .Data.Size 10.0 }}'")
+	tail.Flags().Float64Var(&filter.Rate, "rate", 0, "Maximum number of events per second")
+	tail.Flags().IntVar(&filter.Sample, "sample", 0, "Select one event in every N events")
 	tail.RunE = func(cmd *cobra.Command, args []string) error {
 
 		eventPlugin, err := LoadPlugin(services.Scope.Plugins(), name)
@@ -47,6 +60,25 @@ func Tail(name string, services *cli.Services) *cobra.Command {
 			}
 		}
 
+		for _, t := range eventTypes {
+			filter.Types = append(filter.Types, event.Type(t))
+		}
+		for _, m := range matches {
+			kv := strings.SplitN(m, "=", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("bad match: %s", m)
+			}
+			if filter.Data == nil {
+				filter.Data = map[string]interface{}{}
+			}
+			// the value is json if it can be parsed, e.g. numbers, or a string otherwise
+			var v interface{}
+			if err := json.Unmarshal([]byte(kv[1]), &v); err != nil {
+				v = kv[1]
+			}
+			filter.Data[kv[0]] = v
+		}
+
 		if len(args) == 0 {
 			args = []string{"."}
 		}
@@ -82,9 +114,17 @@ func Tail(name string, services *cli.Services) *cobra.Command {
 				return fmt.Errorf("not a subscriber: %s, %v", target, eventPlugin)
 			}
 
-			log.Debug("Subscribing", "topic", eventTopic)
+			log.Debug("Subscribing", "topic", eventTopic, "filter", filter)
 
-			stream, stop, err := client.SubscribeOn(eventTopic)
+			var stream <-chan *event.Event
+			var stop chan<- struct{}
+			if filtered, is := eventPlugin.(event.FilteredSubscriber); is && !filter.Empty() {
+				stream, stop, err = filtered.SubscribeWithFilter(eventTopic, filter)
+			} else if !filter.Empty() {
+				return fmt.Errorf("filters not supported: %s, %v", target, eventPlugin)
+			} else {
+				stream, stop, err = client.SubscribeOn(eventTopic)
+			}
 			if err != nil {
 				return fmt.Errorf("cannot subscribe: %s, err=%v", topic, err)
 			}
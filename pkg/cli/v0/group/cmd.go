@@ -20,6 +20,8 @@ func init() {
 			Free,
 			Destroy,
 			Scale,
+			Promote,
+			Abort,
 
 			// Unusual - for showing list of groups / aggregate
 			Groups,
@@ -42,6 +44,8 @@ func Group(name string, services *cli.Services) *cobra.Command {
 		Free(name, services),
 		Destroy(name, services),
 		Scale(name, services),
+		Promote(name, services),
+		Abort(name, services),
 
 		// Unusual - for showing groups in the aggregate
 		Groups(name, services),
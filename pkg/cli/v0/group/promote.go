@@ -0,0 +1,87 @@
+package group // import "github.com/docker/infrakit/pkg/cli/v0/group"
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/infrakit/pkg/cli"
+	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/spi/group"
+	"github.com/spf13/cobra"
+)
+
+// Promote returns the Promote command
+func Promote(name string, services *cli.Services) *cobra.Command {
+
+	promote := &cobra.Command{
+		Use:   "promote <group ID>",
+		Short: "Promote an update paused after its canary phase and update the rest of the group",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			pluginName := plugin.Name(name)
+			_, gid := pluginName.GetLookupAndType()
+			if gid == "" {
+				if len(args) < 1 {
+					cmd.Usage()
+					os.Exit(1)
+				} else {
+					gid = args[0]
+				}
+			}
+
+			groupPlugin, err := services.Scope.Group(name)
+			if err != nil {
+				return nil
+			}
+			cli.MustNotNil(groupPlugin, "group plugin not found", "name", name)
+
+			groupID := group.ID(gid)
+			err = groupPlugin.PromoteUpdate(groupID)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println("Promoted", groupID)
+			return nil
+		},
+	}
+	return promote
+}
+
+// Abort returns the Abort command
+func Abort(name string, services *cli.Services) *cobra.Command {
+
+	abort := &cobra.Command{
+		Use:   "abort <group ID>",
+		Short: "Abort an update paused after its canary phase and roll back the canary instances",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			pluginName := plugin.Name(name)
+			_, gid := pluginName.GetLookupAndType()
+			if gid == "" {
+				if len(args) < 1 {
+					cmd.Usage()
+					os.Exit(1)
+				} else {
+					gid = args[0]
+				}
+			}
+
+			groupPlugin, err := services.Scope.Group(name)
+			if err != nil {
+				return nil
+			}
+			cli.MustNotNil(groupPlugin, "group plugin not found", "name", name)
+
+			groupID := group.ID(gid)
+			err = groupPlugin.AbortUpdate(groupID)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println("Aborted", groupID)
+			return nil
+		},
+	}
+	return abort
+}
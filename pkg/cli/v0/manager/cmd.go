@@ -18,6 +18,10 @@ func init() {
 			Inspect,
 			Specs,
 			Terminate,
+			History,
+			Diff,
+			Rollback,
+			StepDown,
 		})
 }
 
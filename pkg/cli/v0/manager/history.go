@@ -0,0 +1,163 @@
+package manager // import "github.com/docker/infrakit/pkg/cli/v0/manager"
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/docker/infrakit/pkg/cli"
+	"github.com/docker/infrakit/pkg/spi/stack"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// History returns the history command
+func History(name string, services *cli.Services) *cobra.Command {
+	return HistoryCommand(services, func() (stack.Interface, error) { return services.Scope.Stack(name) })
+}
+
+// Diff returns the diff command
+func Diff(name string, services *cli.Services) *cobra.Command {
+	return DiffCommand(services, func() (stack.Interface, error) { return services.Scope.Stack(name) })
+}
+
+// Rollback returns the rollback command
+func Rollback(name string, services *cli.Services) *cobra.Command {
+	return RollbackCommand(services, func() (stack.Interface, error) { return services.Scope.Stack(name) })
+}
+
+// HistoryCommand returns the history command for the stack returned by the lookup
+func HistoryCommand(services *cli.Services, lookup func() (stack.Interface, error)) *cobra.Command {
+	history := &cobra.Command{
+		Use:   "history",
+		Short: "History lists the revisions of the specs for the entire stack",
+	}
+
+	history.Flags().AddFlagSet(services.OutputFlags)
+	history.RunE = func(cmd *cobra.Command, args []string) error {
+
+		if len(args) != 0 {
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		stack, err := lookup()
+		if err != nil {
+			return err
+		}
+		cli.MustNotNil(stack, "stack plugin not found")
+
+		revisions, err := stack.History()
+		if err != nil {
+			return err
+		}
+
+		return services.Output(os.Stdout, revisions,
+			func(w io.Writer, v interface{}) error {
+				format := "%-8v  %-25v  %-20v  %v\n"
+				fmt.Fprintf(w, format, "REVISION", "TIMESTAMP", "AUTHOR", "FINGERPRINT")
+				for _, r := range revisions {
+					fmt.Fprintf(w, format, r.Revision, r.Timestamp.Format(time.RFC3339),
+						r.Author, r.Fingerprint)
+				}
+				return nil
+			})
+	}
+	return history
+}
+
+// DiffCommand returns the diff command for the stack returned by the lookup
+func DiffCommand(services *cli.Services, lookup func() (stack.Interface, error)) *cobra.Command {
+	diff := &cobra.Command{
+		Use:   "diff <revision> <revision>",
+		Short: "Diff shows the changes to the specs between two revisions",
+	}
+
+	diff.RunE = func(cmd *cobra.Command, args []string) error {
+
+		if len(args) != 2 {
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		revs, err := parseRevisions(args)
+		if err != nil {
+			return err
+		}
+
+		stack, err := lookup()
+		if err != nil {
+			return err
+		}
+		cli.MustNotNil(stack, "stack plugin not found")
+
+		specs := []string{}
+		for _, rev := range revs {
+			revision, err := stack.Revision(rev)
+			if err != nil {
+				return err
+			}
+			buff, err := types.AnyValueMust(revision.Specs).MarshalYAML()
+			if err != nil {
+				return err
+			}
+			specs = append(specs, string(buff))
+		}
+
+		if specs[0] == specs[1] {
+			fmt.Println("No changes")
+			return nil
+		}
+		fmt.Print(cli.DiffLines(specs[0], specs[1]))
+		return nil
+	}
+	return diff
+}
+
+// RollbackCommand returns the rollback command for the stack returned by the lookup
+func RollbackCommand(services *cli.Services, lookup func() (stack.Interface, error)) *cobra.Command {
+	rollback := &cobra.Command{
+		Use:   "rollback <revision>",
+		Short: "Rollback enforces the specs of an earlier revision",
+	}
+
+	rollback.RunE = func(cmd *cobra.Command, args []string) error {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		revs, err := parseRevisions(args)
+		if err != nil {
+			return err
+		}
+
+		stack, err := lookup()
+		if err != nil {
+			return err
+		}
+		cli.MustNotNil(stack, "stack plugin not found")
+
+		if err := stack.Rollback(revs[0]); err != nil {
+			return err
+		}
+		fmt.Println("Rolled back to revision", revs[0])
+		return nil
+	}
+	return rollback
+}
+
+func parseRevisions(args []string) ([]int, error) {
+	revs := []int{}
+	for _, arg := range args {
+		rev, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("bad revision %v", arg)
+		}
+		revs = append(revs, rev)
+	}
+	return revs, nil
+}
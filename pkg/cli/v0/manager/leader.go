@@ -0,0 +1,98 @@
+package manager // import "github.com/docker/infrakit/pkg/cli/v0/manager"
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/docker/infrakit/pkg/cli"
+	"github.com/docker/infrakit/pkg/spi/stack"
+	"github.com/spf13/cobra"
+)
+
+// StepDown returns the step-down command
+func StepDown(name string, services *cli.Services) *cobra.Command {
+	return StepDownCommand(services, func() (stack.Interface, error) { return services.Scope.Stack(name) })
+}
+
+// StepDownCommand returns the step-down command for the stack returned by the lookup
+func StepDownCommand(services *cli.Services, lookup func() (stack.Interface, error)) *cobra.Command {
+	stepDown := &cobra.Command{
+		Use:   "step-down",
+		Short: "Step-down makes the leader stop its controllers and hand the leadership to another manager",
+		Long: `Step-down makes the leader stop its controllers and hand the leadership to another manager.
+
+How the leadership is handed over depends on the backend of the manager:
+  file   With a leader file, the successor is required and its ID is written to the file.  With a lease,
+         the lease is released and only the successor, if given, can take it until the lease expires.
+  consul The lock is released and the other managers race for it.  A successor cannot be nominated.
+  etcd   Stepping down is not supported.
+  swarm  The node of the leader is demoted to a worker so that the other managers elect a new leader.
+         A successor cannot be nominated.  The node has to be promoted again with docker node promote to
+         rejoin the managers.  This is disabled unless the manager runs with DemoteOnStepDown in the swarm
+         backend options or INFRAKIT_MANAGER_SWARM_DEMOTE_ON_STEP_DOWN=true.`,
+	}
+
+	successor := stepDown.Flags().String("successor", "", "ID of the manager to hand the leadership to, if supported")
+	wait := stepDown.Flags().Duration("wait", 1*time.Minute, "How long to wait for the new leader. 0 to not wait")
+	poll := stepDown.Flags().Duration("poll", 1*time.Second, "Interval to check the location of the leader")
+
+	stepDown.RunE = func(cmd *cobra.Command, args []string) error {
+
+		if len(args) != 0 {
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		stack, err := lookup()
+		if err != nil {
+			return err
+		}
+		cli.MustNotNil(stack, "stack plugin not found")
+
+		before, err := stack.LeaderLocation()
+		if err != nil {
+			log.Warn("Cannot get location of leader", "err", err)
+		}
+
+		if err := stack.StepDown(*successor); err != nil {
+			return err
+		}
+
+		if *wait == 0 {
+			return nil
+		}
+
+		after, err := waitForLeader(stack, before, *wait, *poll)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("LeaderLocation : %v\n", after)
+		return nil
+	}
+	return stepDown
+}
+
+// waitForLeader polls the location of the leader until it's different from the location before
+func waitForLeader(leadership stack.Leadership, before *url.URL, wait, poll time.Duration) (*url.URL, error) {
+	deadline := time.After(wait)
+	tick := time.NewTicker(poll)
+	defer tick.Stop()
+
+	for {
+		location, err := leadership.LeaderLocation()
+		if err != nil {
+			log.Warn("Cannot get location of leader", "err", err)
+		}
+		if location != nil && (before == nil || location.String() != before.String()) {
+			return location, nil
+		}
+
+		select {
+		case <-deadline:
+			return nil, fmt.Errorf("no new leader after %v", wait)
+		case <-tick.C:
+		}
+	}
+}
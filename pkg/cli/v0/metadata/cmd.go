@@ -17,12 +17,14 @@ func init() {
 		[]cli.CmdBuilder{
 			Keys,
 			Cat,
+			Watch,
 		})
 	cli.Register(metadata.UpdatableInterfaceSpec,
 		[]cli.CmdBuilder{
 			Keys,
 			Cat,
 			Change,
+			Watch,
 		})
 }
 
@@ -57,6 +59,7 @@ func Metadata(name string, services *cli.Services) *cobra.Command {
 	cmd.AddCommand(
 		Keys(name, services),
 		Cat(name, services),
+		Watch(name, services),
 	)
 
 	return cmd
@@ -74,6 +77,7 @@ func Updatable(name string, services *cli.Services) *cobra.Command {
 		Keys(name, services),
 		Cat(name, services),
 		Change(name, services),
+		Watch(name, services),
 	)
 
 	return cmd
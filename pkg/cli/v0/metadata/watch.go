@@ -0,0 +1,64 @@
+package metadata // import "github.com/docker/infrakit/pkg/cli/v0/metadata"
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/infrakit/pkg/cli"
+	metadata_plugin "github.com/docker/infrakit/pkg/plugin/metadata"
+	"github.com/docker/infrakit/pkg/spi/metadata"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// Watch is the watch command
+func Watch(name string, services *cli.Services) *cobra.Command {
+
+	watch := &cobra.Command{
+		Use:   "watch <path>",
+		Short: "Watch the changes of the metadata entry at path",
+	}
+	watch.Flags().AddFlagSet(services.OutputFlags)
+
+	count := watch.Flags().Int("count", 0, "Exit after the number of changes. Watch forever if 0")
+
+	watch.RunE = func(cmd *cobra.Command, args []string) error {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		metadataPlugin, err := loadPlugin(services.Scope.Plugins(), name)
+		if err != nil {
+			return err
+		}
+		cli.MustNotNil(metadataPlugin, "metadata plugin not found", "name", name)
+
+		path := types.PathFromString(args[0])
+		updates, done, err := metadata_plugin.Watch(metadataPlugin, path, metadata_plugin.DefaultPollInterval)
+		if err != nil {
+			return err
+		}
+		defer close(done)
+
+		for i := 0; *count == 0 || i < *count; i++ {
+			update, ok := <-updates
+			if !ok {
+				return fmt.Errorf("watch of %v ended", path)
+			}
+			err := services.Output(os.Stdout, update,
+				func(w io.Writer, v interface{}) error {
+					u := v.(metadata.Update)
+					fmt.Fprintf(w, "%v: %s -> %s\n", u.Path, u.Old.Bytes(), u.New.Bytes())
+					return nil
+				})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return watch
+}
@@ -33,6 +33,10 @@ func (f fakeLeaderT) LeaderLocation() (*url.URL, error) {
 	return nil, nil
 }
 
+func (f fakeLeaderT) StepDown(successor string) error {
+	return nil
+}
+
 type fakePlugins map[string]*plugin.Endpoint
 
 func (f fakePlugins) Find(name plugin.Name) (*plugin.Endpoint, error) {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	enrollment "github.com/docker/infrakit/pkg/controller/enrollment/types"
+	"github.com/docker/infrakit/pkg/metrics"
 	"github.com/docker/infrakit/pkg/plugin"
 	"github.com/docker/infrakit/pkg/spi/group"
 	"github.com/docker/infrakit/pkg/spi/instance"
@@ -164,6 +165,8 @@ func (l *enroller) sync() error {
 	)
 
 	log.Debug("Computed delta", "add", add, "remove", remove, "debug", debugV)
+	metrics.EnrollmentDelta.WithLabelValues(l.spec.Metadata.Name, "add").Set(float64(len(add)))
+	metrics.EnrollmentDelta.WithLabelValues(l.spec.Metadata.Name, "remove").Set(float64(len(remove)))
 	// Use Info logging only when making deltas, log the ID:LogicalID for each delta
 	if len(add) > 0 || len(remove) > 0 {
 		addIDs := []string{}
@@ -196,6 +199,7 @@ func (l *enroller) sync() error {
 		props, err := l.buildProperties(n)
 		if err != nil {
 			log.Error("Cannot bulid properties to enroll", "err", err, "description", n)
+			metrics.EnrollmentErrors.WithLabelValues(l.spec.Metadata.Name, "add").Inc()
 			continue
 		}
 		spec := instance.Spec{
@@ -205,6 +209,7 @@ func (l *enroller) sync() error {
 		_, err = instancePlugin.Provision(spec)
 		if err != nil {
 			log.Error("Failed to create enrollment", "err", err, "spec", spec)
+			metrics.EnrollmentErrors.WithLabelValues(l.spec.Metadata.Name, "add").Inc()
 		}
 	}
 
@@ -212,6 +217,7 @@ func (l *enroller) sync() error {
 		err = instancePlugin.Destroy(n.ID, instance.Termination)
 		if err != nil {
 			log.Error("Failed to remove enrollment", "err", err, "id", n.ID)
+			metrics.EnrollmentErrors.WithLabelValues(l.spec.Metadata.Name, "remove").Inc()
 			continue // get them next time...
 		}
 	}
@@ -19,6 +19,7 @@ var (
 	// DefaultOptions return an Options with default values filled in.
 	DefaultOptions = gc.Options{
 		PluginRetryInterval: types.Duration(1 * time.Second),
+		StateSaveInterval:   types.Duration(1 * time.Minute),
 	}
 )
 
@@ -2,6 +2,7 @@ package gc // import "github.com/docker/infrakit/pkg/controller/gc"
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	gc "github.com/docker/infrakit/pkg/controller/gc/types"
@@ -10,19 +11,41 @@ import (
 )
 
 var (
-	models = map[string]func(gc.Properties) (Model, error){}
+	models = map[string]func(*gc.Properties) (Model, error){}
 	lock   sync.RWMutex
 )
 
 // Register registers an available model
-func Register(key string, builder func(gc.Properties) (Model, error)) {
+func Register(key string, builder func(*gc.Properties) (Model, error)) {
 	lock.Lock()
 	defer lock.Unlock()
 
 	models[key] = builder
 }
 
-func model(properties gc.Properties) (Model, error) {
+// Models returns the names of the registered models
+func Models() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	names := []string{}
+	for name := range models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ModelSpec returns the spec of the state machines of the registered model, built with the default properties
+func ModelSpec(name string) (*fsm.Spec, error) {
+	m, err := model(&gc.Properties{Model: name})
+	if err != nil {
+		return nil, err
+	}
+	return m.Spec(), nil
+}
+
+func model(properties *gc.Properties) (Model, error) {
 	lock.RLock()
 	defer lock.RUnlock()
 
@@ -42,6 +65,7 @@ type Model interface {
 	Start()
 	Stop()
 	Spec() *fsm.Spec
+	Set() *fsm.Set
 	New() fsm.FSM
 	FoundNode(fsm.FSM, instance.Description) error
 	LostNode(fsm.FSM)
@@ -50,3 +74,11 @@ type Model interface {
 	GCNode() <-chan fsm.FSM
 	GCInstance() <-chan fsm.FSM
 }
+
+// PartialItems is implemented by the models that keep acting on an item after one of its sides is lost
+// while the other side is still observed, e.g. to destroy the instance after its node is removed.  The
+// items of the other models are removed as soon as either side is lost.
+type PartialItems interface {
+	// KeepPartialItems returns true to keep the items that lost one side
+	KeepPartialItems() bool
+}
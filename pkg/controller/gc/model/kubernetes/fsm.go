@@ -0,0 +1,389 @@
+package kubernetes // import "github.com/docker/infrakit/pkg/controller/gc/model/kubernetes"
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/infrakit/pkg/controller/gc"
+	gc_types "github.com/docker/infrakit/pkg/controller/gc/types"
+	"github.com/docker/infrakit/pkg/fsm"
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/metrics"
+	"github.com/docker/infrakit/pkg/spi/instance"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+var (
+	log    = logutil.New("module", "controller/gc/kubernetes")
+	debugV = logutil.V(300)
+)
+
+func init() {
+	gc.Register("kubernetes", BuildModel)
+}
+
+const (
+	nodeReady fsm.Signal = iota
+	nodeNotReady
+	nodeGone
+	instanceOK
+	instanceGone
+	timeout
+	reap
+	drain
+)
+
+// Node is the subset of the Kubernetes Node object that the model looks at.  It is decoded from
+// the Properties of the descriptions of the node observer.
+type Node struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+
+	Status struct {
+		Conditions []NodeCondition `json:"conditions"`
+	} `json:"status"`
+}
+
+// NodeCondition is a condition of the node, e.g. Ready
+type NodeCondition struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// Ready returns true if the node reports the Ready condition as True
+func (n Node) Ready() bool {
+	for _, c := range n.Status.Conditions {
+		if c.Type == "Ready" {
+			return c.Status == "True"
+		}
+	}
+	return false
+}
+
+// NodeFromDescription returns a kubernetes node that is assumed to be attached as a Properties
+func NodeFromDescription(desc instance.Description) (Node, error) {
+	node := Node{}
+	if desc.Properties == nil {
+		return node, fmt.Errorf("no kubernetes node information %v", desc)
+	}
+	return node, desc.Properties.Decode(&node)
+}
+
+const (
+	start                  fsm.Index = iota
+	matchedInstance                  // has vm information, waiting to match to node
+	matchedNode                      // has node information, waiting to match to vm
+	kubeNode                         // has matching node and vm information
+	kubeNodeReady                    // node is Ready
+	kubeNodeNotReady                 // node is NotReady
+	removedNode                      // node is drained and deleted, vm to be destroyed
+	pendingInstanceDestroy           // vm needs to be removedInstance (instance destroy)
+	removedInstance                  // instance is deleted
+	done                             // terminal
+)
+
+type modelProperties struct {
+	TickUnit                  types.Duration
+	NoData                    fsm.Tick
+	NodeJoin                  fsm.Tick
+	WaitDescribeInstances     fsm.Tick
+	NotReadyTTL               fsm.Tick // how long a node can be NotReady before it's drained and replaced
+	WaitBeforeInstanceDestroy fsm.Tick
+	WaitBeforeCleanup         fsm.Tick
+	RmNodeBufferSize          int
+	RmInstanceBufferSize      int
+}
+
+var defaultModelProperties = modelProperties{
+	TickUnit:                  types.FromDuration(1 * time.Second),
+	NoData:                    fsm.Tick(10),
+	NodeJoin:                  fsm.Tick(10),
+	WaitDescribeInstances:     fsm.Tick(5),
+	NotReadyTTL:               fsm.Tick(60),
+	WaitBeforeInstanceDestroy: fsm.Tick(3),
+	WaitBeforeCleanup:         fsm.Tick(10),
+	RmNodeBufferSize:          10,
+	RmInstanceBufferSize:      10,
+}
+
+type model struct {
+	spec     *fsm.Spec
+	set      *fsm.Set
+	clock    *fsm.Clock
+	tickSize time.Duration
+
+	*gc_types.Properties
+	modelProperties
+
+	nodeRmChan          chan fsm.FSM
+	instanceDestroyChan chan fsm.FSM
+
+	lock sync.RWMutex
+}
+
+func (m *model) GCNode() <-chan fsm.FSM {
+	return m.nodeRmChan
+}
+
+func (m *model) GCInstance() <-chan fsm.FSM {
+	return m.instanceDestroyChan
+}
+
+func (m *model) New() fsm.FSM {
+	return m.set.Add(start)
+}
+
+func (m *model) FoundNode(fsm fsm.FSM, desc instance.Description) error {
+	node, err := NodeFromDescription(desc)
+	if err != nil {
+		return err
+	}
+
+	if node.Ready() {
+		fsm.Signal(nodeReady)
+		return nil
+	}
+
+	log.Debug("kubernetes node not ready", "node", node.Metadata.Name, "V", debugV)
+	fsm.Signal(nodeNotReady)
+	return nil
+}
+
+func (m *model) LostNode(fsm fsm.FSM) {
+	fsm.Signal(nodeGone)
+}
+
+func (m *model) FoundInstance(fsm fsm.FSM, desc instance.Description) error {
+	fsm.Signal(instanceOK)
+	return nil
+}
+
+func (m *model) LostInstance(fsm fsm.FSM) {
+	fsm.Signal(instanceGone)
+}
+
+// KeepPartialItems returns true since the instance is destroyed after its node is removed
+func (m *model) KeepPartialItems() bool {
+	return true
+}
+
+func (m *model) Set() *fsm.Set {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.set
+}
+
+func (m *model) Spec() *fsm.Spec {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.spec
+}
+
+func (m *model) Start() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.clock.Start()
+	m.set = fsm.NewSet(m.spec, m.clock, fsm.DefaultOptions("kubernetes"))
+	metrics.RegisterSet("gc", m.set)
+}
+
+func (m *model) Stop() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	metrics.UnregisterSet(m.set)
+	m.set.Stop()
+	m.clock.Stop()
+
+	close(m.nodeRmChan)
+	close(m.instanceDestroyChan)
+}
+
+func (m *model) instanceDestroy(i fsm.FSM) error {
+	if m.instanceDestroyChan == nil {
+		return fmt.Errorf("not initialized")
+	}
+
+	m.instanceDestroyChan <- i
+	return nil
+}
+
+func (m *model) nodeRm(i fsm.FSM) error {
+	if m.nodeRmChan == nil {
+		return fmt.Errorf("not initialized")
+	}
+
+	m.nodeRmChan <- i
+	return nil
+}
+
+func longer(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// BuildModel constructs a workflow model given the configuration blob provided by user in the Properties.
+// The node observer is expected to describe the Nodes with the Node objects as Properties, as done by the
+// instance plugin of the kubernetes flavor.  A node that stays NotReady longer than NotReadyTTL is cordoned,
+// drained and deleted by the node observer, and then the backing instance is destroyed.
+func BuildModel(properties *gc_types.Properties) (gc.Model, error) {
+
+	modelProperties := defaultModelProperties
+	if properties.ModelProperties != nil {
+		if err := properties.ModelProperties.Decode(&modelProperties); err != nil {
+			return nil, err
+		}
+	}
+
+	model := &model{
+		Properties:          properties,
+		modelProperties:     modelProperties,
+		nodeRmChan:          make(chan fsm.FSM, modelProperties.RmNodeBufferSize),
+		instanceDestroyChan: make(chan fsm.FSM, modelProperties.RmInstanceBufferSize),
+	}
+
+	d := longer(model.modelProperties.TickUnit.Duration(), longer(
+		properties.NodeObserver.ObserveInterval.Duration(),
+		properties.InstanceObserver.ObserveInterval.Duration(),
+	))
+
+	model.tickSize = d
+	model.clock = fsm.Wall(time.Tick(d))
+
+	spec, err := fsm.Define(
+		fsm.State{
+			Index: start,
+			TTL:   fsm.Expiry{TTL: modelProperties.NoData, Raise: timeout},
+			Transitions: map[fsm.Signal]fsm.Index{
+				nodeReady:    matchedNode,
+				nodeNotReady: matchedNode,
+				instanceOK:   matchedInstance,
+				timeout:      removedInstance, // nothing happened... cleanup
+			},
+		},
+		fsm.State{
+			Index: matchedInstance,
+			TTL:   fsm.Expiry{TTL: modelProperties.NodeJoin, Raise: nodeGone},
+			Transitions: map[fsm.Signal]fsm.Index{
+				nodeReady:    kubeNode,
+				nodeNotReady: kubeNode,
+				nodeGone:     pendingInstanceDestroy,
+				instanceGone: removedInstance,
+			},
+		},
+		fsm.State{
+			Index: pendingInstanceDestroy,
+			TTL:   fsm.Expiry{TTL: modelProperties.WaitBeforeInstanceDestroy, Raise: reap},
+			Transitions: map[fsm.Signal]fsm.Index{
+				nodeReady:    kubeNode, // late joiner
+				instanceGone: removedInstance,
+				reap:         removedInstance,
+			},
+			Actions: map[fsm.Signal]fsm.Action{
+				reap: model.instanceDestroy,
+			},
+		},
+		fsm.State{
+			Index: matchedNode,
+			TTL:   fsm.Expiry{TTL: modelProperties.WaitDescribeInstances, Raise: instanceGone},
+			Transitions: map[fsm.Signal]fsm.Index{
+				instanceOK:   kubeNode,
+				instanceGone: removedInstance,
+				nodeGone:     removedInstance, // could be kubectl delete'd out of band
+			},
+			Actions: map[fsm.Signal]fsm.Action{
+				instanceGone: model.nodeRm,
+			},
+		},
+		fsm.State{
+			Index: kubeNode,
+			Transitions: map[fsm.Signal]fsm.Index{
+				nodeReady:    kubeNodeReady,
+				nodeNotReady: kubeNodeNotReady,
+				nodeGone:     matchedInstance,
+				instanceGone: matchedNode,
+			},
+		},
+		fsm.State{
+			Index: kubeNodeReady,
+			Transitions: map[fsm.Signal]fsm.Index{
+				nodeNotReady: kubeNodeNotReady,
+				nodeGone:     matchedInstance,
+				instanceGone: matchedNode,
+			},
+		},
+		fsm.State{
+			Index: kubeNodeNotReady,
+			TTL:   fsm.Expiry{TTL: modelProperties.NotReadyTTL, Raise: drain},
+			Transitions: map[fsm.Signal]fsm.Index{
+				nodeReady:    kubeNodeReady,
+				nodeGone:     pendingInstanceDestroy,
+				instanceGone: matchedNode,
+				drain:        removedNode,
+			},
+			Actions: map[fsm.Signal]fsm.Action{
+				drain: model.nodeRm, // cordon, drain and delete the node
+			},
+		},
+		fsm.State{
+			Index: removedNode, // the node is going away, no late joiners
+			TTL:   fsm.Expiry{TTL: modelProperties.WaitBeforeInstanceDestroy, Raise: reap},
+			Transitions: map[fsm.Signal]fsm.Index{
+				instanceGone: removedInstance,
+				reap:         removedInstance,
+			},
+			Actions: map[fsm.Signal]fsm.Action{
+				reap: model.instanceDestroy,
+			},
+		},
+		fsm.State{
+			Index: removedInstance, // after we removed the instance, we can still have unmatched node
+			TTL:   fsm.Expiry{TTL: modelProperties.WaitBeforeCleanup, Raise: timeout},
+			Transitions: map[fsm.Signal]fsm.Index{
+				nodeNotReady: done,
+				timeout:      done,
+			},
+			Actions: map[fsm.Signal]fsm.Action{
+				nodeNotReady: model.nodeRm,
+			},
+		},
+		fsm.State{
+			Index: done, // deleted state is terminal. this will be garbage collected
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	spec.SetStateNames(map[fsm.Index]string{
+		start:                  "START",
+		matchedInstance:        "FOUND_INSTANCE",
+		matchedNode:            "FOUND_NODE",
+		kubeNode:               "KUBE_NODE",
+		kubeNodeReady:          "KUBE_NODE_READY",
+		kubeNodeNotReady:       "KUBE_NODE_NOT_READY",
+		removedNode:            "NODE_REMOVED",
+		pendingInstanceDestroy: "PENDING_INSTANCE_DESTROY",
+		removedInstance:        "INSTANCE_REMOVED",
+		done:                   "DONE",
+	}).SetSignalNames(map[fsm.Signal]string{
+		nodeReady:    "node_ready",
+		nodeNotReady: "node_not_ready",
+		nodeGone:     "node_gone",
+		instanceOK:   "instance_ok",
+		instanceGone: "instance_gone",
+		timeout:      "timeout",
+		reap:         "reap",
+		drain:        "drain",
+	})
+	model.spec = spec
+	return model, nil
+}
@@ -0,0 +1,171 @@
+package kubernetes // import "github.com/docker/infrakit/pkg/controller/gc/model/kubernetes"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/infrakit/pkg/controller/gc"
+	gc_types "github.com/docker/infrakit/pkg/controller/gc/types"
+	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/docker/infrakit/pkg/spi/instance"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func buildModel(t *testing.T, config string) *model {
+	gcProperties := new(gc_types.Properties)
+	require.NoError(t, types.Decode([]byte(config), gcProperties))
+
+	m, err := BuildModel(gcProperties)
+	require.NoError(t, err)
+	return m.(*model)
+}
+
+// nodeDescription returns the description of a node as observed by the node instance plugin
+func nodeDescription(status string) instance.Description {
+	return instance.Description{
+		ID: instance.ID("node1"),
+		Properties: types.AnyYAMLMust([]byte(`
+metadata:
+  name: node1
+  labels:
+    kubernetes.io/hostname: node1
+spec:
+  externalID: i-1
+status:
+  conditions:
+  - type: OutOfDisk
+    status: "False"
+  - type: Ready
+    status: "` + status + `"
+`)),
+	}
+}
+
+// waitFor waits for the signal raised at a deadline to be processed
+func waitFor(t *testing.T, f fsm.FSM, state fsm.Index) {
+	for i := 0; i < 100 && f.State() != state; i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+	require.Equal(t, state, f.State())
+}
+
+func next(t *testing.T, ch <-chan fsm.FSM) fsm.FSM {
+	select {
+	case f := <-ch:
+		return f
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timed out")
+	}
+	return nil
+}
+
+func TestKubernetesModel(t *testing.T) {
+
+	model := buildModel(t, `
+Model : kubernetes
+ModelProperties:
+  TickUnit: 1s
+  NotReadyTTL: 20
+NodeObserver:
+  ObserveInterval: 10s
+InstanceObserver:
+  ObserveInterval: 5s
+`)
+
+	require.Equal(t, fsm.Tick(20), model.NotReadyTTL)
+
+	// the items are kept after the node is removed so the instance is destroyed
+	var m gc.Model = model
+	partial, is := m.(gc.PartialItems)
+	require.True(t, is)
+	require.True(t, partial.KeepPartialItems())
+	require.Equal(t, fsm.Tick(10), model.NoData)
+	require.Equal(t, 10*time.Second, model.tickSize) // must take the slower of the two durations of 10s vs 1s
+
+	model.Start()
+	<-time.After(1 * time.Second)
+	model.Stop()
+
+	// expect channels to be closed
+	{
+		_, ok := <-model.GCNode()
+		require.False(t, ok)
+	}
+	{
+		_, ok := <-model.GCInstance()
+		require.False(t, ok)
+	}
+}
+
+func TestKubernetesModelNotReady(t *testing.T) {
+
+	model := buildModel(t, `
+Model : kubernetes
+ModelProperties:
+  NotReadyTTL: 5
+  WaitBeforeInstanceDestroy: 2
+`)
+	clock := fsm.NewClock()
+	model.clock = clock
+
+	model.Start()
+	defer model.Stop()
+
+	node := model.New()
+	require.NoError(t, model.FoundInstance(node, instance.Description{ID: "i-1"}))
+	require.NoError(t, model.FoundNode(node, nodeDescription("True")))
+	require.Equal(t, kubeNode, node.State())
+
+	require.NoError(t, model.FoundNode(node, nodeDescription("True")))
+	require.Equal(t, kubeNodeReady, node.State())
+
+	// flaps back to ready before the ttl
+	require.NoError(t, model.FoundNode(node, nodeDescription("Unknown")))
+	require.Equal(t, kubeNodeNotReady, node.State())
+	clock.Ticks(3)
+	require.NoError(t, model.FoundNode(node, nodeDescription("True")))
+	require.Equal(t, kubeNodeReady, node.State())
+
+	// stays not ready past the ttl
+	require.NoError(t, model.FoundNode(node, nodeDescription("False")))
+	clock.Ticks(5)
+
+	gone := next(t, model.GCNode())
+	require.Equal(t, node.ID(), gone.ID())
+	waitFor(t, node, removedNode)
+
+	// the deleted node is no longer observed
+	model.LostNode(node)
+
+	clock.Ticks(2)
+	gone = next(t, model.GCInstance())
+	require.Equal(t, node.ID(), gone.ID())
+	waitFor(t, node, removedInstance)
+}
+
+func TestKubernetesModelNodeFailsToJoin(t *testing.T) {
+
+	model := buildModel(t, `
+Model : kubernetes
+ModelProperties:
+  NodeJoin: 3
+  WaitBeforeInstanceDestroy: 2
+`)
+	clock := fsm.NewClock()
+	model.clock = clock
+
+	model.Start()
+	defer model.Stop()
+
+	node := model.New()
+	require.NoError(t, model.FoundInstance(node, instance.Description{ID: "i-1"}))
+	require.Equal(t, matchedInstance, node.State())
+
+	clock.Ticks(3)
+	waitFor(t, node, pendingInstanceDestroy)
+
+	clock.Ticks(2)
+	gone := next(t, model.GCInstance())
+	require.Equal(t, node.ID(), gone.ID())
+}
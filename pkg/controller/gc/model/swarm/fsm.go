@@ -10,6 +10,7 @@ import (
 	gc_types "github.com/docker/infrakit/pkg/controller/gc/types"
 	"github.com/docker/infrakit/pkg/fsm"
 	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/metrics"
 	"github.com/docker/infrakit/pkg/spi/instance"
 	"github.com/docker/infrakit/pkg/types"
 )
@@ -84,7 +85,7 @@ type model struct {
 	clock    *fsm.Clock
 	tickSize time.Duration
 
-	gc_types.Properties
+	*gc_types.Properties
 	modelProperties
 
 	dockerNodeRmChan    chan fsm.FSM
@@ -152,6 +153,13 @@ func (m *model) LostInstance(fsm fsm.FSM) {
 	fsm.Signal(instanceGone)
 }
 
+func (m *model) Set() *fsm.Set {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.set
+}
+
 func (m *model) Spec() *fsm.Spec {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
@@ -165,12 +173,14 @@ func (m *model) Start() {
 
 	m.clock.Start()
 	m.set = fsm.NewSet(m.spec, m.clock, fsm.DefaultOptions("swarm"))
+	metrics.RegisterSet("gc", m.set)
 }
 
 func (m *model) Stop() {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	metrics.UnregisterSet(m.set)
 	m.set.Stop()
 	m.clock.Stop()
 
@@ -204,7 +214,7 @@ func longer(a, b time.Duration) time.Duration {
 }
 
 // BuildModel constructs a workflow model given the configuration blob provided by user in the Properties
-func BuildModel(properties gc_types.Properties) (gc.Model, error) {
+func BuildModel(properties *gc_types.Properties) (gc.Model, error) {
 
 	modelProperties := defaultModelProperties
 	if properties.ModelProperties != nil {
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/infrakit/pkg/controller/gc"
 	gc_types "github.com/docker/infrakit/pkg/controller/gc/types"
 	"github.com/docker/infrakit/pkg/fsm"
 	"github.com/docker/infrakit/pkg/types"
@@ -29,11 +30,15 @@ InstanceObserver:
 
 	require.NoError(t, err)
 
-	m, err := BuildModel(*gcProperties)
+	m, err := BuildModel(gcProperties)
 	require.NoError(t, err)
 
 	model := m.(*model)
 
+	// the items are removed as soon as either side is lost
+	_, partial := m.(gc.PartialItems)
+	require.False(t, partial)
+
 	require.Equal(t, fsm.Tick(20), model.NoData)
 	require.Equal(t, 1*time.Second, model.TickUnit.Duration())
 	require.Equal(t, 10*time.Second, model.tickSize) // must take the slower of the two durations of 10s vs 1s
@@ -18,7 +18,7 @@ import (
 type reaper struct {
 	*internal.Collection
 
-	properties gc.Properties
+	properties *gc.Properties
 	options    gc.Options
 
 	model Model
@@ -50,6 +50,9 @@ func newReaper(scope scope.Scope, options gc.Options) (internal.Managed, error)
 	base.StartFunc = r.run
 	base.StopFunc = r.stop
 	base.UpdateSpecFunc = r.updateSpec
+	base.SetFunc = r.set
+	base.StateStore = options.StateStore
+	base.StateSaveInterval = options.StateSaveInterval.Duration()
 
 	return r, nil
 }
@@ -69,6 +72,11 @@ func (r *reaper) run(ctx context.Context) {
 	r.model.Start()
 	log.Info("model started")
 
+	// Continue from the state saved by a previous run, if any
+	if _, err := r.Restore(r.model.Spec()); err != nil {
+		log.Error("Cannot restore state", "err", err)
+	}
+
 	go r.instanceObserver.Start()
 	log.Info("instance started")
 
@@ -82,6 +90,13 @@ func (r *reaper) run(ctx context.Context) {
 	log.Info("processing observations")
 }
 
+func (r *reaper) set() *fsm.Set {
+	if r.model == nil {
+		return nil
+	}
+	return r.model.Set()
+}
+
 func (r *reaper) stop() error {
 	r.instanceObserver.Stop()
 	r.nodeObserver.Stop()
@@ -96,9 +111,9 @@ var (
 
 func (r *reaper) updateSpec(spec types.Spec, prev *types.Spec) error {
 	// parse input, then select the model to use
-	properties := gc.Properties{}
+	properties := &gc.Properties{}
 
-	err := spec.Properties.Decode(&properties)
+	err := spec.Properties.Decode(properties)
 	if err != nil {
 		return err
 	}
@@ -113,7 +128,7 @@ func (r *reaper) updateSpec(spec types.Spec, prev *types.Spec) error {
 		return err
 	}
 
-	instanceObserver := properties.InstanceObserver
+	instanceObserver := &properties.InstanceObserver
 	if err := instanceObserver.Validate(defaultInstanceObserver); err != nil {
 		return err
 	}
@@ -121,7 +136,7 @@ func (r *reaper) updateSpec(spec types.Spec, prev *types.Spec) error {
 		return err
 	}
 
-	nodeObserver := properties.NodeObserver
+	nodeObserver := &properties.NodeObserver
 	if err := nodeObserver.Validate(defaultInstanceObserver); err != nil {
 		return err
 	}
@@ -129,8 +144,8 @@ func (r *reaper) updateSpec(spec types.Spec, prev *types.Spec) error {
 		return err
 	}
 
-	r.instanceObserver = &instanceObserver
-	r.nodeObserver = &nodeObserver
+	r.instanceObserver = instanceObserver
+	r.nodeObserver = nodeObserver
 
 	r.instances = instance_plugin.LazyConnect(
 		func() (instance.Plugin, error) {
@@ -151,8 +166,9 @@ func (r *reaper) updateSpec(spec types.Spec, prev *types.Spec) error {
 func (r *reaper) getNodeDescription(i fsm.FSM) (desc *instance.Description) {
 	r.Collection.Visit(func(item internal.Item) bool {
 		if item.State.ID() == i.ID() {
-			copy := (item.Data["node"]).(instance.Description)
-			desc = &copy
+			if copy, is := (item.Data["node"]).(instance.Description); is {
+				desc = &copy
+			}
 			return false
 		}
 		return true
@@ -163,8 +179,9 @@ func (r *reaper) getNodeDescription(i fsm.FSM) (desc *instance.Description) {
 func (r *reaper) getInstanceDescription(i fsm.FSM) (desc *instance.Description) {
 	r.Collection.Visit(func(item internal.Item) bool {
 		if item.State.ID() == i.ID() {
-			copy := (item.Data["instance"]).(instance.Description)
-			desc = &copy
+			if copy, is := (item.Data["instance"]).(instance.Description); is {
+				desc = &copy
+			}
 			return false
 		}
 		return true
@@ -217,6 +234,25 @@ func (r *reaper) gc(ctx context.Context) {
 	}
 }
 
+// forget removes the item whose side is lost.  If the model keeps partial items, only the lost side is
+// removed and the item is kept while the other side is still observed so the model can continue to act on
+// it, e.g. destroy the instance after its node is removed.
+func (r *reaper) forget(key string, side string) {
+	if partial, is := r.model.(PartialItems); !is || !partial.KeepPartialItems() {
+		r.Collection.Delete(key)
+		return
+	}
+
+	empty := false
+	r.Update(key, func(item *internal.Item) {
+		delete(item.Data, side)
+		empty = len(item.Data) == 0
+	})
+	if empty {
+		r.Collection.Delete(key)
+	}
+}
+
 func (r *reaper) processObservations(ctx context.Context) {
 	for {
 		select {
@@ -236,7 +272,7 @@ func (r *reaper) processObservations(ctx context.Context) {
 				if item != nil {
 					r.model.LostNode(item.State)
 
-					r.Collection.Delete(key)
+					r.forget(key, "node")
 					log.Debug("lostNode", "node", lost, "key", key, "V", debugV)
 				}
 			}
@@ -256,7 +292,7 @@ func (r *reaper) processObservations(ctx context.Context) {
 				if item != nil {
 					r.model.LostInstance(item.State)
 
-					r.Collection.Delete(key)
+					r.forget(key, "instance")
 					log.Debug("lostInstance", "instance", lost, "key", key, "V", debugV)
 				}
 			}
@@ -278,7 +314,7 @@ func (r *reaper) processObservations(ctx context.Context) {
 					item = r.Collection.Put(key, r.model.New(), r.model.Spec(), nil)
 				}
 
-				item.Data["node"] = found // update the node
+				r.Update(key, func(item *internal.Item) { item.Data["node"] = found }) // update the node
 
 				r.model.FoundNode(item.State, found) // signal the fsm
 
@@ -301,12 +337,15 @@ func (r *reaper) processObservations(ctx context.Context) {
 					item = r.Collection.Put(key, r.model.New(), r.model.Spec(), nil)
 				}
 
-				item.Data["instance"] = found // update the instance
+				r.Update(key, func(item *internal.Item) { item.Data["instance"] = found }) // update the instance
 
 				r.model.FoundInstance(item.State, found) // signal the fsm
 
 				log.Debug("foundInstance", "instance", found, "V", debugV)
 			}
 		}
+
+		// the fsm signaled so the state is saved, since the transitions of the model are not published
+		r.StateChanged()
 	}
 }
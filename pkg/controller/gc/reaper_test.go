@@ -0,0 +1,75 @@
+package gc // import "github.com/docker/infrakit/pkg/controller/gc"
+
+import (
+	"testing"
+
+	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/spi/instance"
+	testing_scope "github.com/docker/infrakit/pkg/testing/scope"
+	"github.com/stretchr/testify/require"
+)
+
+// testModel is a model that keeps the partial items or not
+type testModel struct {
+	Model
+	partial bool
+}
+
+func (m testModel) KeepPartialItems() bool {
+	return m.partial
+}
+
+func testReaper(t *testing.T, model Model) (*reaper, *fsm.Spec, *fsm.Set) {
+	managed, err := newReaper(testing_scope.DefaultScope(), DefaultOptions)
+	require.NoError(t, err)
+	r := managed.(*reaper)
+	r.model = model
+
+	events := make(chan *event.Event)
+	go func() {
+		for range events {
+		}
+	}()
+	r.PublishOn(events)
+
+	spec, err := fsm.Define(fsm.State{Index: 0})
+	require.NoError(t, err)
+	return r, spec, fsm.NewSet(spec, fsm.NewClock())
+}
+
+func TestForgetRemovesItems(t *testing.T) {
+	// models that don't keep partial items, e.g. swarm
+	for _, model := range []Model{nil, testModel{partial: false}} {
+		r, spec, set := testReaper(t, model)
+		defer set.Stop()
+
+		r.Put("node1", set.Add(0), spec, map[string]interface{}{
+			"node":     instance.Description{ID: "node1"},
+			"instance": instance.Description{ID: "i-1"},
+		})
+
+		r.forget("node1", "node")
+		require.Nil(t, r.Copy("node1"))
+	}
+}
+
+func TestForgetKeepsPartialItems(t *testing.T) {
+	r, spec, set := testReaper(t, testModel{partial: true})
+	defer set.Stop()
+
+	r.Put("node1", set.Add(0), spec, map[string]interface{}{
+		"node":     instance.Description{ID: "node1"},
+		"instance": instance.Description{ID: "i-1"},
+	})
+
+	// kept while the instance is still observed
+	r.forget("node1", "node")
+	item := r.Copy("node1")
+	require.NotNil(t, item)
+	require.Nil(t, item.Data["node"])
+	require.Equal(t, instance.Description{ID: "i-1"}, item.Data["instance"])
+
+	r.forget("node1", "instance")
+	require.Nil(t, r.Copy("node1"))
+}
@@ -7,6 +7,7 @@ import (
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/run/depends"
 	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/store"
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -68,7 +69,7 @@ type Properties struct {
 }
 
 // Validate validates the input properties
-func (p Properties) Validate(ctx context.Context) error {
+func (p *Properties) Validate(ctx context.Context) error {
 	return nil
 }
 
@@ -77,6 +78,13 @@ type Options struct {
 
 	// PluginRetryInterval is the interval for retrying to connect to the plugins
 	PluginRetryInterval types.Duration
+
+	// StateStore is where the state of the collections is saved, so that the controller can continue where
+	// it left off after a restart or a change of leadership.  The state is not saved if nil.
+	StateStore store.Snapshot `json:"-" yaml:"-"`
+
+	// StateSaveInterval is how often the state is saved, in addition to the transitions of the items.
+	StateSaveInterval types.Duration
 }
 
 // Validate validates the controller's options
@@ -0,0 +1,94 @@
+package group // import "github.com/docker/infrakit/pkg/controller/group"
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	errCanaryAborted = errors.New("Canary aborted by user")
+	errNotPaused     = errors.New("Update is not paused")
+)
+
+// canaryGate pauses a rolling update once the canary instances are in place, until the update
+// is promoted or aborted.  Because the canary instances are identified by their config hash, an update
+// that is planned again (e.g. when the spec is committed again) pauses at the same point, unless the
+// promotion has been recorded in the spec as CanaryPolicy.Promoted.
+type canaryGate struct {
+	lock     sync.Mutex
+	size     int
+	paused   bool
+	promoted bool
+	resume   chan bool
+}
+
+func newCanaryGate() *canaryGate {
+	return &canaryGate{resume: make(chan bool, 1)}
+}
+
+// enable sets the number of canary instances
+func (g *canaryGate) enable(size int) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.size = size
+}
+
+// pending returns true if the update has a canary phase that has not been promoted yet
+func (g *canaryGate) pending() bool {
+	if g == nil {
+		return false
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.size > 0 && !g.promoted
+}
+
+// state returns the size of the canary and whether the update is paused.  A size of 0 means that
+// there is no canary phase.
+func (g *canaryGate) state() (size int, paused bool) {
+	if g == nil {
+		return 0, false
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.size, g.paused
+}
+
+// wait blocks until the update is promoted, aborted or stopped.
+func (g *canaryGate) wait(stop <-chan bool) error {
+	g.lock.Lock()
+	g.paused = true
+	g.lock.Unlock()
+
+	select {
+	case promote := <-g.resume:
+		if !promote {
+			return errCanaryAborted
+		}
+		g.lock.Lock()
+		g.promoted = true
+		g.lock.Unlock()
+		return nil
+	case <-stop:
+		return errors.New("Update halted by user")
+	}
+}
+
+// signal resumes a paused update.  The update continues if promote is true and is aborted otherwise.
+func (g *canaryGate) signal(promote bool) error {
+	if g == nil {
+		return errNotPaused
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if !g.paused {
+		return errNotPaused
+	}
+	g.paused = false
+	g.resume <- promote
+	return nil
+}
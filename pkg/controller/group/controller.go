@@ -1,9 +1,12 @@
 package group // import "github.com/docker/infrakit/pkg/controller/group"
 
 import (
+	"context"
 	"fmt"
 
+	group_types "github.com/docker/infrakit/pkg/controller/group/types"
 	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/spi/controller"
 	"github.com/docker/infrakit/pkg/spi/group"
 	"github.com/docker/infrakit/pkg/types"
@@ -32,6 +35,13 @@ type adapter struct {
 	plugin group.Plugin
 }
 
+// WithContext implements spi.Contextual.  The group plugin is used in the context if it is Contextual.
+func (c *adapter) WithContext(ctx context.Context) interface{} {
+	copy := *c
+	copy.plugin = spi.WithContext(c.plugin, ctx).(group.Plugin)
+	return &copy
+}
+
 func (c *adapter) translateSpec(spec types.Spec) (group.Spec, error) {
 	gSpec := group.Spec{
 		Properties: spec.Properties,
@@ -104,13 +114,21 @@ func (c *adapter) Plan(operation controller.Operation,
 		return
 	}
 
-	plan = controller.Plan{}
+	after, e := group_types.ParseProperties(gSpec)
+	if e != nil {
+		err = e
+		return
+	}
+
+	plan = controller.Plan{After: &spec}
 	objects, e := c.Describe(&spec.Metadata)
 	if e != nil {
 		err = e
 		return
 	}
 
+	var before *group_types.Spec
+	desc := group.Description{}
 	if len(objects) == 0 {
 		object, err = buildObject(spec, group.Description{})
 		if err != nil {
@@ -120,16 +138,35 @@ func (c *adapter) Plan(operation controller.Operation,
 	} else if len(objects) == 1 {
 		object = objects[0]
 		plan.Message = []string{"update-existing"}
+		plan.Before = &object.Spec
+
+		if current, e := group_types.ParseProperties(group.Spec{Properties: object.Spec.Properties}); e == nil {
+			before = &current
+		}
+		if object.State != nil {
+			if err = object.State.Decode(&desc); err != nil {
+				return
+			}
+		}
 	} else {
 		err = fmt.Errorf("change affects more than one object")
 		return
 	}
 
+	if operation == controller.Destroy {
+		plan.After = nil
+		plan.Message = []string{fmt.Sprintf("Destroying %d instances", len(desc.Instances))}
+		plan.Changes = planChanges(operation, before, after, desc.Instances)
+		return
+	}
+
 	if resp, cerr := c.plugin.CommitGroup(gSpec, true); cerr == nil {
 		plan.Message = []string{resp}
 	} else {
 		err = cerr
+		return
 	}
+	plan.Changes = planChanges(operation, before, after, desc.Instances)
 	return
 }
 
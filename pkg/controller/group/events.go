@@ -0,0 +1,58 @@
+package group // import "github.com/docker/infrakit/pkg/controller/group"
+
+import (
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/spi/group"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+const (
+	// topicRollback is the topic for events about groups being rolled back after a failed update
+	topicRollback = "rollback"
+
+	// topicHealing is the topic for events about unhealthy instances being replaced
+	topicHealing = "healing"
+
+	eventTypeRollback = event.Type("Rollback")
+	eventTypeReplaced = event.Type("Replaced")
+)
+
+var topics = func() map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, topic := range types.PathsFromStrings(
+		topicRollback,
+		topicHealing,
+	) {
+		types.Put(topic, "", m)
+	}
+	return m
+}()
+
+// List returns the nodes under the given topic
+func (p *gController) List(topic types.Path) ([]string, error) {
+	return types.List(topic, topics), nil
+}
+
+// PublishOn sets the channel to publish on
+func (p *gController) PublishOn(events chan<- *event.Event) {
+	p.eventsLock.Lock()
+	defer p.eventsLock.Unlock()
+
+	p.events = events
+}
+
+// publish publishes an event for the group if there's a channel to publish on
+func (p *gController) publish(topic string, typ event.Type, id group.ID, data interface{}, message string) {
+	p.eventsLock.Lock()
+	defer p.eventsLock.Unlock()
+
+	if p.events == nil {
+		return
+	}
+
+	p.events <- event.Event{
+		Type:    typ,
+		ID:      string(id),
+		Message: message,
+	}.Init().WithTopic(topic).WithDataMust(data)
+}
@@ -1,8 +1,10 @@
 package group // import "github.com/docker/infrakit/pkg/controller/group"
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
@@ -10,7 +12,9 @@ import (
 
 	group_types "github.com/docker/infrakit/pkg/controller/group/types"
 	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/metrics"
 	plugin_base "github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/spi/event"
 	"github.com/docker/infrakit/pkg/spi/flavor"
 	"github.com/docker/infrakit/pkg/spi/group"
 	"github.com/docker/infrakit/pkg/spi/instance"
@@ -58,9 +62,31 @@ type gController struct {
 	maxParallelNum  uint
 	lock            sync.RWMutex
 	groups          groups
+
+	eventsLock sync.Mutex
+	events     chan<- *event.Event
+}
+
+// gControllerInContext is the group plugin used in a context, e.g. the trace of the commit served.
+type gControllerInContext struct {
+	*gController
+	ctx context.Context
+}
+
+// WithContext implements spi.Contextual.  The updates of the groups committed use the plugins in the context.
+func (p *gController) WithContext(ctx context.Context) interface{} {
+	return &gControllerInContext{gController: p, ctx: ctx}
+}
+
+func (p *gControllerInContext) CommitGroup(config group.Spec, pretend bool) (string, error) {
+	return p.commitGroup(p.ctx, config, pretend)
 }
 
 func (p *gController) CommitGroup(config group.Spec, pretend bool) (string, error) {
+	return p.commitGroup(context.Background(), config, pretend)
+}
+
+func (p *gController) commitGroup(ctx context.Context, config group.Spec, pretend bool) (string, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -85,26 +111,17 @@ func (p *gController) CommitGroup(config group.Spec, pretend bool) (string, erro
 		// not be much work, and will make this routine easier to follow.
 
 		// TODO(wfarner): Don't hold the lock - this is a blocking operation.
-		updatePlan, err := context.supervisor.PlanUpdate(context.scaled, context.settings, settings)
+		updatePlan, err := context.supervisor.PlanUpdate(context.scaled.in(ctx), context.settings, settings)
 		if err != nil {
 			return "unable to fulfill request", err
 		}
 
 		if !pretend {
+			previous := context.currentSettings()
+			context.setRollback(nil)
 			context.setUpdate(updatePlan)
 			context.changeSettings(settings)
-			go func() {
-				log.Info("Executing update plan",
-					"groupID", config.ID,
-					"updating", settings.config.Updating,
-					"plan", updatePlan.Explain())
-				if err := updatePlan.Run(p.pollInterval, settings.config.Updating); err != nil {
-					log.Error("Update failed", "groupID", config.ID, "err", err)
-				} else {
-					log.Info("Convergence", "groupID", config.ID)
-				}
-				context.setUpdate(nil)
-			}()
+			p.runUpdate(ctx, config.ID, context, updatePlan, settings, &previous)
 		}
 
 		return updatePlan.Explain(), nil
@@ -126,13 +143,101 @@ func (p *gController) CommitGroup(config group.Spec, pretend bool) (string, erro
 
 	scaled.supervisor = supervisor
 	if !pretend {
-		p.groups.put(config.ID, &groupContext{supervisor: supervisor, scaled: scaled, settings: settings})
+		context := &groupContext{supervisor: supervisor, scaled: scaled, settings: settings}
+		context.healer = newHealer(config.ID, scaled, p.pollInterval,
+			supervisor.Size, context.currentSettings, context.updating)
+		context.healer.replaced = func(inst instance.Description) {
+			p.publish(topicHealing, eventTypeReplaced, config.ID, inst,
+				fmt.Sprintf("Replacing unhealthy instance %v of group %v", inst.ID, config.ID))
+		}
+		p.groups.put(config.ID, context)
+		metrics.GroupConverged.WithLabelValues(string(config.ID)).Set(1)
 		go supervisor.Run()
+		go context.healer.Run()
 	}
 
 	return fmt.Sprintf("Managing %d instances", supervisor.Size()), nil
 }
 
+// runUpdate executes the update plan in the background.  If the update fails because the new instances
+// do not become healthy or its canary is aborted, and the previous settings are known, the group is rolled
+// back to them.  The plugins are used in the context of the commit that started the update.
+func (p *gController) runUpdate(ctx context.Context, id group.ID, context *groupContext, plan updatePlan,
+	settings groupSettings, previous *groupSettings) {
+
+	updating := settings.config.Updating
+	if previous == nil {
+		// Never roll back a rollback, nor pause it for canaries
+		updating.Rollback = nil
+		updating.Canary = nil
+	}
+	if updating.Canary != nil && updating.Canary.Promoted == settings.config.InstanceHash() {
+		// The canary phase of this update was promoted before the update was planned again
+		updating.Canary = nil
+	}
+
+	go func() {
+		log.Info("Executing update plan",
+			"groupID", id,
+			"updating", updating,
+			"plan", plan.Explain())
+		err := plan.Run(p.pollInterval, updating)
+		if err == nil {
+			log.Info("Convergence", "groupID", id)
+			context.setUpdate(nil)
+			return
+		}
+
+		log.Error("Update failed", "groupID", id, "err", err)
+		if _, is := err.(errRollback); (is || err == errCanaryAborted) && previous != nil {
+			// The rollback replaces the update of the context so there is no need to clear it
+			p.rollback(ctx, id, settings, *previous, err)
+			return
+		}
+		context.setUpdate(nil)
+	}()
+}
+
+// rollback commits the previous settings of a group after a failed update.  The rollback is skipped
+// if the group has been changed since.
+func (p *gController) rollback(ctx context.Context, id group.ID, failed, previous groupSettings, cause error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	context, exists := p.groups.get(id)
+	if !exists {
+		return
+	}
+
+	current := context.currentSettings()
+	if !reflect.DeepEqual(current.config, failed.config) {
+		log.Info("Group changed since the failed update, not rolling back", "groupID", id)
+		return
+	}
+	plan, err := context.supervisor.PlanUpdate(context.scaled.in(ctx), current, previous)
+	if err != nil {
+		log.Error("Unable to plan rollback", "groupID", id, "err", err)
+		context.setUpdate(nil)
+		return
+	}
+
+	status := &group.Rollback{
+		From:      current.config.InstanceHash(),
+		To:        previous.config.InstanceHash(),
+		Reason:    cause.Error(),
+		Timestamp: time.Now(),
+	}
+	log.Warn("Rolling back", "groupID", id, "from", status.From, "to", status.To, "reason", status.Reason)
+
+	context.setRollback(status)
+	context.setUpdate(plan)
+	context.changeSettings(previous)
+	p.publish(topicRollback, eventTypeRollback, id, status,
+		fmt.Sprintf("Rolling back group %v: %v", id, plan.Explain()))
+
+	p.runUpdate(ctx, id, context, plan, previous, nil)
+}
+
 func (p *gController) doFree(id group.ID) (*groupContext, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -144,7 +249,11 @@ func (p *gController) doFree(id group.ID) (*groupContext, error) {
 
 	grp.stopUpdating()
 	grp.supervisor.Stop()
+	if grp.healer != nil {
+		grp.healer.Stop()
+	}
 	p.groups.del(id)
+	metrics.DeleteGroup(string(id))
 
 	log.Info("Ignored", "groupID", id)
 	return grp, nil
@@ -171,7 +280,60 @@ func (p *gController) DescribeGroup(id group.ID) (group.Description, error) {
 		return group.Description{}, err
 	}
 
-	return group.Description{Instances: instances, Converged: !context.updating()}, nil
+	return group.Description{
+		Instances: instances,
+		Converged: !context.updating(),
+		Rollback:  context.lastRollback(),
+		Canary:    describeCanary(context, instances),
+	}, nil
+}
+
+// describeCanary returns the state of the canary phase of the update in progress, if any
+func describeCanary(context *groupContext, instances []instance.Description) *group.Canary {
+	size, paused := context.canary().state()
+	if size == 0 {
+		return nil
+	}
+
+	settings := context.currentSettings()
+	canary := &group.Canary{
+		ConfigSHA: settings.config.InstanceHash(),
+		Size:      size,
+		Paused:    paused,
+		Healthy:   []instance.ID{},
+		Unhealthy: []instance.ID{},
+		Unknown:   []instance.ID{},
+	}
+	desired, _ := desiredAndUndesiredInstances(instances, settings)
+	for _, inst := range desired {
+		switch context.scaled.Health(inst) {
+		case flavor.Healthy:
+			canary.Healthy = append(canary.Healthy, inst.ID)
+		case flavor.Unhealthy:
+			canary.Unhealthy = append(canary.Unhealthy, inst.ID)
+		default:
+			canary.Unknown = append(canary.Unknown, inst.ID)
+		}
+	}
+	return canary
+}
+
+func (p *gController) PromoteUpdate(id group.ID) error {
+	context, exists := p.groups.get(id)
+	if !exists {
+		return fmt.Errorf("Group '%s' is not being watched", id)
+	}
+	log.Info("Promoting update", "groupID", id)
+	return context.canary().signal(true)
+}
+
+func (p *gController) AbortUpdate(id group.ID) error {
+	context, exists := p.groups.get(id)
+	if !exists {
+		return fmt.Errorf("Group '%s' is not being watched", id)
+	}
+	log.Info("Aborting update", "groupID", id)
+	return context.canary().signal(false)
 }
 
 func (p *gController) DestroyGroup(gid group.ID) error {
@@ -215,7 +377,15 @@ func (p *gController) Size(gid group.ID) (size int, err error) {
 	return
 }
 
-func (p *gController) SetSize(gid group.ID, size int) (err error) {
+func (p *gControllerInContext) SetSize(gid group.ID, size int) error {
+	return p.setSize(p.ctx, gid, size)
+}
+
+func (p *gController) SetSize(gid group.ID, size int) error {
+	return p.setSize(context.Background(), gid, size)
+}
+
+func (p *gController) setSize(ctx context.Context, gid group.ID, size int) (err error) {
 	if size < 0 {
 		return fmt.Errorf("size cannot be negative")
 	}
@@ -236,7 +406,7 @@ func (p *gController) SetSize(gid group.ID, size int) (err error) {
 			}
 			g.Allocation.Size = uint(size)
 			gg.Properties = types.AnyValueMust(g)
-			_, err = p.CommitGroup(gg, false)
+			_, err = p.commitGroup(ctx, gg, false)
 			return err
 		}
 	}
@@ -322,6 +492,11 @@ type updatePlan interface {
 	Stop()
 }
 
+// canaryUpdate is implemented by update plans that can pause after a canary phase
+type canaryUpdate interface {
+	canary() *canaryGate
+}
+
 type noopUpdate struct {
 }
 
@@ -363,6 +538,34 @@ func (p *gController) validate(config group.Spec) (groupSettings, error) {
 	if parsed.Updating.Count > 0 && parsed.Updating.Duration.Duration() > time.Duration(0) {
 		return noSettings, errors.New("Only one Updating method may be used")
 	}
+	if parsed.Updating.MaxUnavailable < 0 || parsed.Updating.MaxSurge < 0 {
+		return noSettings, errors.New("MaxUnavailable and MaxSurge must not be negative")
+	}
+	if parsed.Updating.MaxSurge > 0 && len(parsed.Allocation.LogicalIDs) > 0 {
+		return noSettings, errors.New("MaxSurge is not supported for groups allocated by LogicalIDs")
+	}
+	if parsed.Updating.Rollback != nil && parsed.Updating.Rollback.Deadline.Duration() <= time.Duration(0) {
+		return noSettings, errors.New("Rollback requires a Deadline")
+	}
+	if healing := parsed.Healing; healing != nil {
+		if healing.Count < 0 || healing.MaxParallel < 0 {
+			return noSettings, errors.New("Healing Count and MaxParallel must not be negative")
+		}
+		if healing.Count > 0 && healing.Duration.Duration() > time.Duration(0) {
+			return noSettings, errors.New("Only one Healing method may be used")
+		}
+		if healing.Count == 0 && healing.Duration.Duration() <= time.Duration(0) {
+			return noSettings, errors.New("Healing requires a Duration or a Count")
+		}
+	}
+	if canary := parsed.Updating.Canary; canary != nil {
+		if canary.Count < 0 || canary.Percentage < 0 || canary.Percentage > 100 {
+			return noSettings, errors.New("Canary Count must not be negative and Percentage must be within 0 and 100")
+		}
+		if canary.Count > 0 && canary.Percentage > 0 {
+			return noSettings, errors.New("Only one of Canary Count and Percentage may be used")
+		}
+	}
 
 	// Validate Flavor plugin
 	flavorPlugin, err := p.flavorPlugins(parsed.Flavor.Plugin)
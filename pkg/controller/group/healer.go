@@ -0,0 +1,164 @@
+package group // import "github.com/docker/infrakit/pkg/controller/group"
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/infrakit/pkg/metrics"
+	"github.com/docker/infrakit/pkg/spi/flavor"
+	"github.com/docker/infrakit/pkg/spi/group"
+	"github.com/docker/infrakit/pkg/spi/instance"
+)
+
+// healer watches the health of the instances of a group and destroys the instances that have been
+// Unhealthy for longer than allowed by the healing policy of the group.  The supervisor of the group
+// then replaces them as it would replace any missing instance.
+type healer struct {
+	id           group.ID
+	scaled       Scaled
+	pollInterval time.Duration
+
+	// size returns the target size of the group
+	size func() uint
+
+	// settings returns the current settings of the group
+	settings func() groupSettings
+
+	// paused returns true while the group is being updated.  Rolling updates replace instances on their own.
+	paused func() bool
+
+	// replaced is called for each instance destroyed
+	replaced func(inst instance.Description)
+
+	now       func() time.Time
+	unhealthy map[instance.ID]*unhealthy
+	stop      chan bool
+}
+
+// unhealthy tracks an instance that has been Unhealthy since the first poll that found it so
+type unhealthy struct {
+	since time.Time
+	polls int
+}
+
+func newHealer(id group.ID, scaled Scaled, pollInterval time.Duration,
+	size func() uint, settings func() groupSettings, paused func() bool) *healer {
+	return &healer{
+		id:           id,
+		scaled:       scaled,
+		pollInterval: pollInterval,
+		size:         size,
+		settings:     settings,
+		paused:       paused,
+		replaced:     func(instance.Description) {},
+		now:          time.Now,
+		unhealthy:    map[instance.ID]*unhealthy{},
+		stop:         make(chan bool),
+	}
+}
+
+func (h *healer) Run() {
+	ticker := time.NewTicker(h.pollInterval)
+	for {
+		select {
+		case <-ticker.C:
+			h.heal()
+		case <-h.stop:
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+func (h *healer) Stop() {
+	close(h.stop)
+}
+
+// heal checks the health of the instances once and destroys the instances that are due for a replacement
+func (h *healer) heal() {
+	settings := h.settings()
+	policy := settings.config.Healing
+	if policy == nil {
+		h.unhealthy = map[instance.ID]*unhealthy{}
+		return
+	}
+
+	descriptions, err := h.scaled.List()
+	if err != nil {
+		log.Error("Failed to list group instances", "groupID", h.id, "err", err)
+		return
+	}
+
+	now := h.now()
+	found := map[instance.ID]*unhealthy{}
+	due := []instance.Description{}
+	for _, inst := range descriptions {
+		if h.scaled.Health(inst) != flavor.Unhealthy {
+			continue
+		}
+		state, has := h.unhealthy[inst.ID]
+		if !has {
+			state = &unhealthy{since: now}
+			log.Info("Instance is unhealthy", "groupID", h.id, "id", inst.ID)
+		}
+		state.polls++
+		found[inst.ID] = state
+
+		if policy.Count > 0 && state.polls < policy.Count {
+			continue
+		}
+		if policy.Count == 0 && now.Sub(state.since) < policy.Duration.Duration() {
+			continue
+		}
+		if isSelf(inst, settings) {
+			log.Warn("Not replacing the unhealthy instance running this controller", "groupID", h.id, "id", inst.ID)
+			continue
+		}
+		due = append(due, inst)
+	}
+	h.unhealthy = found
+
+	if len(due) == 0 {
+		return
+	}
+	if h.paused() {
+		log.Info("Not replacing unhealthy instances during an update", "groupID", h.id, "count", len(due))
+		return
+	}
+
+	// Instances missing from the group are replacements not yet created
+	allowed := policy.Parallel()
+	if missing := int(h.size()) - len(descriptions); missing > 0 {
+		allowed -= missing
+	}
+	if allowed <= 0 {
+		log.Info("Waiting for replacements before replacing more unhealthy instances",
+			"groupID", h.id, "count", len(due))
+		return
+	}
+	if len(due) > allowed {
+		sort.Sort(sortByID{list: due})
+		due = due[:allowed]
+	}
+
+	grp := sync.WaitGroup{}
+	for _, inst := range due {
+		destroy := inst
+		log.Warn("Replacing unhealthy instance", "groupID", h.id, "id", destroy.ID)
+		delete(h.unhealthy, destroy.ID)
+
+		grp.Add(1)
+		go func() {
+			defer grp.Done()
+			// Destroy drains the instance via the flavor first
+			if err := h.scaled.Destroy(destroy, instance.Termination); err != nil {
+				log.Error("Failed to destroy unhealthy instance", "groupID", h.id, "id", destroy.ID, "err", err)
+				return
+			}
+			metrics.GroupInstancesReplaced.WithLabelValues(string(h.id)).Inc()
+			h.replaced(destroy)
+		}()
+	}
+	grp.Wait()
+}
@@ -0,0 +1,125 @@
+package group // import "github.com/docker/infrakit/pkg/controller/group"
+
+import (
+	"testing"
+	"time"
+
+	group_types "github.com/docker/infrakit/pkg/controller/group/types"
+	mock_group "github.com/docker/infrakit/pkg/mock/plugin/group"
+	"github.com/docker/infrakit/pkg/spi/flavor"
+	"github.com/docker/infrakit/pkg/spi/group"
+	"github.com/docker/infrakit/pkg/spi/instance"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func testHealer(scaled Scaled, size uint, healing *group_types.HealingPolicy) *healer {
+	settings := groupSettings{config: group_types.Spec{Healing: healing}}
+	return newHealer(group.ID("heal"), scaled, 1*time.Millisecond,
+		func() uint { return size },
+		func() groupSettings { return settings },
+		func() bool { return false },
+	)
+}
+
+func TestHealerCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaled := mock_group.NewMockScaled(ctrl)
+	h := testHealer(scaled, 3, &group_types.HealingPolicy{Count: 2})
+
+	replaced := []instance.ID{}
+	h.replaced = func(inst instance.Description) { replaced = append(replaced, inst.ID) }
+
+	scaled.EXPECT().List().Return([]instance.Description{a, b, c}, nil).Times(3)
+	scaled.EXPECT().Health(a).Return(flavor.Healthy).Times(3)
+	scaled.EXPECT().Health(b).Return(flavor.Unhealthy).Times(3)
+	gomock.InOrder(
+		scaled.EXPECT().Health(c).Return(flavor.Unhealthy),
+		scaled.EXPECT().Health(c).Return(flavor.Healthy),
+		scaled.EXPECT().Health(c).Return(flavor.Unhealthy),
+	)
+	scaled.EXPECT().Destroy(b, instance.Termination).Return(nil)
+
+	h.heal()
+	require.Equal(t, []instance.ID{}, replaced)
+
+	h.heal() // b is unhealthy for 2 polls, c recovered
+	require.Equal(t, []instance.ID{"b"}, replaced)
+
+	h.heal() // b is tracked anew after its replacement
+	require.Equal(t, []instance.ID{"b"}, replaced)
+	require.Equal(t, 1, h.unhealthy["b"].polls)
+	require.Equal(t, 1, h.unhealthy["c"].polls)
+}
+
+func TestHealerDuration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaled := mock_group.NewMockScaled(ctrl)
+	h := testHealer(scaled, 3, &group_types.HealingPolicy{Duration: types.FromDuration(1 * time.Minute)})
+
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	scaled.EXPECT().List().Return([]instance.Description{a, b, c}, nil).Times(3)
+	scaled.EXPECT().Health(a).Return(flavor.Healthy).Times(3)
+	scaled.EXPECT().Health(b).Return(flavor.Healthy).Times(3)
+	scaled.EXPECT().Health(c).Return(flavor.Unhealthy).Times(3)
+
+	h.heal()
+
+	now = now.Add(59 * time.Second)
+	h.heal()
+
+	scaled.EXPECT().Destroy(c, instance.Termination).Return(nil)
+
+	now = now.Add(1 * time.Second)
+	h.heal()
+}
+
+func TestHealerRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaled := mock_group.NewMockScaled(ctrl)
+	h := testHealer(scaled, 3, &group_types.HealingPolicy{Count: 1, MaxParallel: 2})
+
+	// All unhealthy, only 2 are replaced at a time
+	scaled.EXPECT().List().Return([]instance.Description{a, b, c}, nil)
+	scaled.EXPECT().Health(gomock.Any()).Return(flavor.Unhealthy).Times(3)
+	scaled.EXPECT().Destroy(a, instance.Termination).Return(nil)
+	scaled.EXPECT().Destroy(b, instance.Termination).Return(nil)
+	h.heal()
+
+	// The replacements are not created yet so c waits
+	scaled.EXPECT().List().Return([]instance.Description{c}, nil)
+	scaled.EXPECT().Health(c).Return(flavor.Unhealthy)
+	h.heal()
+
+	// One replacement is created, but the new instance is unhealthy too
+	scaled.EXPECT().List().Return([]instance.Description{c, d}, nil)
+	scaled.EXPECT().Health(gomock.Any()).Return(flavor.Unhealthy).Times(2)
+	scaled.EXPECT().Destroy(c, instance.Termination).Return(nil)
+	h.heal()
+}
+
+func TestHealerPaused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaled := mock_group.NewMockScaled(ctrl)
+	h := testHealer(scaled, 3, &group_types.HealingPolicy{Count: 1})
+	h.paused = func() bool { return true }
+
+	scaled.EXPECT().List().Return([]instance.Description{a, b, c}, nil)
+	scaled.EXPECT().Health(gomock.Any()).Return(flavor.Unhealthy).Times(3)
+	h.heal()
+
+	// Without a policy the health is not checked
+	h = testHealer(scaled, 3, nil)
+	h.heal()
+}
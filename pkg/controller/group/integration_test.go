@@ -11,6 +11,7 @@ import (
 
 	group_types "github.com/docker/infrakit/pkg/controller/group/types"
 	plugin_base "github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/spi/event"
 	"github.com/docker/infrakit/pkg/spi/flavor"
 	"github.com/docker/infrakit/pkg/spi/group"
 	"github.com/docker/infrakit/pkg/spi/instance"
@@ -535,6 +536,97 @@ func TestRollingUpdateUpdatingDuration(t *testing.T) {
 	require.NoError(t, grp.FreeGroup(id))
 }
 
+func TestRollingUpdateMaxSurge(t *testing.T) {
+	plugin := newTestInstancePlugin(
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+	)
+
+	// Track the largest size of the group observed while the update is in progress
+	mutex := sync.Mutex{}
+	maxSize := 0
+
+	flavorPlugin := testFlavor{
+		healthy: func(flavorProperties *types.Any, inst instance.Description) (flavor.Health, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if size := len(plugin.instancesCopy()); size > maxSize {
+				maxSize = size
+			}
+			return flavor.Healthy, nil
+		},
+	}
+	flavorLookup := func(_ plugin_base.Name) (flavor.Plugin, error) {
+		return &flavorPlugin, nil
+	}
+
+	grp := NewGroupPlugin(pluginLookup(pluginName, plugin), flavorLookup,
+		group_types.Options{
+			PollInterval: types.FromDuration(1 * time.Millisecond),
+		})
+	_, err := grp.CommitGroup(minions, false)
+	require.NoError(t, err)
+
+	updated := group.Spec{
+		ID:         id,
+		Properties: minionProperties(3, group_types.Updating{MaxSurge: 1}, "data2", "flavor2"),
+	}
+
+	desc, err := grp.CommitGroup(updated, false)
+	require.NoError(t, err)
+	require.Equal(t, "Performing a rolling update on 3 instances", desc)
+
+	require.NoError(t, awaitGroupConvergence(t, grp))
+
+	// The surge instance is removed by the scaler once the update completes
+	start := time.Now()
+	for len(plugin.instancesCopy()) != 3 {
+		require.True(t, time.Now().Sub(start) < 2*time.Second, "Group did not shrink back to its size")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mutex.Lock()
+	require.Equal(t, 4, maxSize)
+	mutex.Unlock()
+
+	instances, err := plugin.DescribeInstances(memberTags(updated.ID), false)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(instances))
+	for _, i := range instances {
+		require.Equal(t, provisionTagsDefault(updated, nil), i.Tags)
+	}
+
+	require.NoError(t, grp.FreeGroup(id))
+}
+
+func TestValidateMaxSurgeWithLogicalIDs(t *testing.T) {
+	plugin := newTestInstancePlugin()
+	grp := NewGroupPlugin(pluginLookup(pluginName, plugin), flavorPluginLookup,
+		group_types.Options{
+			PollInterval: types.FromDuration(1 * time.Millisecond),
+		})
+	p, is := grp.(*gController)
+	require.True(t, is)
+	spec := group_types.Spec{
+		Allocation: group.AllocationMethod{
+			LogicalIDs: leaderIDs,
+		},
+		Updating: group_types.Updating{
+			MaxSurge: 1,
+		},
+	}
+	props, err := types.AnyValue(spec)
+	require.NoError(t, err)
+	settings, err := p.validate(group.Spec{
+		ID:         group.ID("id"),
+		Properties: props,
+	})
+	require.Error(t, err)
+	require.EqualError(t, err, "MaxSurge is not supported for groups allocated by LogicalIDs")
+	require.Equal(t, groupSettings{}, settings)
+}
+
 func TestRollingUpdateDestroyError(t *testing.T) {
 	// The 2nd instance will error out on Destroy, causing the 3rd instance to not be updated.
 	plugin := newTestInstancePlugin(
@@ -1261,6 +1353,222 @@ func TestUpdateFailsWhenInstanceIsUnhealthy(t *testing.T) {
 	require.NoError(t, grp.FreeGroup(id))
 }
 
+func TestRollbackWhenInstanceIsUnhealthy(t *testing.T) {
+	plugin := newTestInstancePlugin(
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+	)
+
+	flavorPlugin := testFlavor{
+		healthy: func(flavorProperties *types.Any, inst instance.Description) (flavor.Health, error) {
+			if strings.Contains(flavorProperties.String(), "bad update") {
+				return flavor.Unhealthy, nil
+			}
+			return flavor.Healthy, nil
+		},
+	}
+	flavorLookup := func(_ plugin_base.Name) (flavor.Plugin, error) {
+		return &flavorPlugin, nil
+	}
+
+	grp := NewGroupPlugin(pluginLookup(pluginName, plugin), flavorLookup,
+		group_types.Options{
+			PollInterval: types.FromDuration(1 * time.Millisecond),
+		})
+
+	events := make(chan *event.Event, 10)
+	grp.(event.Publisher).PublishOn(events)
+
+	_, err := grp.CommitGroup(minions, false)
+	require.NoError(t, err)
+
+	updated := group.Spec{
+		ID: id,
+		Properties: minionProperties(3,
+			group_types.Updating{
+				Rollback: &group_types.RollbackPolicy{
+					Deadline: types.FromDuration(20 * time.Millisecond),
+				},
+			},
+			"data", "bad update"),
+	}
+
+	_, err = grp.CommitGroup(updated, false)
+	require.NoError(t, err)
+
+	evt := <-events
+	require.Equal(t, types.PathFromString("rollback"), evt.Topic)
+	require.Equal(t, string(id), evt.ID)
+
+	require.NoError(t, awaitGroupConvergence(t, grp))
+
+	desc, err := grp.DescribeGroup(id)
+	require.NoError(t, err)
+	require.NotNil(t, desc.Rollback)
+	require.Equal(t, group_types.MustParse(group_types.ParseProperties(minions)).InstanceHash(), desc.Rollback.To)
+	require.Equal(t, group_types.MustParse(group_types.ParseProperties(updated)).InstanceHash(), desc.Rollback.From)
+
+	// All instances should be at the original configuration
+	for _, inst := range plugin.instancesCopy() {
+		require.NotEqual(t, "bad update", inst.Init)
+	}
+	require.Len(t, plugin.instancesCopy(), 3)
+
+	// The rollback is reflected in the group spec
+	specs, err := grp.InspectGroups()
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	require.Nil(t, group_types.MustParse(group_types.ParseProperties(specs[0])).Updating.Rollback)
+
+	require.NoError(t, grp.FreeGroup(id))
+}
+
+func awaitCanaryPaused(t *testing.T, grp group.Plugin) group.Description {
+	start := time.Now()
+	for {
+		desc, err := grp.DescribeGroup(id)
+		require.NoError(t, err)
+		if desc.Canary != nil && desc.Canary.Paused {
+			return desc
+		}
+		require.True(t, time.Now().Sub(start) < 2*time.Second, "Canary has not paused in 2s")
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func countInit(plugin *testplugin, init string) int {
+	count := 0
+	for _, inst := range plugin.instancesCopy() {
+		if inst.Init == init {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCanaryUpdatePromote(t *testing.T) {
+	plugin := newTestInstancePlugin(
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+	)
+
+	grp := NewGroupPlugin(pluginLookup(pluginName, plugin), flavorPluginLookup,
+		group_types.Options{
+			PollInterval: types.FromDuration(1 * time.Millisecond),
+		})
+	_, err := grp.CommitGroup(group.Spec{ID: id, Properties: minionProperties(4, emptyUpdating, "data", "init")}, false)
+	require.NoError(t, err)
+
+	require.Error(t, grp.PromoteUpdate(id))
+
+	updated := group.Spec{
+		ID: id,
+		Properties: minionProperties(4,
+			group_types.Updating{Canary: &group_types.CanaryPolicy{Percentage: 50}},
+			"data", "canary"),
+	}
+	_, err = grp.CommitGroup(updated, false)
+	require.NoError(t, err)
+
+	desc := awaitCanaryPaused(t, grp)
+	require.False(t, desc.Converged)
+	require.Equal(t, 2, desc.Canary.Size)
+	require.Len(t, desc.Canary.Healthy, 2)
+	require.Equal(t, group_types.MustParse(group_types.ParseProperties(updated)).InstanceHash(), desc.Canary.ConfigSHA)
+	require.Equal(t, 2, countInit(plugin, "canary"))
+
+	require.NoError(t, grp.PromoteUpdate(id))
+	require.NoError(t, awaitGroupConvergence(t, grp))
+
+	desc, err = grp.DescribeGroup(id)
+	require.NoError(t, err)
+	require.Nil(t, desc.Canary)
+	require.Equal(t, 4, countInit(plugin, "canary"))
+	require.Len(t, plugin.instancesCopy(), 4)
+
+	require.NoError(t, grp.FreeGroup(id))
+}
+
+func TestCanaryUpdateAbort(t *testing.T) {
+	plugin := newTestInstancePlugin(
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+	)
+
+	grp := NewGroupPlugin(pluginLookup(pluginName, plugin), flavorPluginLookup,
+		group_types.Options{
+			PollInterval: types.FromDuration(1 * time.Millisecond),
+		})
+	_, err := grp.CommitGroup(minions, false)
+	require.NoError(t, err)
+
+	updated := group.Spec{
+		ID: id,
+		Properties: minionProperties(3,
+			group_types.Updating{Canary: &group_types.CanaryPolicy{Count: 1}},
+			"data", "canary"),
+	}
+	_, err = grp.CommitGroup(updated, false)
+	require.NoError(t, err)
+
+	awaitCanaryPaused(t, grp)
+	require.Equal(t, 1, countInit(plugin, "canary"))
+
+	require.NoError(t, grp.AbortUpdate(id))
+	require.NoError(t, awaitGroupConvergence(t, grp))
+
+	desc, err := grp.DescribeGroup(id)
+	require.NoError(t, err)
+	require.NotNil(t, desc.Rollback)
+	require.Equal(t, errCanaryAborted.Error(), desc.Rollback.Reason)
+	require.Equal(t, 0, countInit(plugin, "canary"))
+	require.Len(t, plugin.instancesCopy(), 3)
+
+	require.NoError(t, grp.FreeGroup(id))
+}
+
+func TestCanaryUpdateAlreadyPromoted(t *testing.T) {
+	plugin := newTestInstancePlugin(
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+		newFakeInstanceDefault(minions, nil),
+	)
+
+	grp := NewGroupPlugin(pluginLookup(pluginName, plugin), flavorPluginLookup,
+		group_types.Options{
+			PollInterval: types.FromDuration(1 * time.Millisecond),
+		})
+	_, err := grp.CommitGroup(minions, false)
+	require.NoError(t, err)
+
+	// the promotion of the update is recorded in the spec, e.g. the spec is committed again after the promotion
+	canary := &group_types.CanaryPolicy{Count: 1}
+	hash := group_types.MustParse(group_types.ParseProperties(group.Spec{
+		ID:         id,
+		Properties: minionProperties(3, group_types.Updating{Canary: canary}, "data", "canary"),
+	})).InstanceHash()
+	canary.Promoted = hash
+
+	updated := group.Spec{
+		ID:         id,
+		Properties: minionProperties(3, group_types.Updating{Canary: canary}, "data", "canary"),
+	}
+	_, err = grp.CommitGroup(updated, false)
+	require.NoError(t, err)
+	require.NoError(t, awaitGroupConvergence(t, grp))
+
+	desc, err := grp.DescribeGroup(id)
+	require.NoError(t, err)
+	require.Nil(t, desc.Canary)
+	require.Equal(t, 3, countInit(plugin, "canary"))
+
+	require.NoError(t, grp.FreeGroup(id))
+}
+
 func TestNoSideEffectsFromPretendCommit(t *testing.T) {
 	// Tests that internal state is not modified by a GroupCommit with Pretend=true.
 
@@ -0,0 +1,119 @@
+package group // import "github.com/docker/infrakit/pkg/controller/group"
+
+import (
+	"fmt"
+	"sort"
+
+	group_types "github.com/docker/infrakit/pkg/controller/group/types"
+	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/spi/group"
+	"github.com/docker/infrakit/pkg/spi/instance"
+)
+
+// planChanges returns the changes made to the instances of a group when the spec is committed with
+// the operation.  The instances are destroyed first, then created, and finally updated in batches.
+// The spec currently enforced, if any, is used to tell whether the instances yet to be labeled are up to date.
+func planChanges(op controller.Operation, before *group_types.Spec, spec group_types.Spec,
+	instances []instance.Description) []controller.Change {
+
+	sorted := make([]instance.Description, len(instances))
+	copy(sorted, instances)
+	sort.Sort(sortByID{list: sorted})
+
+	changes := []controller.Change{}
+	step := 0
+	add := func(t controller.ChangeType, list []instance.Description, message string) {
+		if len(list) == 0 {
+			return
+		}
+		step++
+		for _, inst := range list {
+			change := controller.Change{
+				Type:    t,
+				Step:    step,
+				ID:      string(inst.ID),
+				Message: message,
+			}
+			if inst.LogicalID != nil {
+				change.LogicalID = string(*inst.LogicalID)
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	if op == controller.Destroy {
+		add(controller.ChangeDestroy, sorted, "destroy instance")
+		return changes
+	}
+
+	unlabeled := []instance.Description{}
+	for _, inst := range sorted {
+		if instanceNeedsLabel(inst) {
+			unlabeled = append(unlabeled, inst)
+		}
+	}
+	add(controller.ChangeLabel, unlabeled, "label instance with the config hash")
+
+	kept := sorted
+	create := []instance.Description{}
+
+	if len(spec.Allocation.LogicalIDs) > 0 {
+		wanted := map[instance.LogicalID]bool{}
+		for _, id := range spec.Allocation.LogicalIDs {
+			wanted[id] = true
+		}
+		found := map[instance.LogicalID]bool{}
+		unknown := []instance.Description{}
+		kept = []instance.Description{}
+		for _, inst := range sorted {
+			if inst.LogicalID != nil && !wanted[*inst.LogicalID] {
+				unknown = append(unknown, inst)
+				continue
+			}
+			if inst.LogicalID != nil {
+				found[*inst.LogicalID] = true
+			}
+			kept = append(kept, inst)
+		}
+		for _, id := range spec.Allocation.LogicalIDs {
+			if !found[id] {
+				logicalID := id
+				create = append(create, instance.Description{LogicalID: &logicalID})
+			}
+		}
+		add(controller.ChangeDestroy, unknown, "destroy instance with unknown logical id")
+
+	} else {
+		size := int(spec.Allocation.Size)
+		if len(sorted) > size {
+			// same as the scaler, which removes the instances in the order of their ids
+			add(controller.ChangeDestroy, sorted[:len(sorted)-size],
+				fmt.Sprintf("destroy instance to reduce the group size to %d", size))
+			kept = sorted[len(sorted)-size:]
+		}
+		for i := len(sorted); i < size; i++ {
+			create = append(create, instance.Description{})
+		}
+	}
+
+	add(controller.ChangeCreate, create, "create instance")
+
+	hash := spec.InstanceHash()
+	undesired := []instance.Description{}
+	for _, inst := range kept {
+		actual := inst.Tags[group.ConfigSHATag]
+		if instanceNeedsLabel(inst) && before != nil {
+			// labeling tags the instance with the hash of the current config
+			actual = before.InstanceHash()
+		}
+		if actual != hash {
+			undesired = append(undesired, inst)
+		}
+	}
+	batchSize := spec.Updating.BatchSize()
+	for i := 0; i < len(undesired); i += batchSize {
+		batch := undesired[i:minInt(i+batchSize, len(undesired))]
+		add(controller.ChangeUpdate, batch, fmt.Sprintf("replace instance with config %s", hash))
+	}
+	return changes
+}
@@ -0,0 +1,128 @@
+package group // import "github.com/docker/infrakit/pkg/controller/group"
+
+import (
+	"testing"
+
+	group_types "github.com/docker/infrakit/pkg/controller/group/types"
+	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/spi/group"
+	"github.com/docker/infrakit/pkg/spi/instance"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func planSpec(size uint, instanceType string) group_types.Spec {
+	return group_types.Spec{
+		Instance: group_types.InstancePlugin{
+			Plugin:     "instance",
+			Properties: types.AnyValueMust(map[string]string{"type": instanceType}),
+		},
+		Allocation: group.AllocationMethod{Size: size},
+	}
+}
+
+func planInstance(id, hash string, logicalID *instance.LogicalID) instance.Description {
+	return instance.Description{
+		ID:        instance.ID(id),
+		LogicalID: logicalID,
+		Tags:      map[string]string{group.ConfigSHATag: hash},
+	}
+}
+
+func changeIDs(changes []controller.Change, t controller.ChangeType) []string {
+	ids := []string{}
+	for _, c := range changes {
+		if c.Type == t {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
+
+func TestPlanChangesScale(t *testing.T) {
+	spec := planSpec(3, "small")
+	hash := spec.InstanceHash()
+
+	instances := []instance.Description{
+		planInstance("b", hash, nil),
+		planInstance("a", hash, nil),
+	}
+
+	changes := planChanges(controller.Enforce, &spec, spec, instances)
+	require.Equal(t, []controller.Change{
+		{Type: controller.ChangeCreate, Step: 1, Message: "create instance"},
+	}, changes)
+
+	spec.Allocation.Size = 1
+	changes = planChanges(controller.Enforce, &spec, spec, instances)
+	require.Equal(t, 1, len(changes))
+	require.Equal(t, controller.ChangeDestroy, changes[0].Type)
+	require.Equal(t, "a", changes[0].ID)
+
+	spec.Allocation.Size = 2
+	require.Equal(t, []controller.Change{}, planChanges(controller.Enforce, &spec, spec, instances))
+}
+
+func TestPlanChangesRollingUpdate(t *testing.T) {
+	before := planSpec(3, "small")
+	after := planSpec(3, "large")
+	after.Updating.MaxUnavailable = 2
+
+	instances := []instance.Description{
+		planInstance("c", before.InstanceHash(), nil),
+		planInstance("a", before.InstanceHash(), nil),
+		planInstance("b", bootstrapConfigTag, nil),
+	}
+
+	changes := planChanges(controller.Enforce, &before, after, instances)
+	require.Equal(t, []string{"b"}, changeIDs(changes, controller.ChangeLabel))
+	require.Equal(t, []string{"a", "b", "c"}, changeIDs(changes, controller.ChangeUpdate))
+
+	steps := map[string]int{}
+	for _, c := range changes {
+		steps[string(c.Type)+"/"+c.ID] = c.Step
+	}
+	require.Equal(t, map[string]int{
+		"label/b":  1,
+		"update/a": 2,
+		"update/b": 2,
+		"update/c": 3,
+	}, steps)
+
+	// labeling alone does not replace the instance
+	changes = planChanges(controller.Enforce, &before, before, instances)
+	require.Equal(t, []string{"b"}, changeIDs(changes, controller.ChangeLabel))
+	require.Equal(t, []string{}, changeIDs(changes, controller.ChangeUpdate))
+}
+
+func TestPlanChangesLogicalIDs(t *testing.T) {
+	id1, id2, id3 := instance.LogicalID("10.0.0.1"), instance.LogicalID("10.0.0.2"), instance.LogicalID("10.0.0.3")
+
+	spec := planSpec(0, "small")
+	spec.Allocation.LogicalIDs = []instance.LogicalID{id1, id3}
+	hash := spec.InstanceHash()
+
+	instances := []instance.Description{
+		planInstance("a", hash, &id1),
+		planInstance("b", hash, &id2),
+	}
+
+	changes := planChanges(controller.Enforce, &spec, spec, instances)
+	require.Equal(t, []controller.Change{
+		{Type: controller.ChangeDestroy, Step: 1, ID: "b", LogicalID: string(id2),
+			Message: "destroy instance with unknown logical id"},
+		{Type: controller.ChangeCreate, Step: 2, LogicalID: string(id3), Message: "create instance"},
+	}, changes)
+}
+
+func TestPlanChangesDestroy(t *testing.T) {
+	spec := planSpec(2, "small")
+	instances := []instance.Description{
+		planInstance("b", spec.InstanceHash(), nil),
+		planInstance("a", spec.InstanceHash(), nil),
+	}
+
+	changes := planChanges(controller.Destroy, &spec, spec, instances)
+	require.Equal(t, []string{"a", "b"}, changeIDs(changes, controller.ChangeDestroy))
+	require.Equal(t, len(instances), len(changes))
+}
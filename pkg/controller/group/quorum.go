@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/docker/infrakit/pkg/metrics"
 	"github.com/docker/infrakit/pkg/spi/group"
 	"github.com/docker/infrakit/pkg/spi/instance"
 )
@@ -49,12 +50,14 @@ func (q *quorum) PlanUpdate(scaled Scaled, settings groupSettings, newSettings g
 	}
 
 	return &rollingupdate{
+		id: q.id,
 		desc: fmt.Sprintf(
 			"Performing a rolling update on %d instances",
 			len(undesired)),
 		scaled:       scaled,
 		updatingFrom: settings,
 		updatingTo:   newSettings,
+		gate:         newCanaryGate(),
 		stop:         make(chan bool),
 	}, nil
 }
@@ -95,6 +98,8 @@ func (q *quorum) converge() {
 	}
 
 	log.Debug("Found existing instances", "groupID", q.ID(), "descriptions", descriptions, "V", debugV)
+	metrics.GroupTargetSize.WithLabelValues(string(q.id)).Set(float64(len(q.LogicalIDs)))
+	metrics.GroupSize.WithLabelValues(string(q.id)).Set(float64(len(descriptions)))
 
 	unknownIPs := []instance.Description{}
 	for _, description := range descriptions {
@@ -2,10 +2,12 @@ package group // import "github.com/docker/infrakit/pkg/controller/group"
 
 import (
 	"errors"
+	"fmt"
 	"sort"
 	"time"
 
 	group_types "github.com/docker/infrakit/pkg/controller/group/types"
+	"github.com/docker/infrakit/pkg/metrics"
 	"github.com/docker/infrakit/pkg/spi/flavor"
 	"github.com/docker/infrakit/pkg/spi/group"
 	"github.com/docker/infrakit/pkg/spi/instance"
@@ -38,14 +40,32 @@ func desiredAndUndesiredInstances(
 	return desired, undesired
 }
 
+// errRollback is returned by a rolling update when the new instances fail to become healthy
+// as defined by the rollback policy of the update.
+type errRollback struct {
+	failed   []instance.ID
+	deadline time.Duration
+}
+
+func (e errRollback) Error() string {
+	return fmt.Sprintf("Instances %v not healthy after %v", e.failed, e.deadline)
+}
+
 type rollingupdate struct {
+	id           group.ID
 	desc         string
 	scaled       Scaled
+	scaler       *scaler // optional; only set for groups allocated by size and used for surging
 	updatingFrom groupSettings
 	updatingTo   groupSettings
+	gate         *canaryGate
 	stop         chan bool
 }
 
+func (r *rollingupdate) canary() *canaryGate {
+	return r.gate
+}
+
 func (r rollingupdate) Explain() string {
 	return r.desc
 }
@@ -57,6 +77,8 @@ func (r *rollingupdate) waitUntilQuiesced(pollInterval time.Duration, updating g
 	log.Info("waitUntilQuiesced", "expectedNewInstances", expectedNewInstances)
 	// Track when the expected new instance count is healthy
 	counts := updatingCount{}
+	// Track since when each new instance is not healthy
+	unhealthy := unhealthyTracker{}
 	// TODO: start processing right away instead of waiting for first tick
 	ticker := time.NewTicker(pollInterval)
 	for {
@@ -86,7 +108,8 @@ func (r *rollingupdate) waitUntilQuiesced(pollInterval time.Duration, updating g
 			// The following design choices are currently implemented:
 			//
 			//   - the update will continue indefinitely if one or more instances are in the
-			//     flavor.UnknownHealth state.  Operators must stop the update and diagnose the cause.
+			//     flavor.UnknownHealth state, unless a rollback policy is set.  Operators must stop the
+			//     update and diagnose the cause.
 			//
 			//   - the update is stopped immediately if any instance enters the flavor.Unhealthy state.
 			//
@@ -98,7 +121,8 @@ func (r *rollingupdate) waitUntilQuiesced(pollInterval time.Duration, updating g
 				// TODO(wfarner): More careful thought is needed with respect to blocking and timeouts
 				// here.  This might mean formalizing timeout behavior for different types of RPCs in
 				// the group, and/or documenting the expectations for plugin implementations.
-				switch r.scaled.Health(inst) {
+				health := r.scaled.Health(inst)
+				switch health {
 				case flavor.Healthy:
 					log.Info("waitUntilQuiesced", "health", "heathy", "nodeID", inst.ID)
 					numHealthy++
@@ -107,6 +131,16 @@ func (r *rollingupdate) waitUntilQuiesced(pollInterval time.Duration, updating g
 				case flavor.Unknown:
 					log.Info("waitUntilQuiesced", "health", "unknown", "nodeID", inst.ID)
 				}
+				unhealthy.observe(inst.ID, health, time.Now())
+			}
+
+			if updating.Rollback != nil {
+				deadline := updating.Rollback.Deadline.Duration()
+				if failed := unhealthy.failed(deadline, time.Now()); len(failed) >= updating.Rollback.Threshold() {
+					ticker.Stop()
+					log.Warn("waitUntilQuiesced", "msg", "Update failed", "failed", failed, "deadline", deadline)
+					return errRollback{failed: failed, deadline: deadline}
+				}
 			}
 
 			if numHealthy >= int(expectedNewInstances) {
@@ -132,6 +166,35 @@ func (r *rollingupdate) waitUntilQuiesced(pollInterval time.Duration, updating g
 	}
 }
 
+// unhealthyTracker tracks since when each instance has not been healthy
+type unhealthyTracker map[instance.ID]time.Time
+
+func (u unhealthyTracker) observe(id instance.ID, health flavor.Health, now time.Time) {
+	if health == flavor.Healthy {
+		delete(u, id)
+		return
+	}
+	if _, has := u[id]; !has {
+		u[id] = now
+	}
+}
+
+// failed returns the instances that have not been healthy for at least the deadline
+func (u unhealthyTracker) failed(deadline time.Duration, now time.Time) []instance.ID {
+	ids := []string{}
+	for id, since := range u {
+		if now.Sub(since) >= deadline {
+			ids = append(ids, string(id))
+		}
+	}
+	sort.Strings(ids)
+	failed := []instance.ID{}
+	for _, id := range ids {
+		failed = append(failed, instance.ID(id))
+	}
+	return failed
+}
+
 // Tracks the progress of the update
 type updatingCount struct {
 	healthyTs    *time.Time
@@ -182,8 +245,11 @@ func (counts *updatingCount) exceedsHealthyThreshold(updating group_types.Updati
 	return true
 }
 
-// Run identifies instances not matching the desired state and destroys them one at a time until all instances in the
-// group match the desired state, with the desired number of instances.
+// Run identifies instances not matching the desired state and destroys them in batches until all instances in the
+// group match the desired state, with the desired number of instances.  The size of each batch is determined by
+// the MaxUnavailable and MaxSurge settings of the update.  If MaxSurge is set, the target size of the scaler is
+// raised for the duration of the update so that replacements are provisioned before undesired instances are
+// destroyed.
 // TODO(wfarner): Make this routine more resilient to transient errors.
 func (r *rollingupdate) Run(pollInterval time.Duration, updating group_types.Updating) error {
 
@@ -197,12 +263,31 @@ func (r *rollingupdate) Run(pollInterval time.Duration, updating group_types.Upd
 	expectedNewInstances := len(desired)
 	log.Info("RollingUpdate-Run", "expectedNewInstances", expectedNewInstances)
 
+	desiredSize := len(r.updatingTo.config.Allocation.LogicalIDs)
+	if desiredSize == 0 {
+		desiredSize = int(r.updatingTo.config.Allocation.Size)
+	}
+
+	if r.scaler != nil && updating.MaxSurge > 0 {
+		// Surge instances are created by the scaler with the new configuration, so they count
+		// towards the new instances that must be healthy before anything is destroyed.
+		originalSize := r.scaler.getSize()
+		r.scaler.SetSize(originalSize + uint(updating.MaxSurge))
+		defer r.scaler.SetSize(originalSize)
+
+		desiredSize += updating.MaxSurge
+		expectedNewInstances += updating.MaxSurge
+		log.Info("RollingUpdate-Run", "surge", updating.MaxSurge, "expectedNewInstances", expectedNewInstances)
+	}
+
+	batchSize := updating.BatchSize()
+
+	if updating.Canary != nil && r.gate != nil {
+		r.gate.enable(updating.Canary.Size(desiredSize - updating.MaxSurge))
+	}
+
 	for {
 		// Wait until any new nodes are healthy
-		desiredSize := len(r.updatingTo.config.Allocation.LogicalIDs)
-		if desiredSize == 0 {
-			desiredSize = int(r.updatingTo.config.Allocation.Size)
-		}
 		err := r.waitUntilQuiesced(pollInterval, updating, minInt(expectedNewInstances, desiredSize))
 		if err != nil {
 			return err
@@ -216,28 +301,51 @@ func (r *rollingupdate) Run(pollInterval time.Duration, updating group_types.Upd
 		// Now check if we have instances that do not match the hash
 		_, undesiredInstances := desiredAndUndesiredInstances(instances, r.updatingTo)
 		log.Info("RollingUpdate-Run", "undesiredInstances", len(undesiredInstances))
+		metrics.GroupUpdateRemaining.WithLabelValues(string(r.id)).Set(float64(len(undesiredInstances)))
 		if len(undesiredInstances) == 0 {
 			break
 		}
 
+		// Once the canary instances are in place, pause until the update is promoted or aborted.
+		canarySize, _ := r.gate.state()
+		if r.gate.pending() && len(instances)-len(undesiredInstances) >= canarySize {
+			log.Info("RollingUpdate-Run", "msg", "Canary in place, waiting for promotion", "canary", canarySize)
+			if err := r.gate.wait(r.stop); err != nil {
+				return err
+			}
+			continue
+		}
+
 		// Sort instances first to ensure predictable destroy order (if "self" is set then it
 		// is always sorted last)
 		sort.Sort(sortByID{list: undesiredInstances, settings: &r.updatingFrom})
 
-		// TODO(wfarner): Make the 'batch size' configurable.
-		if err := r.scaled.Destroy(undesiredInstances[0], instance.RollingUpdate); err != nil {
-			log.Warn("Failed to destroy instance during rolling update", "ID", undesiredInstances[0].ID, "err", err)
-			return err
+		size := minInt(batchSize, len(undesiredInstances))
+		if r.gate.pending() {
+			// Do not update more than the canary instances before the update is promoted
+			size = minInt(size, canarySize-(len(instances)-len(undesiredInstances)))
 		}
-		// Never invoke the instance Destroy on "self", the group Destroy only invokes the flavor
-		// Drain. Since we will never get a replacement VM for "self" we need to exit the loop.
-		if isSelf(undesiredInstances[0], r.updatingFrom) {
-			log.Info("Terminating update, current instance is all that remains", "self", *undesiredInstances[0].LogicalID)
-			return nil
+		batch := undesiredInstances[:size]
+		log.Info("RollingUpdate-Run", "batch", len(batch), "batchSize", batchSize)
+
+		for _, inst := range batch {
+			if err := r.scaled.Destroy(inst, instance.RollingUpdate); err != nil {
+				log.Warn("Failed to destroy instance during rolling update", "ID", inst.ID, "err", err)
+				return err
+			}
+			// Never invoke the instance Destroy on "self", the group Destroy only invokes the flavor
+			// Drain. Since we will never get a replacement VM for "self" we need to exit the loop.
+			// Self is always sorted last so it is always the last instance of the last batch.
+			if isSelf(inst, r.updatingFrom) {
+				log.Info("Terminating update, current instance is all that remains", "self", *inst.LogicalID)
+				return nil
+			}
 		}
 
-		// Increment new instance count to replace the node that was just destroyed
-		expectedNewInstances++
+		metrics.GroupUpdateBatches.WithLabelValues(string(r.id)).Inc()
+
+		// Increment new instance count to replace the nodes that were just destroyed
+		expectedNewInstances += len(batch)
 	}
 
 	return nil
@@ -1,10 +1,15 @@
 package group // import "github.com/docker/infrakit/pkg/controller/group"
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	group_types "github.com/docker/infrakit/pkg/controller/group/types"
+	"github.com/docker/infrakit/pkg/spi/flavor"
+	"github.com/docker/infrakit/pkg/spi/group"
+	"github.com/docker/infrakit/pkg/spi/instance"
 	"github.com/docker/infrakit/pkg/types"
 	"github.com/stretchr/testify/require"
 )
@@ -71,3 +76,107 @@ func TestExceedsHealthyThresholdDuration(t *testing.T) {
 	require.Nil(t, counts.healthyCount)
 	require.Equal(t, *counts.healthyTs, ts)
 }
+
+// fakeScaled replaces destroyed instances right away with instances of the new configuration.  It
+// records the number of List calls at the time of each Destroy so that batches can be identified.
+type fakeScaled struct {
+	lock      sync.Mutex
+	hash      string
+	instances []instance.Description
+	lists     int
+	destroyed map[instance.ID]int
+	nextID    int
+}
+
+func (f *fakeScaled) CreateOne(id *instance.LogicalID) {}
+
+func (f *fakeScaled) Health(inst instance.Description) flavor.Health {
+	return flavor.Healthy
+}
+
+func (f *fakeScaled) Destroy(inst instance.Description, ctx instance.Context) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.destroyed[inst.ID] = f.lists
+	for i, v := range f.instances {
+		if v.ID == inst.ID {
+			f.nextID++
+			f.instances[i] = instance.Description{
+				ID:   instance.ID(fmt.Sprintf("new-%d", f.nextID)),
+				Tags: map[string]string{group.ConfigSHATag: f.hash},
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeScaled) List() ([]instance.Description, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.lists++
+	return append([]instance.Description{}, f.instances...), nil
+}
+
+func (f *fakeScaled) Label() error {
+	return nil
+}
+
+func TestRollingUpdateBatchSize(t *testing.T) {
+	newSettings := groupSettings{
+		config: group_types.Spec{
+			Allocation: group.AllocationMethod{Size: 5},
+			Instance:   group_types.InstancePlugin{Properties: types.AnyString(`"new"`)},
+		},
+	}
+
+	scaled := &fakeScaled{
+		hash:      newSettings.config.InstanceHash(),
+		destroyed: map[instance.ID]int{},
+	}
+	for i := 0; i < 5; i++ {
+		scaled.instances = append(scaled.instances, instance.Description{
+			ID:   instance.ID(fmt.Sprintf("old-%d", i)),
+			Tags: map[string]string{group.ConfigSHATag: "old"},
+		})
+	}
+
+	update := &rollingupdate{
+		scaled:     scaled,
+		updatingTo: newSettings,
+		stop:       make(chan bool),
+	}
+	require.NoError(t, update.Run(1*time.Millisecond, group_types.Updating{MaxUnavailable: 2}))
+
+	require.Len(t, scaled.destroyed, 5)
+
+	// Instances are destroyed in sorted order, 2 at a time
+	require.Equal(t, scaled.destroyed["old-0"], scaled.destroyed["old-1"])
+	require.Equal(t, scaled.destroyed["old-2"], scaled.destroyed["old-3"])
+	require.True(t, scaled.destroyed["old-1"] < scaled.destroyed["old-2"])
+	require.True(t, scaled.destroyed["old-3"] < scaled.destroyed["old-4"])
+}
+
+func TestUpdatingBatchSize(t *testing.T) {
+	require.Equal(t, 1, group_types.Updating{}.BatchSize())
+	require.Equal(t, 3, group_types.Updating{MaxUnavailable: 3}.BatchSize())
+	require.Equal(t, 3, group_types.Updating{MaxUnavailable: 1, MaxSurge: 2}.BatchSize())
+}
+
+func TestUnhealthyTracker(t *testing.T) {
+	now := time.Now()
+	tracker := unhealthyTracker{}
+
+	tracker.observe("a", flavor.Unhealthy, now)
+	tracker.observe("b", flavor.Unknown, now.Add(5*time.Second))
+	tracker.observe("c", flavor.Healthy, now)
+	require.Equal(t, []instance.ID{}, tracker.failed(10*time.Second, now.Add(9*time.Second)))
+	require.Equal(t, []instance.ID{"a"}, tracker.failed(10*time.Second, now.Add(10*time.Second)))
+	require.Equal(t, []instance.ID{"a", "b"}, tracker.failed(10*time.Second, now.Add(15*time.Second)))
+
+	// Observing again does not reset the time unless the instance is healthy
+	tracker.observe("a", flavor.Unhealthy, now.Add(15*time.Second))
+	tracker.observe("b", flavor.Healthy, now.Add(15*time.Second))
+	require.Equal(t, []instance.ID{"a"}, tracker.failed(10*time.Second, now.Add(15*time.Second)))
+}
@@ -1,10 +1,12 @@
 package group // import "github.com/docker/infrakit/pkg/controller/group"
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
 	group_types "github.com/docker/infrakit/pkg/controller/group/types"
+	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/spi/flavor"
 	"github.com/docker/infrakit/pkg/spi/group"
 	"github.com/docker/infrakit/pkg/spi/instance"
@@ -58,8 +60,67 @@ func (s *scaledGroup) latestSettings() groupSettings {
 	return s.settings
 }
 
+// in returns the scaled group whose plugins are used in the context, e.g. the trace of the commit that
+// updates the group.
+func (s *scaledGroup) in(ctx context.Context) Scaled {
+	return &scaledInContext{scaledGroup: s, ctx: ctx}
+}
+
 func (s *scaledGroup) CreateOne(logicalID *instance.LogicalID) {
-	settings := s.latestSettings()
+	s.createOne(s.latestSettings(), logicalID)
+}
+
+func (s *scaledGroup) Health(inst instance.Description) flavor.Health {
+	return s.health(s.latestSettings(), inst)
+}
+
+func (s *scaledGroup) Destroy(inst instance.Description, ctx instance.Context) error {
+	return s.destroy(s.latestSettings(), inst, ctx)
+}
+
+func (s *scaledGroup) List() ([]instance.Description, error) {
+	return s.list(s.latestSettings())
+}
+
+func (s *scaledGroup) Label() error {
+	return s.label(s.latestSettings())
+}
+
+// scaledInContext is the scaled group used in a context.  The plugins of the latest settings are used in
+// the context if they are Contextual.
+type scaledInContext struct {
+	*scaledGroup
+	ctx context.Context
+}
+
+func (s *scaledInContext) latestSettings() groupSettings {
+	settings := s.scaledGroup.latestSettings()
+	settings.instancePlugin = spi.WithContext(settings.instancePlugin, s.ctx).(instance.Plugin)
+	settings.flavorPlugin = spi.WithContext(settings.flavorPlugin, s.ctx).(flavor.Plugin)
+	return settings
+}
+
+func (s *scaledInContext) CreateOne(logicalID *instance.LogicalID) {
+	s.createOne(s.latestSettings(), logicalID)
+}
+
+func (s *scaledInContext) Health(inst instance.Description) flavor.Health {
+	return s.health(s.latestSettings(), inst)
+}
+
+func (s *scaledInContext) Destroy(inst instance.Description, ctx instance.Context) error {
+	return s.destroy(s.latestSettings(), inst, ctx)
+}
+
+func (s *scaledInContext) List() ([]instance.Description, error) {
+	return s.list(s.latestSettings())
+}
+
+func (s *scaledInContext) Label() error {
+	return s.label(s.latestSettings())
+}
+
+func (s *scaledGroup) createOne(settings groupSettings, logicalID *instance.LogicalID) {
 
 	tags := map[string]string{}
 	for k, v := range s.memberTags {
@@ -106,9 +167,7 @@ func (s *scaledGroup) CreateOne(logicalID *instance.LogicalID) {
 	log.Info("Created instance", "id", *id, "tags", spec.Tags, "volumeDesc", volumeDesc)
 }
 
-func (s *scaledGroup) Health(inst instance.Description) flavor.Health {
-	settings := s.latestSettings()
-
+func (s *scaledGroup) health(settings groupSettings, inst instance.Description) flavor.Health {
 	health, err := settings.flavorPlugin.Healthy(types.AnyCopy(settings.config.Flavor.Properties), inst)
 	if err != nil {
 		log.Warn("Failed to check health of instance", "id", inst.ID, "err", err)
@@ -118,9 +177,7 @@ func (s *scaledGroup) Health(inst instance.Description) flavor.Health {
 
 }
 
-func (s *scaledGroup) Destroy(inst instance.Description, ctx instance.Context) error {
-	settings := s.latestSettings()
-
+func (s *scaledGroup) destroy(settings groupSettings, inst instance.Description, ctx instance.Context) error {
 	if ctx == instance.RollingUpdate && s.isSkipDrain() {
 		log.Info("Skipping drain before instance destroy", "id", inst.ID)
 	} else {
@@ -158,9 +215,7 @@ func (s *scaledGroup) isSkipDrain() bool {
 	return group_types.SkipBeforeInstanceDestroyDrain == *s.settings.config.Updating.SkipBeforeInstanceDestroy
 }
 
-func (s *scaledGroup) List() ([]instance.Description, error) {
-	settings := s.latestSettings()
-
+func (s *scaledGroup) list(settings groupSettings) ([]instance.Description, error) {
 	list := []instance.Description{}
 
 	found, err := settings.instancePlugin.DescribeInstances(s.memberTags, true)
@@ -183,9 +238,7 @@ func (s *scaledGroup) List() ([]instance.Description, error) {
 	return list, nil
 }
 
-func (s *scaledGroup) Label() error {
-	settings := s.latestSettings()
-
+func (s *scaledGroup) label(settings groupSettings) error {
 	instances, err := settings.instancePlugin.DescribeInstances(s.memberTags, false)
 	if err != nil {
 		return err
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	group_types "github.com/docker/infrakit/pkg/controller/group/types"
+	"github.com/docker/infrakit/pkg/metrics"
 	"github.com/docker/infrakit/pkg/spi/group"
 	"github.com/docker/infrakit/pkg/spi/instance"
 )
@@ -104,9 +105,12 @@ func (s *scaler) PlanUpdate(scaled Scaled, settings groupSettings, newSettings g
 	}
 
 	plan.rollingPlan = &rollingupdate{
+		id:           s.id,
 		scaled:       scaled,
+		scaler:       s,
 		updatingFrom: settings,
 		updatingTo:   newSettings,
+		gate:         newCanaryGate(),
 		stop:         make(chan bool),
 	}
 
@@ -151,6 +155,13 @@ func (s scalerUpdatePlan) Stop() {
 	s.rollingPlan.Stop()
 }
 
+func (s scalerUpdatePlan) canary() *canaryGate {
+	if u, is := s.rollingPlan.(canaryUpdate); is {
+		return u.canary()
+	}
+	return nil
+}
+
 func (s *scaler) SetSize(size uint) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -229,6 +240,9 @@ func (s *scaler) converge() {
 
 	actualSize := uint(len(descriptions))
 	desiredSize := s.getSize()
+	metrics.GroupTargetSize.WithLabelValues(string(s.id)).Set(float64(desiredSize))
+	metrics.GroupSize.WithLabelValues(string(s.id)).Set(float64(actualSize))
+
 	switch {
 	case actualSize == desiredSize:
 		log.Debug("No action - Group has enough instances", "desired", desiredSize)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/docker/infrakit/pkg/controller/group/types"
 	"github.com/docker/infrakit/pkg/controller/group/util"
+	"github.com/docker/infrakit/pkg/metrics"
 	"github.com/docker/infrakit/pkg/spi/flavor"
 	"github.com/docker/infrakit/pkg/spi/group"
 	"github.com/docker/infrakit/pkg/spi/instance"
@@ -32,16 +33,57 @@ type groupSettings struct {
 type groupContext struct {
 	settings   groupSettings
 	supervisor Supervisor
+	healer     *healer
 	scaled     *scaledGroup
 	update     updatePlan
+	rollback   *group.Rollback
 	lock       sync.RWMutex
 }
 
+// canary returns the canary gate of the update in progress.  It returns nil if there is no update
+// or the update does not support canaries.
+func (c *groupContext) canary() *canaryGate {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if u, is := c.update.(canaryUpdate); is {
+		return u.canary()
+	}
+	return nil
+}
+
+func (c *groupContext) setRollback(rollback *group.Rollback) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.rollback = rollback
+}
+
+func (c *groupContext) lastRollback() *group.Rollback {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.rollback
+}
+
+func (c *groupContext) currentSettings() groupSettings {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.settings
+}
+
 func (c *groupContext) setUpdate(plan updatePlan) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
 	c.update = plan
+
+	id := string(c.supervisor.ID())
+	metrics.GroupConverged.WithLabelValues(id).Set(metrics.Bool(plan == nil))
+	if plan == nil {
+		metrics.GroupUpdateRemaining.WithLabelValues(id).Set(0)
+	}
 }
 
 func (c *groupContext) updating() bool {
@@ -106,6 +106,29 @@ type Spec struct {
 	Flavor     FlavorPlugin
 	Allocation group.AllocationMethod
 	Updating   Updating
+	Healing    *HealingPolicy `json:",omitempty"`
+}
+
+// HealingPolicy defines when an instance that the flavor reports as Unhealthy is drained and destroyed so
+// that it is replaced.  If Duration is set then the instance must be Unhealthy for at least the specified
+// time.  If Count is set then the instance must be Unhealthy for the specified number of consecutive polls.
+// Only one of Duration and Count may be set.
+//
+// MaxParallel is the number of instances that may be replaced at the same time.  Instances missing from
+// the group count as being replaced, so healing waits for the replacements to be created before destroying
+// more instances.  This keeps a flaky health check from destroying the whole group.
+type HealingPolicy struct {
+	Duration    types.Duration
+	Count       int
+	MaxParallel int `json:",omitempty"`
+}
+
+// Parallel returns the number of instances that may be replaced at the same time. Default is 1.
+func (h HealingPolicy) Parallel() int {
+	if h.MaxParallel < 1 {
+		return 1
+	}
+	return h.MaxParallel
 }
 
 // Updating is the configuration schema using on a rolling update and defines how long
@@ -113,10 +136,80 @@ type Spec struct {
 // node must be healthy for at least the specified time. If Count is set then the
 // node must be healthy for specified number of poll intervals. Both Duration and Count
 // cannot be non 0.
+//
+// MaxUnavailable is the number of undesired instances that are destroyed in each batch
+// of the update.  MaxSurge is the number of replacement instances that are provisioned
+// above the target size before any undesired instances are destroyed.  When both are 0,
+// instances are updated one at a time.  MaxSurge is only supported for groups that are
+// allocated by Size.
+//
+// If Rollback is set, the update is rolled back to the previous configuration when the
+// new instances fail to become healthy.  If Canary is set, the update pauses after updating
+// the canary instances until it is promoted or aborted.
 type Updating struct {
 	Duration                  types.Duration
 	Count                     int
+	MaxUnavailable            int `json:",omitempty"`
+	MaxSurge                  int `json:",omitempty"`
 	SkipBeforeInstanceDestroy *SkipBeforeInstanceDestroy
+	Rollback                  *RollbackPolicy `json:",omitempty"`
+	Canary                    *CanaryPolicy   `json:",omitempty"`
+}
+
+// CanaryPolicy defines how many instances are updated before an update pauses for validation.
+// Only one of Count and Percentage may be set.
+type CanaryPolicy struct {
+	// Count is the number of canary instances
+	Count int
+
+	// Percentage is the percentage of the group size to use as canary instances
+	Percentage int
+
+	// Promoted is the config SHA of the update that has been promoted.  It is recorded with the spec
+	// when the update is promoted so that the update is not paused again when it is planned anew.
+	Promoted string `json:",omitempty"`
+}
+
+// Size returns the number of canary instances for a group of the given size.  There is always
+// at least one canary instance.
+func (c CanaryPolicy) Size(groupSize int) int {
+	size := c.Count
+	if size == 0 {
+		size = (groupSize*c.Percentage + 99) / 100
+	}
+	if size < 1 {
+		size = 1
+	}
+	if size > groupSize {
+		size = groupSize
+	}
+	return size
+}
+
+// RollbackPolicy defines when a rolling update is considered failed and is automatically rolled back.
+type RollbackPolicy struct {
+	// Deadline is how long a new instance may remain Unhealthy or Unknown before it is considered failed.
+	Deadline types.Duration
+
+	// FailureThreshold is the number of failed new instances that triggers the rollback. Default is 1.
+	FailureThreshold int
+}
+
+// Threshold returns the number of failed instances that triggers the rollback.
+func (r RollbackPolicy) Threshold() int {
+	if r.FailureThreshold < 1 {
+		return 1
+	}
+	return r.FailureThreshold
+}
+
+// BatchSize returns the number of undesired instances to destroy at a time.
+func (u Updating) BatchSize() int {
+	size := u.MaxUnavailable + u.MaxSurge
+	if size < 1 {
+		return 1
+	}
+	return size
 }
 
 // // AllocationMethod defines the type of allocation and supervision needed by a flavor's Group.
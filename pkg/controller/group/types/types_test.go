@@ -90,3 +90,11 @@ func VerifyValidCharsInHash(t *testing.T, hash string) {
 	validString := regexp.MustCompile(regex)
 	require.True(t, validString.MatchString(hash), fmt.Sprintf("Invalid characters found in string: %v. Valid characters are %v", hash, regex))
 }
+
+func TestCanaryPolicySize(t *testing.T) {
+	require.Equal(t, 2, CanaryPolicy{Count: 2}.Size(10))
+	require.Equal(t, 1, CanaryPolicy{}.Size(10))
+	require.Equal(t, 3, CanaryPolicy{Percentage: 25}.Size(10))
+	require.Equal(t, 1, CanaryPolicy{Percentage: 1}.Size(10))
+	require.Equal(t, 3, CanaryPolicy{Count: 5}.Size(3))
+}
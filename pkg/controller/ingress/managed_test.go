@@ -28,6 +28,10 @@ func (l fakeLeadershipT) LeaderLocation() (*url.URL, error) {
 	return nil, nil
 }
 
+func (l fakeLeadershipT) StepDown(successor string) error {
+	return nil
+}
+
 func TestManagedStartStop(t *testing.T) {
 
 	ticker := make(chan time.Time, 1)
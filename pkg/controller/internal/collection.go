@@ -14,6 +14,7 @@ import (
 	"github.com/docker/infrakit/pkg/spi/event"
 	"github.com/docker/infrakit/pkg/spi/instance"
 	"github.com/docker/infrakit/pkg/spi/metadata"
+	"github.com/docker/infrakit/pkg/store"
 	"github.com/docker/infrakit/pkg/template"
 	"github.com/docker/infrakit/pkg/types"
 )
@@ -47,6 +48,19 @@ type Item struct {
 	Ordinal int
 	State   stateMachine
 	Data    map[string]interface{} `json:",omitempty"`
+	History []string               `json:",omitempty"`
+}
+
+// copy returns a copy of the item with a copy of its data
+func (i Item) copy() Item {
+	if i.Data != nil {
+		data := map[string]interface{}{}
+		for k, v := range i.Data {
+			data[k] = v
+		}
+		i.Data = data
+	}
+	return i
 }
 
 // Error associates an error
@@ -88,6 +102,17 @@ type Collection struct {
 	// This is not the same as Stop, which stops monitoring.
 	TerminateFunc func() error `json:"-"`
 
+	// SetFunc returns the set of state machines of the items.  Optional, for saving and restoring the items.
+	SetFunc func() *fsm.Set `json:"-"`
+
+	// StateStore is where the state of the items is saved on the transitions of their state machines,
+	// every StateSaveInterval and when the collection is paused or stopped, so it can be restored on start.
+	// The state is not saved if nil.
+	StateStore store.Snapshot `json:"-"`
+
+	// StateSaveInterval is how often the state is saved, in addition to the transitions.  Not periodic if 0.
+	StateSaveInterval time.Duration `json:"-"`
+
 	types.Spec
 
 	previous *types.Spec
@@ -97,6 +122,10 @@ type Collection struct {
 
 	stop chan struct{}
 
+	saves  chan struct{} // signals the saver that the state changed
+	saving chan struct{} // closed to stop the saver
+	saved  chan struct{} // closed when the saver stopped
+
 	scope scope.Scope
 
 	running bool
@@ -127,6 +156,9 @@ var (
 
 	// TopicCollectionGone is the topic to get collection gones
 	TopicCollectionGone = types.PathFromString("collection/gone")
+
+	// TopicCollectionTransition is the topic to get the state transitions of the items
+	TopicCollectionTransition = types.PathFromString("collection/transition")
 )
 
 // Topic returns a topic suitable for the events in this collection
@@ -147,6 +179,7 @@ func NewCollection(scope scope.Scope, topics ...types.Path) (*Collection, error)
 		items:           map[string]*Item{},
 		metadataUpdates: make(chan func(map[string]interface{})),
 		stop:            make(chan struct{}),
+		saves:           make(chan struct{}, 1),
 		topics:          map[string]interface{}{},
 		events:          make(chan *event.Event),
 	}
@@ -158,6 +191,7 @@ func NewCollection(scope scope.Scope, topics ...types.Path) (*Collection, error)
 		TopicMetadataGone,
 		TopicCollectionUpdate,
 		TopicCollectionGone,
+		TopicCollectionTransition,
 	}, topics...) {
 		types.Put(topic, stub, c.topics)
 	}
@@ -203,9 +237,10 @@ func (c *Collection) List(topic types.Path) ([]string, error) {
 
 // PublishOn sets the channel to publish on
 func (c *Collection) PublishOn(events chan<- *event.Event) {
+	in := c.events
 	go func() {
 		for {
-			evt, ok := <-c.events
+			evt, ok := <-in
 			if !ok {
 				close(events)
 				return
@@ -218,6 +253,43 @@ func (c *Collection) PublishOn(events chan<- *event.Event) {
 	return
 }
 
+// PublishTransitions publishes the transitions of the items' state machines in the set under
+// the collection's topic.  The set must be the one returned by SetFunc.
+func (c *Collection) PublishTransitions(set *fsm.Set) {
+	transitions := make(chan *event.Event)
+	set.PublishOn(transitions)
+
+	go func() {
+		for {
+			evt, ok := <-transitions
+			if !ok {
+				return
+			}
+
+			var t fsm.Transition
+			if err := evt.Data.Decode(&t); err != nil {
+				log.Error("Cannot decode transition", "event", evt, "err", err)
+				continue
+			}
+
+			key := evt.ID
+			if item := c.GetByID(t.ID); item != nil {
+				key = item.Key
+			}
+			evt.Topic = c.Topic(TopicCollectionTransition)
+			evt.ID = c.EventID(key)
+
+			c.lock.RLock()
+			if c.events != nil {
+				c.events <- evt
+			}
+			c.lock.RUnlock()
+
+			c.StateChanged()
+		}
+	}()
+}
+
 // MetadataGone removes the object in the metadata plugin interface
 func (c *Collection) MetadataGone(key func(instance.Description) (string, error), v []instance.Description) {
 	c.metadataUpdates <- func(view map[string]interface{}) {
@@ -403,9 +475,28 @@ func (c *Collection) GetCountByState(state fsm.Index) (count int) {
 
 // GetByFSM returns an item by the state machine
 func (c *Collection) GetByFSM(f fsm.FSM) (item *Item) {
+	return c.GetByID(f.ID())
+}
+
+// Copy returns a copy of the item of the key, or nil if there's no item of the key.  The data of the
+// copy is copied too, so it's safe to read while the item is updated.
+func (c *Collection) Copy(k string) *Item {
+	c.itemsLock.RLock()
+	defer c.itemsLock.RUnlock()
+
+	item, has := c.items[k]
+	if !has {
+		return nil
+	}
+	copy := item.copy()
+	return &copy
+}
+
+// GetByID returns a copy of the item by the id of its state machine
+func (c *Collection) GetByID(id fsm.ID) (item *Item) {
 	c.Visit(func(i Item) bool {
-		if i.State.ID() == f.ID() {
-			copy := i
+		if i.State.FSM != nil && i.State.ID() == id {
+			copy := i.copy()
 			item = &copy
 			return false
 		}
@@ -473,6 +564,9 @@ func (c *Collection) start() {
 	c.StartFunc(ctx)
 
 	c.running = true
+
+	// after the StartFunc so the state restored is not overwritten
+	c.startSaving()
 }
 
 // Running returns true if managed is running
@@ -490,6 +584,14 @@ func (c *Collection) Stop() error {
 
 	log.Debug("Stop", "V", debugV)
 
+	c.stopSaving()
+
+	// stopped before the channels are closed, since the processing may still publish
+	var err error
+	if c.StopFunc != nil {
+		err = c.StopFunc()
+	}
+
 	if c.metadataUpdates != nil {
 		close(c.metadataUpdates)
 		c.metadataUpdates = nil
@@ -500,11 +602,7 @@ func (c *Collection) Stop() error {
 		c.events = nil
 	}
 
-	if c.StopFunc == nil {
-		return nil
-	}
-
-	return c.StopFunc()
+	return err
 }
 
 func (c *Collection) writeTxn(txn func() error) error {
@@ -596,6 +694,7 @@ func (c *Collection) Inspect() (object *types.Object, err error) {
 // Pause pauses the collection from monitoring and reconciling. This is temporary compared to Stop.
 func (c *Collection) Pause() (object *types.Object, err error) {
 	err = c.writeTxn(func() error {
+		c.stopSaving()
 		if c.PauseFunc != nil {
 			c.PauseFunc(true)
 		}
@@ -627,6 +726,17 @@ func (c *Collection) Terminate() (object *types.Object, err error) {
 
 func (c *Collection) snapshot() (*types.Any, error) {
 
+	// the histories are taken from one snapshot of the set before the items are locked, since the set
+	// runs the actions that update the items
+	histories := map[fsm.ID][]fsm.Transition{}
+	if c.SetFunc != nil {
+		if set := c.SetFunc(); set != nil {
+			for _, m := range set.Snapshot().Members {
+				histories[m.ID] = m.History
+			}
+		}
+	}
+
 	c.itemsLock.RLock()
 	defer c.itemsLock.RUnlock()
 
@@ -634,12 +744,33 @@ func (c *Collection) snapshot() (*types.Any, error) {
 
 	for _, item := range c.items {
 		obj := *item
+		if item.State.FSM != nil && item.State.Spec != nil {
+			for _, t := range histories[item.State.ID()] {
+				obj.History = append(obj.History, item.State.Describe(t))
+			}
+		}
 		view = append(view, obj)
 	}
 
 	return types.AnyValue(view)
 }
 
+// Update calls the function with the item of the key while holding the lock of the items, so the
+// data of the item is changed safely while the collection is inspected or saved.  The function must
+// not call other methods of the collection.  It returns false if there's no item of the key.
+func (c *Collection) Update(k string, f func(*Item)) bool {
+
+	c.itemsLock.Lock()
+	defer c.itemsLock.Unlock()
+
+	item, has := c.items[k]
+	if !has {
+		return false
+	}
+	f(item)
+	return true
+}
+
 // Visit visits the items managed in this collection.
 func (c *Collection) Visit(v func(Item) bool) {
 
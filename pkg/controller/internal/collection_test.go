@@ -0,0 +1,141 @@
+package internal // import "github.com/docker/infrakit/pkg/controller/internal"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionTransitions(t *testing.T) {
+
+	const (
+		requested fsm.Index = iota
+		ready
+
+		found fsm.Signal = iota
+	)
+
+	spec, err := fsm.Define(
+		fsm.State{
+			Index: requested,
+			Transitions: map[fsm.Signal]fsm.Index{
+				found: ready,
+			},
+		},
+		fsm.State{
+			Index: ready,
+		},
+	)
+	require.NoError(t, err)
+	spec.SetStateNames(map[fsm.Index]string{requested: "requested", ready: "ready"}).
+		SetSignalNames(map[fsm.Signal]string{found: "found"})
+
+	set := fsm.NewSet(spec, fsm.NewClock())
+	defer set.Stop()
+
+	c, err := NewCollection(nil)
+	require.NoError(t, err)
+	c.Spec = types.Spec{Metadata: types.Metadata{Name: "workers"}}
+	c.SetFunc = func() *fsm.Set { return set }
+
+	events := make(chan *event.Event, 10)
+	c.PublishOn(events)
+	c.PublishTransitions(set)
+
+	c.Put("workers_0000", set.Add(requested), spec, nil)
+	require.Equal(t, c.Topic(TopicCollectionUpdate), (<-events).Topic)
+
+	require.NoError(t, c.Get("workers_0000").State.Signal(found))
+
+	transition := <-events
+	require.Equal(t, types.PathFromString("workers/collection/transition"), transition.Topic)
+	require.Equal(t, "workers/workers_0000", transition.ID)
+	require.Equal(t, "0: requested + found -> ready", transition.Message)
+
+	// the history is in the state of the collection
+	time.Sleep(100 * time.Millisecond)
+	object, err := c.Inspect()
+	require.NoError(t, err)
+
+	items := []struct {
+		Key     string
+		State   string
+		History []string
+	}{}
+	require.NoError(t, object.State.Decode(&items))
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "ready", items[0].State)
+	require.Equal(t, []string{"0: requested + found -> ready"}, items[0].History)
+}
+
+func TestCollectionInspectWhileActionUpdates(t *testing.T) {
+
+	const (
+		requested fsm.Index = iota
+		ready
+
+		found fsm.Signal = iota
+	)
+
+	var c *Collection
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	spec, err := fsm.Define(
+		fsm.State{
+			Index: requested,
+			Transitions: map[fsm.Signal]fsm.Index{
+				found: ready,
+			},
+			Actions: map[fsm.Signal]fsm.Action{
+				found: func(fsm.FSM) error {
+					close(started)
+					<-release
+					// the action updates the item, as done by the controllers
+					c.Update("workers_0000", func(item *Item) { item.Data["found"] = true })
+					return nil
+				},
+			},
+		},
+		fsm.State{
+			Index: ready,
+		},
+	)
+	require.NoError(t, err)
+
+	set := fsm.NewSet(spec, fsm.NewClock())
+	defer set.Stop()
+
+	c, err = NewCollection(nil)
+	require.NoError(t, err)
+	c.Spec = types.Spec{Metadata: types.Metadata{Name: "workers"}}
+	c.SetFunc = func() *fsm.Set { return set }
+
+	events := make(chan *event.Event, 10)
+	c.PublishOn(events)
+
+	c.Put("workers_0000", set.Add(requested), spec, nil)
+	require.NoError(t, c.Get("workers_0000").State.Signal(found))
+	<-started
+
+	inspected := make(chan error)
+	go func() {
+		_, err := c.Inspect()
+		inspected <- err
+	}()
+
+	// the action updates the item while the collection is inspected
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-inspected:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "inspect deadlocked with the action")
+	}
+}
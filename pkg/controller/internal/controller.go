@@ -3,6 +3,7 @@ package internal // import "github.com/docker/infrakit/pkg/controller/internal"
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/docker/infrakit/pkg/spi/controller"
 	"github.com/docker/infrakit/pkg/spi/event"
@@ -171,6 +172,12 @@ func (c *Controller) Controllers() (map[string]controller.Controller, error) {
 func (c *Controller) Plan(operation controller.Operation,
 	spec types.Spec) (object types.Object, plan controller.Plan, err error) {
 
+	// exclusive since the managed object is allocated if it doesn't exist
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, existed := c.managed[c.keyfunc(spec.Metadata)]
+
 	m := []**Managed{}
 	copy := spec
 	m, err = c.getManaged(&spec.Metadata, &copy)
@@ -188,7 +195,13 @@ func (c *Controller) Plan(operation controller.Operation,
 		object = *o
 	}
 	if p != nil {
-		plan = controller.Plan{}
+		plan = *p
+	}
+	if plan.Before == nil && existed && o != nil {
+		plan.Before = &o.Spec
+	}
+	if plan.After == nil && operation == controller.Enforce {
+		plan.After = &spec
 	}
 	err = e
 	return
@@ -341,3 +354,48 @@ func (c *Controller) Free(search *types.Metadata) (objects []types.Object, err e
 	}
 	return
 }
+
+func (c *Controller) leaser(search types.Metadata) (Leaser, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	m, err := c.getManaged(&search, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(m) != 1 {
+		return nil, fmt.Errorf("not found: %v", search.Name)
+	}
+	leaser, is := (**m[0]).(Leaser)
+	if !is {
+		return nil, controller.ErrLeasingNotSupported
+	}
+	return leaser, nil
+}
+
+// Claim leases an idle resource of the object matching the search to the consumer for the given duration.
+func (c *Controller) Claim(search types.Metadata, consumer string, ttl time.Duration) (controller.Lease, error) {
+	leaser, err := c.leaser(search)
+	if err != nil {
+		return controller.Lease{}, err
+	}
+	return leaser.Claim(consumer, ttl)
+}
+
+// Release ends the lease with the given id
+func (c *Controller) Release(search types.Metadata, id string) error {
+	leaser, err := c.leaser(search)
+	if err != nil {
+		return err
+	}
+	return leaser.Release(id)
+}
+
+// Leases returns the active leases of the object matching the search
+func (c *Controller) Leases(search types.Metadata) ([]controller.Lease, error) {
+	leaser, err := c.leaser(search)
+	if err != nil {
+		return nil, err
+	}
+	return leaser.Leases()
+}
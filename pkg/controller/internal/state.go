@@ -0,0 +1,186 @@
+package internal // import "github.com/docker/infrakit/pkg/controller/internal"
+
+import (
+	"sync"
+	"time"
+
+	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+// savedItem is an item in the saved state of the collection
+type savedItem struct {
+	Key     string
+	Ordinal int
+	FSM     fsm.ID
+	Data    *types.Any `json:",omitempty"`
+}
+
+// savedState is the state of the collection that is saved in the store
+type savedState struct {
+	Name  string
+	Set   fsm.Snapshot
+	Items []savedItem
+}
+
+// stateLock serializes the saves of the collections, since the collections of a controller share the store
+// and the state of each collection is saved by its name in the map of the saved states.
+var stateLock sync.Mutex
+
+// persisted returns true if the state of the collection is saved
+func (c *Collection) persisted() bool {
+	return c.StateStore != nil && c.SetFunc != nil
+}
+
+// save saves the items of the named collection and the state of their state machines, if the state is persisted.
+// This doesn't take the lock of the collection so it can be called by the saver while the collection is paused.
+func (c *Collection) save(name string) error {
+	if !c.persisted() || name == "" {
+		return nil
+	}
+	set := c.SetFunc()
+	if set == nil {
+		return nil
+	}
+
+	saved := savedState{
+		Name: name,
+		Set:  set.Snapshot(),
+	}
+	var err error
+	c.Visit(func(item Item) bool {
+		if item.State.FSM == nil {
+			return true
+		}
+		// marshaled while the items are locked since the data is updated concurrently
+		data, e := types.AnyValue(item.Data)
+		if e != nil {
+			err = e
+			return false
+		}
+		saved.Items = append(saved.Items, savedItem{
+			Key:     item.Key,
+			Ordinal: item.Ordinal,
+			FSM:     item.State.ID(),
+			Data:    data,
+		})
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	stateLock.Lock()
+	defer stateLock.Unlock()
+
+	states := map[string]savedState{}
+	if err := c.StateStore.Load(&states); err != nil {
+		return err
+	}
+	states[name] = saved
+
+	log.Debug("Saving state", "name", name, "items", len(saved.Items), "V", debugV)
+	return c.StateStore.Save(states)
+}
+
+// StateChanged signals that the state of the items changed so that the state is saved, without waiting for
+// the save.  This is called on the transitions published by PublishTransitions.
+func (c *Collection) StateChanged() {
+	select {
+	case c.saves <- struct{}{}:
+	default:
+	}
+}
+
+// startSaving starts saving the state every StateSaveInterval and when the state changes, if the state is
+// persisted.  This is called with the collection locked.
+func (c *Collection) startSaving() {
+	if !c.persisted() || c.saving != nil {
+		return
+	}
+
+	name, interval := c.Spec.Metadata.Name, c.StateSaveInterval
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	c.saving, c.saved = stop, done
+
+	go func() {
+		defer close(done)
+
+		var tick <-chan time.Time
+		if interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-stop:
+				return
+			case <-tick:
+			case <-c.saves:
+			}
+			if err := c.save(name); err != nil {
+				log.Error("Cannot save state", "name", name, "err", err)
+			}
+		}
+	}()
+}
+
+// stopSaving stops the saver and saves the state, so that nothing is saved after the collection is paused or
+// stopped.  This is called with the collection locked.
+func (c *Collection) stopSaving() {
+	if c.saving != nil {
+		close(c.saving)
+		<-c.saved
+		c.saving, c.saved = nil, nil
+	}
+	if err := c.save(c.Spec.Metadata.Name); err != nil {
+		log.Error("Cannot save state", "name", c.Spec.Metadata.Name, "err", err)
+	}
+}
+
+// Restore restores the items saved by an earlier run of the collection, with their state machines
+// restored in the set of SetFunc.  This is called by the StartFunc after the set is started and before
+// new items are added.  Nothing is restored if the collection replaces a running one, since the states
+// are then no longer current.  It returns the number of items restored.
+func (c *Collection) Restore(spec *fsm.Spec) (int, error) {
+	if c.previous != nil || !c.persisted() {
+		return 0, nil
+	}
+
+	states := map[string]savedState{}
+	if err := c.StateStore.Load(&states); err != nil {
+		return 0, err
+	}
+	saved, has := states[c.Spec.Metadata.Name]
+	if !has || len(saved.Items) == 0 {
+		return 0, nil
+	}
+
+	set := c.SetFunc()
+	if err := set.Restore(saved.Set); err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for _, s := range saved.Items {
+		f := set.Get(s.FSM)
+		if f == nil {
+			log.Warn("No state machine for item", "key", s.Key, "fsm", s.FSM)
+			continue
+		}
+		data := map[string]interface{}{}
+		if s.Data != nil {
+			if err := s.Data.Decode(&data); err != nil {
+				return restored, err
+			}
+		}
+		item := c.Put(s.Key, f, spec, data)
+		item.Ordinal = s.Ordinal
+		restored++
+	}
+
+	log.Info("Restored state", "name", saved.Name, "items", restored)
+	return restored, nil
+}
@@ -0,0 +1,191 @@
+package internal // import "github.com/docker/infrakit/pkg/controller/internal"
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/docker/infrakit/pkg/spi/event"
+	file_store "github.com/docker/infrakit/pkg/store/file"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionSaveRestore(t *testing.T) {
+
+	const (
+		requested fsm.Index = iota
+		ready
+
+		found fsm.Signal = iota
+	)
+
+	spec, err := fsm.Define(
+		fsm.State{
+			Index: requested,
+			Transitions: map[fsm.Signal]fsm.Index{
+				found: ready,
+			},
+		},
+		fsm.State{
+			Index: ready,
+		},
+	)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "infrakit-collection-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := file_store.NewSnapshot(dir, "workers.state")
+	require.NoError(t, err)
+
+	newCollection := func(set *fsm.Set) *Collection {
+		c, err := NewCollection(nil)
+		require.NoError(t, err)
+		c.Spec = types.Spec{Metadata: types.Metadata{Name: "workers"}}
+		c.StateStore = store
+		c.SetFunc = func() *fsm.Set { return set }
+
+		events := make(chan *event.Event)
+		go func() {
+			for range events {
+			}
+		}()
+		c.PublishOn(events)
+		return c
+	}
+
+	set1 := fsm.NewSet(spec, fsm.NewClock())
+	defer set1.Stop()
+
+	c1 := newCollection(set1)
+	for i, k := range []string{"workers_0000", "workers_0001"} {
+		item := c1.Put(k, set1.Add(requested), spec, map[string]interface{}{"key": k})
+		item.Ordinal = i
+	}
+	require.NoError(t, c1.Get("workers_0001").State.Signal(found))
+
+	// pausing, as on losing leadership, saves the state
+	_, err = c1.Pause()
+	require.NoError(t, err)
+
+	set2 := fsm.NewSet(spec, fsm.NewClock())
+	defer set2.Stop()
+
+	c2 := newCollection(set2)
+	restored, err := c2.Restore(spec)
+	require.NoError(t, err)
+	require.Equal(t, 2, restored)
+
+	require.Equal(t, 2, set2.Size())
+	require.Equal(t, 1, c2.GetCountByState(requested))
+	require.Equal(t, 1, c2.GetCountByState(ready))
+
+	item := c2.Get("workers_0001")
+	require.NotNil(t, item)
+	require.Equal(t, 1, item.Ordinal)
+	require.Equal(t, ready, item.State.State())
+	require.Equal(t, "workers_0001", item.Data["key"])
+
+	// a collection replacing a running one doesn't restore
+	set3 := fsm.NewSet(spec, fsm.NewClock())
+	defer set3.Stop()
+
+	c3 := newCollection(set3)
+	c3.SetPrevSpec(c1.CurrentSpec())
+	restored, err = c3.Restore(spec)
+	require.NoError(t, err)
+	require.Equal(t, 0, restored)
+
+	// nothing is saved or restored without a state store
+	c4 := newCollection(set3)
+	c4.StateStore = nil
+	restored, err = c4.Restore(spec)
+	require.NoError(t, err)
+	require.Equal(t, 0, restored)
+}
+
+func TestCollectionSavesOnTransitions(t *testing.T) {
+
+	const (
+		requested fsm.Index = iota
+		ready
+
+		found fsm.Signal = iota
+	)
+
+	spec, err := fsm.Define(
+		fsm.State{
+			Index: requested,
+			Transitions: map[fsm.Signal]fsm.Index{
+				found: ready,
+			},
+		},
+		fsm.State{
+			Index: ready,
+		},
+	)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "infrakit-collection-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := file_store.NewSnapshot(dir, "controller.state")
+	require.NoError(t, err)
+
+	set := fsm.NewSet(spec, fsm.NewClock())
+	defer set.Stop()
+
+	c, err := NewCollection(nil)
+	require.NoError(t, err)
+	c.Spec = types.Spec{Metadata: types.Metadata{Name: "workers"}}
+	c.StateStore = store
+	c.SetFunc = func() *fsm.Set { return set }
+	c.StartFunc = func(ctx context.Context) { c.PublishTransitions(set) }
+
+	events := make(chan *event.Event)
+	go func() {
+		for range events {
+		}
+	}()
+	c.PublishOn(events)
+	c.Start()
+
+	item := c.Put("workers_0000", set.Add(requested), spec, map[string]interface{}{"key": "workers_0000"})
+
+	// saved on the transition, while the collection is still running
+	require.NoError(t, item.State.Signal(found))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		states := map[string]savedState{}
+		require.NoError(t, store.Load(&states))
+		if saved, has := states["workers"]; has && len(saved.Items) == 1 {
+			if members := saved.Set.Members; len(members) == 1 && members[0].State == ready {
+				break
+			}
+		}
+		require.True(t, time.Now().Before(deadline), "state not saved")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// saved while updated
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			c.Update("workers_0000", func(item *Item) { item.Data["count"] = i })
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, c.save("workers"))
+	}
+	<-done
+
+	require.NoError(t, c.Stop())
+}
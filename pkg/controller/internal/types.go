@@ -1,6 +1,8 @@
 package internal // import "github.com/docker/infrakit/pkg/controller/internal"
 
 import (
+	"time"
+
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/spi/controller"
 	"github.com/docker/infrakit/pkg/spi/event"
@@ -40,3 +42,10 @@ type Managed interface {
 	Free() (*types.Object, error)
 	Terminate() (*types.Object, error)
 }
+
+// Leaser is implemented by managed objects whose resources can be leased to consumers
+type Leaser interface {
+	Claim(consumer string, ttl time.Duration) (controller.Lease, error)
+	Release(id string) error
+	Leases() ([]controller.Lease, error)
+}
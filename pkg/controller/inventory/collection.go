@@ -6,6 +6,7 @@ import (
 
 	"github.com/docker/infrakit/pkg/controller/internal"
 	inventory "github.com/docker/infrakit/pkg/controller/inventory/types"
+	"github.com/docker/infrakit/pkg/fsm"
 	"github.com/docker/infrakit/pkg/run/scope"
 	"github.com/docker/infrakit/pkg/spi/event"
 	"github.com/docker/infrakit/pkg/spi/instance"
@@ -71,6 +72,9 @@ func newCollection(scope scope.Scope, options inventory.Options) (internal.Manag
 	base.StartFunc = c.run
 	base.StopFunc = c.stop
 	base.UpdateSpecFunc = c.updateSpec
+	base.SetFunc = c.set
+	base.StateStore = options.StateStore
+	base.StateSaveInterval = options.StateSaveInterval.Duration()
 
 	return c, nil
 }
@@ -185,6 +189,14 @@ func (c *collection) run(ctx context.Context) {
 	// Start the model
 	c.model.Start()
 
+	// Continue from the state saved by a previous run, if any
+	if _, err := c.Restore(c.model.Spec()); err != nil {
+		log.Error("Cannot restore state", "err", err)
+	}
+
+	// Publish the transitions under the collection's topic
+	c.PublishTransitions(c.model.Set())
+
 	// channels that aggregate from all the instance accessors
 	type observation struct {
 		name      string
@@ -326,7 +338,7 @@ func (c *collection) run(ctx context.Context) {
 						log.Error("error getting key", "err", err, "instance", n)
 						break
 					}
-					item := c.Collection.Get(k)
+					item := c.Collection.Copy(k)
 					if item == nil {
 
 						// In this case, the fsm isn't requested.. it's something we get out of band
@@ -357,7 +369,7 @@ func (c *collection) run(ctx context.Context) {
 
 					log.Debug("found", "instance", n, "name", found.name, "key", k, "V", debugV2)
 					item.State.Signal(resourceFound)
-					item.Data["instance"] = n
+					c.Update(k, func(item *internal.Item) { item.Data["instance"] = n })
 				}
 
 				c.MetadataExport(keyOf(found.name, accessor.KeyOf), export)
@@ -379,6 +391,13 @@ func keyOf(n string, keyof keyofFunc) keyofFunc {
 	}
 }
 
+func (c *collection) set() *fsm.Set {
+	if c.model == nil {
+		return nil
+	}
+	return c.model.Set()
+}
+
 func (c *collection) stop() error {
 	log.Info("stop")
 
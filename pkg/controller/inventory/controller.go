@@ -30,6 +30,7 @@ var (
 		PluginRetryInterval:  types.Duration(1 * time.Second),
 		MinChannelBufferSize: 10,
 		ModelProperties:      DefaultModelProperties,
+		StateSaveInterval:    types.Duration(1 * time.Minute),
 	}
 
 	// DefaultModelProperties is the default properties for the fsm model
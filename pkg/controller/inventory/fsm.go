@@ -6,6 +6,7 @@ import (
 
 	inventory "github.com/docker/infrakit/pkg/controller/inventory/types"
 	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/docker/infrakit/pkg/metrics"
 )
 
 // Model encapsulates the workflow / state machines for provisioning resources
@@ -38,6 +39,14 @@ func (m *Model) New() fsm.FSM {
 	return m.set.Add(found)
 }
 
+// Set returns the set of state machines, or nil if the model isn't started
+func (m *Model) Set() *fsm.Set {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.set
+}
+
 // Spec returns the model description
 func (m *Model) Spec() *fsm.Spec {
 	m.lock.RLock()
@@ -54,6 +63,7 @@ func (m *Model) Start() {
 	if m.set == nil {
 		m.clock.Start()
 		m.set = fsm.NewSet(m.spec, m.clock, fsm.DefaultOptions("inventory"))
+		metrics.RegisterSet("inventory", m.set)
 	}
 }
 
@@ -63,6 +73,7 @@ func (m *Model) Stop() {
 	defer m.lock.Unlock()
 
 	if m.set != nil {
+		metrics.UnregisterSet(m.set)
 		m.set.Stop()
 		m.clock.Stop()
 
@@ -8,6 +8,7 @@ import (
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/run/depends"
 	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/store"
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -75,6 +76,13 @@ type Options struct {
 
 	// ModelProperties capture the config parameters of the workflow model
 	ModelProperties `json:",inline" yaml:",inline"`
+
+	// StateStore is where the state of the collections is saved, so that the controller can continue where
+	// it left off after a restart or a change of leadership.  The state is not saved if nil.
+	StateStore store.Snapshot `json:"-" yaml:"-"`
+
+	// StateSaveInterval is how often the state is saved, in addition to the transitions of the items.
+	StateSaveInterval types.Duration
 }
 
 // Validate validates the controller's options
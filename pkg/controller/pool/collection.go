@@ -3,10 +3,12 @@ package pool // import "github.com/docker/infrakit/pkg/controller/pool"
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/docker/infrakit/pkg/controller/internal"
 	pool "github.com/docker/infrakit/pkg/controller/pool/types"
+	"github.com/docker/infrakit/pkg/fsm"
 	"github.com/docker/infrakit/pkg/run/scope"
 	"github.com/docker/infrakit/pkg/spi/event"
 	"github.com/docker/infrakit/pkg/spi/instance"
@@ -31,6 +33,9 @@ type collection struct {
 
 	resources resources
 
+	// leaseLock serializes the claims and releases of leases
+	leaseLock sync.Mutex
+
 	cancel func()
 }
 
@@ -71,6 +76,9 @@ func newCollection(scope scope.Scope, options pool.Options) (internal.Managed, e
 		TopicDestroyErr,
 		TopicPending,
 		TopicReady,
+		TopicLeaseClaim,
+		TopicLeaseRelease,
+		TopicLeaseExpire,
 	)
 	if err != nil {
 		return nil, err
@@ -84,6 +92,9 @@ func newCollection(scope scope.Scope, options pool.Options) (internal.Managed, e
 	base.StartFunc = c.run
 	base.StopFunc = c.stop
 	base.UpdateSpecFunc = c.updateSpec
+	base.SetFunc = c.set
+	base.StateStore = options.StateStore
+	base.StateSaveInterval = options.StateSaveInterval.Duration()
 	base.TerminateFunc = c.terminate
 
 	return c, nil
@@ -166,6 +177,14 @@ func (c *collection) run(ctx context.Context) {
 	// Start the model
 	c.model.Start()
 
+	// Continue from the state saved by a previous run, if any
+	if _, err := c.Restore(c.model.Spec()); err != nil {
+		log.Error("Cannot restore state", "err", err)
+	}
+
+	// Publish the transitions under the collection's topic
+	c.PublishTransitions(c.model.Set())
+
 	// channels that aggregate from all the instance accessors
 	type observation struct {
 		instances []instance.Description
@@ -208,6 +227,20 @@ func (c *collection) run(ctx context.Context) {
 	c.accessor.Start()
 	log.Debug("accessor started")
 
+	// Release the leases as they expire
+	go func() {
+		ticker := time.NewTicker(c.model.tickSize)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				c.expireLeases(now)
+			}
+		}
+	}()
+
 	go func() {
 
 	loop:
@@ -316,7 +349,7 @@ func (c *collection) run(ctx context.Context) {
 
 								log.Error("Cannot destroy", "err", err)
 								item.State.Signal(terminateError)
-								item.Error(err)
+								c.Update(item.Key, func(item *internal.Item) { item.Error(err) })
 
 								c.EventCh() <- event.Event{
 									Topic:   c.Topic(TopicDestroyErr),
@@ -401,7 +434,7 @@ func (c *collection) run(ctx context.Context) {
 
 							log.Error("Cannot provision", "err", err)
 							item.State.Signal(provisionError)
-							item.Error(err)
+							c.Update(item.Key, func(item *internal.Item) { item.Error(err) })
 
 							c.EventCh() <- event.Event{
 								Topic:   c.Topic(TopicProvisionErr),
@@ -477,8 +510,21 @@ func (c *collection) run(ctx context.Context) {
 						log.Error("error getting key", "err", err, "instance", n)
 						break
 					}
-					item := c.Collection.Get(k)
-					if item == nil {
+					item := c.Collection.Copy(k)
+					lease, labeled := leaseFromTags(k, n)
+					if item == nil && labeled {
+
+						// An instance leased in a previous run, which is no longer part of the pool
+						// but is kept until its lease is released or expires.
+						f := c.model.Leased()
+						item = c.Put(k, f, c.model.Spec(), map[string]interface{}{
+							"instance": n,
+							leaseKey:   lease,
+						})
+
+						export = append(export, n) // export to metadata
+
+					} else if item == nil {
 
 						// In this case, the fsm isn't requested.. it's something we get out of band
 						// that somehow shows up (or from previous runs but now the user has
@@ -507,8 +553,14 @@ func (c *collection) run(ctx context.Context) {
 
 					log.Debug("found", "instance", n, "key", k, "V", debugV2)
 					item.State.Signal(resourceFound)
-					item.Data["instance"] = n
-					item.Error(nil) // clear any previous error if this is from a retry
+					c.Update(k, func(item *internal.Item) {
+						item.Data["instance"] = n
+						item.Error(nil) // clear any previous error if this is from a retry
+					})
+
+					if labeled {
+						c.adoptLease(k, lease)
+					}
 				}
 
 				c.MetadataExport(c.accessor.KeyOf, export)
@@ -521,9 +573,12 @@ func (c *collection) run(ctx context.Context) {
 	// Seed the initial fsm instances for the size of the collection
 	for i := 0; i < c.properties.Count; i++ {
 
-		f := c.model.Requested()
 		k := fmt.Sprintf("%s_%04d", c.spec.Metadata.Name, i)
+		if c.Get(k) != nil {
+			continue // restored
+		}
 
+		f := c.model.Requested()
 		item := c.Put(k, f, c.model.Spec(), nil)
 		item.Ordinal = i
 
@@ -542,6 +597,13 @@ func (c *collection) terminate() error {
 	return nil
 }
 
+func (c *collection) set() *fsm.Set {
+	if c.model == nil {
+		return nil
+	}
+	return c.model.Set()
+}
+
 func (c *collection) stop() error {
 	log.Info("stop")
 
@@ -50,6 +50,7 @@ var (
 		ModelProperties:        DefaultModelProperties,
 		ProvisionDeadline:      types.Duration(1 * time.Second),
 		DestroyDeadline:        types.Duration(1 * time.Second),
+		StateSaveInterval:      types.Duration(1 * time.Minute),
 	}
 
 	// DefaultProperties is the default properties for the controller, this is per collection / commit
@@ -6,6 +6,7 @@ import (
 
 	pool "github.com/docker/infrakit/pkg/controller/pool/types"
 	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/docker/infrakit/pkg/metrics"
 )
 
 // Model encapsulates the workflow / state machines for provisioning resources
@@ -57,11 +58,24 @@ func (m *Model) Requested() fsm.FSM {
 	return m.set.Add(requested)
 }
 
+// Leased adds a new fsm in leased state
+func (m *Model) Leased() fsm.FSM {
+	return m.set.Add(leased)
+}
+
 // Unmatched adds a new fsm in unmatched state
 func (m *Model) Unmatched() fsm.FSM {
 	return m.set.Add(unmatched)
 }
 
+// Set returns the set of state machines, or nil if the model isn't started
+func (m *Model) Set() *fsm.Set {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.set
+}
+
 // Spec returns the model description
 func (m *Model) Spec() *fsm.Spec {
 	m.lock.RLock()
@@ -80,6 +94,7 @@ func (m *Model) Start() {
 
 		log.Info("model starting", "options", m.Options.Options)
 		m.set = fsm.NewSet(m.spec, m.clock, m.Options.Options)
+		metrics.RegisterSet("pool", m.set)
 	}
 }
 
@@ -89,6 +104,7 @@ func (m *Model) Stop() {
 	defer m.lock.Unlock()
 
 	if m.set != nil {
+		metrics.UnregisterSet(m.set)
 		m.set.Stop()
 		m.clock.Stop()
 
@@ -116,6 +132,7 @@ const (
 	terminated
 	throttled
 	terminateThrottled
+	leased
 
 	// Signals
 	resourceFound fsm.Signal = iota
@@ -128,6 +145,8 @@ const (
 	terminateError
 	cleanup
 	throttle // when it's asked to go back to requested state
+	claim
+	release
 )
 
 // BuildModel constructs a workflow model given the configuration blob provided by user in the Properties
@@ -274,6 +293,7 @@ func BuildModel(properties pool.Properties, options pool.Options) (*Model, error
 				resourceLost:  provisioning,
 				resourceFound: ready, // just loops back to self in the ready state
 				terminate:     terminating,
+				claim:         leased,
 			},
 			Actions: map[fsm.Signal]fsm.Action{
 				resourceLost: func(n fsm.FSM) error {
@@ -286,6 +306,21 @@ func BuildModel(properties pool.Properties, options pool.Options) (*Model, error
 				},
 			},
 		},
+		fsm.State{
+			Index: leased,
+			Transitions: map[fsm.Signal]fsm.Index{
+				resourceFound: leased,
+				resourceLost:  terminated,
+				release:       ready,
+				terminate:     terminating,
+			},
+			Actions: map[fsm.Signal]fsm.Action{
+				terminate: func(n fsm.FSM) error {
+					model.instanceDestroyChan <- n
+					return nil
+				},
+			},
+		},
 		fsm.State{
 			Index: cannotProvision,
 			Transitions: map[fsm.Signal]fsm.Index{
@@ -345,6 +380,7 @@ func BuildModel(properties pool.Properties, options pool.Options) (*Model, error
 		unmatched:          "UNMATCHED",
 		terminating:        "TERMINATING",
 		terminated:         "TERMINATED",
+		leased:             "LEASED",
 	}).SetSignalNames(map[fsm.Signal]string{
 		resourceFound:     "resource_found",
 		resourceLost:      "resource_lost",
@@ -356,6 +392,8 @@ func BuildModel(properties pool.Properties, options pool.Options) (*Model, error
 		terminateError:    "terminate_error",
 		dependencyMissing: "dependency_missing",
 		dependencyReady:   "dependency_ready",
+		claim:             "claim",
+		release:           "release",
 	})
 	model.spec = spec
 	return model, nil
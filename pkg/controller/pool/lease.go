@@ -0,0 +1,323 @@
+package pool // import "github.com/docker/infrakit/pkg/controller/pool"
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/docker/infrakit/pkg/controller/internal"
+	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/spi/instance"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+const (
+	// LeaseConsumerLabel is the label used to label the consumer of a leased instance
+	LeaseConsumerLabel = "infrakit_lease_consumer"
+
+	// LeaseExpiresLabel is the label used to label the time (RFC3339) when the lease of an instance expires
+	LeaseExpiresLabel = "infrakit_lease_expires"
+
+	leaseKey = "lease"
+)
+
+var (
+	// TopicLeaseClaim is the topic for instances claimed by consumers
+	TopicLeaseClaim = types.PathFromString("lease/claim")
+
+	// TopicLeaseRelease is the topic for leases released by consumers
+	TopicLeaseRelease = types.PathFromString("lease/release")
+
+	// TopicLeaseExpire is the topic for leases that expired
+	TopicLeaseExpire = types.PathFromString("lease/expire")
+)
+
+// leaseOf returns the lease of the item, if it's leased.  The lease is decoded if the item is restored from
+// a saved state.
+func leaseOf(item internal.Item) (lease controller.Lease, leased bool) {
+	switch v := item.Data[leaseKey].(type) {
+	case nil:
+		return
+	case controller.Lease:
+		return v, true
+	default:
+		any, err := types.AnyValue(v)
+		if err != nil {
+			return
+		}
+		leased = any.Decode(&lease) == nil
+		return
+	}
+}
+
+// leaseFromTags returns the lease recorded in the labels of the instance, if any.
+func leaseFromTags(key string, n instance.Description) (lease controller.Lease, leased bool) {
+	consumer := n.Tags[LeaseConsumerLabel]
+	if consumer == "" {
+		return
+	}
+	expires, err := time.Parse(time.RFC3339, n.Tags[LeaseExpiresLabel])
+	if err != nil {
+		log.Warn("Bad lease expiry", "key", key, "err", err)
+	}
+	return controller.Lease{
+		ID:       key,
+		Consumer: consumer,
+		Expires:  expires,
+		Instance: n,
+	}, true
+}
+
+// idle returns true if the item is counted as an instance available in the pool
+func idle(item internal.Item) bool {
+	if _, has := leaseOf(item); has {
+		return false
+	}
+	switch item.State.State() {
+	case requested, provisioning, waiting, throttled, ready, cannotProvision:
+		return true
+	}
+	return false
+}
+
+// Claim leases a ready instance to the consumer for the given duration.  The pool is replenished
+// so that it has its count of idle instances.
+func (c *collection) Claim(consumer string, ttl time.Duration) (lease controller.Lease, err error) {
+	if consumer == "" {
+		err = fmt.Errorf("no consumer")
+		return
+	}
+	if ttl == 0 {
+		ttl = c.properties.Lease.TTL.Duration()
+	}
+	if ttl <= 0 {
+		err = fmt.Errorf("no lease ttl")
+		return
+	}
+
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+
+	if c.model == nil {
+		err = fmt.Errorf("not running")
+		return
+	}
+
+	// pick the ready instance with the lowest key
+	var found *internal.Item
+	var n instance.Description
+	c.Visit(func(item internal.Item) bool {
+		if item.State.State() != ready {
+			return true
+		}
+		if _, has := leaseOf(item); has {
+			return true
+		}
+		d, is := item.Data["instance"].(instance.Description)
+		if !is {
+			return true
+		}
+		if found == nil || item.Key < found.Key {
+			copy := item
+			found, n = &copy, d
+		}
+		return true
+	})
+	if found == nil {
+		err = fmt.Errorf("no ready instance in %v", c.spec.Metadata.Name)
+		return
+	}
+
+	lease = controller.Lease{
+		ID:       found.Key,
+		Consumer: consumer,
+		Expires:  time.Now().Add(ttl),
+		Instance: n,
+	}
+
+	err = c.accessor.Label(n.ID, map[string]string{
+		LeaseConsumerLabel: consumer,
+		LeaseExpiresLabel:  lease.Expires.Format(time.RFC3339),
+	})
+	if err != nil {
+		return
+	}
+
+	c.Update(found.Key, func(item *internal.Item) { item.Data[leaseKey] = lease })
+	if err = found.State.Signal(claim); err != nil {
+		return
+	}
+
+	log.Info("Claimed", "key", found.Key, "consumer", consumer, "expires", lease.Expires)
+	c.EventCh() <- event.Event{
+		Topic:   c.Topic(TopicLeaseClaim),
+		Type:    event.Type("LeaseClaim"),
+		ID:      c.EventID(found.Key),
+		Message: "instance claimed by " + consumer,
+	}.Init().WithDataMust(lease)
+
+	c.replenish()
+	return
+}
+
+// Release ends the lease with the given id.  The instance is reclaimed or destroyed.
+func (c *collection) Release(id string) error {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+
+	return c.release(id, TopicLeaseRelease)
+}
+
+// Leases returns the current leases, ordered by id
+func (c *collection) Leases() ([]controller.Lease, error) {
+	leases := []controller.Lease{}
+	c.Visit(func(item internal.Item) bool {
+		if lease, has := leaseOf(item); has {
+			leases = append(leases, lease)
+		}
+		return true
+	})
+	sort.Slice(leases, func(i, j int) bool { return leases[i].ID < leases[j].ID })
+	return leases, nil
+}
+
+// release ends the lease.  Caller must hold the lease lock.
+func (c *collection) release(id string, topic types.Path) error {
+	if c.model == nil {
+		return fmt.Errorf("not running")
+	}
+
+	item := c.Copy(id)
+	if item == nil {
+		return fmt.Errorf("no lease %v", id)
+	}
+	lease, has := leaseOf(*item)
+	if !has {
+		return fmt.Errorf("no lease %v", id)
+	}
+
+	idleCount := 0
+	c.Visit(func(item internal.Item) bool {
+		if idle(item) {
+			idleCount++
+		}
+		return true
+	})
+
+	reclaim := c.properties.Lease.Reclaim && idleCount < c.properties.Count
+	if reclaim {
+		err := c.accessor.Label(lease.Instance.ID, map[string]string{
+			LeaseConsumerLabel: "",
+			LeaseExpiresLabel:  "",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	c.Update(id, func(item *internal.Item) { delete(item.Data, leaseKey) })
+
+	var err error
+	if reclaim {
+		err = item.State.Signal(release)
+	} else {
+		err = item.State.Signal(terminate)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Info("Released", "key", id, "consumer", lease.Consumer, "reclaim", reclaim, "topic", topic)
+	c.EventCh() <- event.Event{
+		Topic:   c.Topic(topic),
+		Type:    event.Type("LeaseRelease"),
+		ID:      c.EventID(id),
+		Message: "lease of " + lease.Consumer + " ended",
+	}.Init().WithDataMust(lease)
+
+	c.replenish()
+	return nil
+}
+
+// expireLeases releases the leases that have expired
+func (c *collection) expireLeases(now time.Time) {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+
+	expired := []string{}
+	c.Visit(func(item internal.Item) bool {
+		if lease, has := leaseOf(item); has && !lease.Expires.IsZero() && lease.Expires.Before(now) {
+			expired = append(expired, item.Key)
+		}
+		return true
+	})
+	for _, id := range expired {
+		if err := c.release(id, TopicLeaseExpire); err != nil {
+			log.Error("Cannot expire lease", "key", id, "err", err)
+		}
+	}
+}
+
+// replenish requests new instances until the pool has its count of idle instances.  Caller must hold
+// the lease lock.
+func (c *collection) replenish() {
+	if c.model == nil {
+		return
+	}
+
+	idleCount, next := 0, 0
+	c.Visit(func(item internal.Item) bool {
+		if idle(item) {
+			idleCount++
+		}
+		if item.Ordinal >= next {
+			next = item.Ordinal + 1
+		}
+		return true
+	})
+
+	for ; idleCount < c.properties.Count; idleCount++ {
+		k := fmt.Sprintf("%s_%04d", c.spec.Metadata.Name, next)
+		for c.Get(k) != nil {
+			next++
+			k = fmt.Sprintf("%s_%04d", c.spec.Metadata.Name, next)
+		}
+
+		f := c.model.Requested()
+		item := c.Put(k, f, c.model.Spec(), nil)
+		item.Ordinal = next
+		next++
+
+		log.Debug("replenished", "id", f.ID(), "key", item.Key, "ordinal", item.Ordinal)
+	}
+}
+
+// adoptLease takes the lease recorded in the labels of the instance of the key that's just been found, as
+// when the controller restarts without its saved state.  The pool is then replenished.
+func (c *collection) adoptLease(key string, lease controller.Lease) {
+	c.leaseLock.Lock()
+	defer c.leaseLock.Unlock()
+
+	var adopted *internal.Item
+	c.Update(key, func(item *internal.Item) {
+		if _, has := leaseOf(*item); has {
+			return
+		}
+		switch item.State.State() {
+		case requested, provisioning, waiting, throttled:
+		default:
+			return // the labels may be stale after a release
+		}
+		item.Data[leaseKey] = lease
+		adopted = item
+	})
+	if adopted == nil {
+		return
+	}
+
+	log.Info("Adopting lease", "key", key, "consumer", lease.Consumer, "expires", lease.Expires)
+	adopted.State.Signal(claim) // after resource found, so from ready
+
+	c.replenish()
+}
@@ -0,0 +1,242 @@
+package pool // import "github.com/docker/infrakit/pkg/controller/pool"
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/infrakit/pkg/controller/internal"
+	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/spi/instance"
+	testing_instance "github.com/docker/infrakit/pkg/testing/instance"
+	testing_scope "github.com/docker/infrakit/pkg/testing/scope"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// labels records the labels of the instances
+type labels struct {
+	lock sync.Mutex
+	byID map[instance.ID]map[string]string
+}
+
+func (l *labels) get(id instance.ID) map[string]string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.byID[id]
+}
+
+// testPool returns a pool of count ready instances whose leases are reclaimed or not.  The events of the
+// pool are sent to the channel returned.
+func testPool(t *testing.T, count int, reclaim bool) (*collection, *labels, <-chan *event.Event) {
+	recorded := &labels{byID: map[instance.ID]map[string]string{}}
+	plugin := &testing_instance.Plugin{
+		DoLabel: func(id instance.ID, l map[string]string) error {
+			recorded.lock.Lock()
+			defer recorded.lock.Unlock()
+			recorded.byID[id] = l
+			return nil
+		},
+	}
+	scope := testing_scope.DefaultScope()
+	scope.ResolveInstance = func(n string) (instance.Plugin, error) {
+		return plugin, nil
+	}
+
+	managed, err := newCollection(scope, DefaultOptions)
+	require.NoError(t, err)
+	c := managed.(*collection)
+
+	spec := types.Spec{
+		Kind:     "pool",
+		Metadata: types.Metadata{Name: "workers"},
+		Properties: types.AnyValueMust(map[string]interface{}{
+			"plugin": "test/compute",
+			"Count":  count,
+			"Lease": map[string]interface{}{
+				"TTL":     "1h",
+				"Reclaim": reclaim,
+			},
+		}),
+	}
+	require.NoError(t, c.updateSpec(spec, nil))
+	c.model.Start()
+
+	events := make(chan *event.Event, 1000)
+	c.PublishOn(events)
+
+	for i := 0; i < count; i++ {
+		k := fmt.Sprintf("workers_%04d", i)
+		f := c.model.Requested()
+		item := c.Put(k, f, c.model.Spec(), map[string]interface{}{
+			"instance": instance.Description{ID: instance.ID(k)},
+		})
+		item.Ordinal = i
+		require.NoError(t, f.Signal(resourceFound))
+		waitForState(t, c, k, ready)
+	}
+	return c, recorded, events
+}
+
+// waitForState waits for the item of the key to be in the state, since the signals are processed
+// asynchronously.
+func waitForState(t *testing.T, c *collection, k string, state fsm.Index) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		item := c.Copy(k)
+		require.NotNil(t, item)
+		if item.State.State() == state {
+			return
+		}
+		require.True(t, time.Now().Before(deadline), "%v is %v", k, item.State.State())
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitForEvent returns the next event on the topic
+func waitForEvent(t *testing.T, c *collection, events <-chan *event.Event, topic types.Path) *event.Event {
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case evt := <-events:
+			if evt.Topic.Equal(c.Topic(topic)) {
+				return evt
+			}
+		case <-deadline:
+			require.Fail(t, "no event", "topic %v", topic)
+			return nil
+		}
+	}
+}
+
+func TestClaimReplenishes(t *testing.T) {
+	c, labels, events := testPool(t, 2, false)
+	defer c.model.Stop()
+
+	lease, err := c.Claim("ci", 0)
+	require.NoError(t, err)
+	require.Equal(t, "workers_0000", lease.ID)
+	require.Equal(t, "ci", lease.Consumer)
+	require.Equal(t, instance.ID("workers_0000"), lease.Instance.ID)
+	require.True(t, lease.Expires.After(time.Now().Add(59*time.Minute)))
+
+	require.Equal(t, "ci", labels.get("workers_0000")[LeaseConsumerLabel])
+	require.Equal(t, lease.Expires.Format(time.RFC3339), labels.get("workers_0000")[LeaseExpiresLabel])
+	waitForState(t, c, "workers_0000", leased)
+	waitForEvent(t, c, events, TopicLeaseClaim)
+
+	// the pool is replenished with a new instance requested
+	waitForState(t, c, "workers_0002", requested)
+	require.Equal(t, 2, c.Copy("workers_0002").Ordinal)
+
+	leases, err := c.Leases()
+	require.NoError(t, err)
+	require.Equal(t, []controller.Lease{lease}, leases)
+
+	// the next claim takes the other ready instance
+	next, err := c.Claim("ci", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "workers_0001", next.ID)
+	waitForState(t, c, "workers_0003", requested)
+
+	// no more ready instances
+	_, err = c.Claim("ci", time.Minute)
+	require.Error(t, err)
+
+	_, err = c.Claim("", time.Minute)
+	require.Error(t, err)
+}
+
+func TestReleaseDestroys(t *testing.T) {
+	c, labels, events := testPool(t, 1, false)
+	defer c.model.Stop()
+
+	lease, err := c.Claim("ci", time.Minute)
+	require.NoError(t, err)
+	waitForState(t, c, lease.ID, leased)
+
+	require.NoError(t, c.Release(lease.ID))
+	waitForState(t, c, lease.ID, terminating)
+	waitForEvent(t, c, events, TopicLeaseRelease)
+
+	// not reclaimed so the labels are left as they are until the instance is gone
+	require.Equal(t, "ci", labels.get(instance.ID(lease.ID))[LeaseConsumerLabel])
+
+	leases, err := c.Leases()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(leases))
+
+	require.Error(t, c.Release(lease.ID))
+}
+
+func TestReleaseReclaims(t *testing.T) {
+	c, labels, events := testPool(t, 1, true)
+	defer c.model.Stop()
+
+	lease, err := c.Claim("ci", time.Minute)
+	require.NoError(t, err)
+	waitForState(t, c, lease.ID, leased)
+
+	// the pool has its count of idle instances with the one replenished so the release destroys the instance
+	waitForState(t, c, "workers_0001", requested)
+
+	// without the one replenished, the pool is short and the instance released is reclaimed
+	c.Delete("workers_0001")
+	require.NoError(t, c.Release(lease.ID))
+	waitForState(t, c, lease.ID, ready)
+	waitForEvent(t, c, events, TopicLeaseRelease)
+
+	require.Equal(t, "", labels.get(instance.ID(lease.ID))[LeaseConsumerLabel])
+	require.Equal(t, "", labels.get(instance.ID(lease.ID))[LeaseExpiresLabel])
+	require.Nil(t, c.Copy("workers_0001"))
+
+	// the instance can be claimed again
+	again, err := c.Claim("ci2", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, lease.ID, again.ID)
+}
+
+func TestExpireLeases(t *testing.T) {
+	c, _, events := testPool(t, 2, false)
+	defer c.model.Stop()
+
+	short, err := c.Claim("ci", time.Minute)
+	require.NoError(t, err)
+	long, err := c.Claim("ci", time.Hour)
+	require.NoError(t, err)
+	waitForState(t, c, short.ID, leased)
+	waitForState(t, c, long.ID, leased)
+
+	c.expireLeases(time.Now().Add(30 * time.Minute))
+	waitForState(t, c, short.ID, terminating)
+	evt := waitForEvent(t, c, events, TopicLeaseExpire)
+	require.Equal(t, c.EventID(short.ID), evt.ID)
+
+	leases, err := c.Leases()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(leases))
+	require.Equal(t, long.ID, leases[0].ID)
+}
+
+func TestLeaseOf(t *testing.T) {
+	lease := controller.Lease{ID: "workers_0000", Consumer: "ci", Expires: time.Now().Round(time.Second).UTC()}
+
+	_, leased := leaseOf(internal.Item{Data: map[string]interface{}{}})
+	require.False(t, leased)
+
+	found, leased := leaseOf(internal.Item{Data: map[string]interface{}{leaseKey: lease}})
+	require.True(t, leased)
+	require.Equal(t, lease, found)
+
+	// as restored from a saved state
+	var restored interface{}
+	require.NoError(t, types.AnyValueMust(lease).Decode(&restored))
+	found, leased = leaseOf(internal.Item{Data: map[string]interface{}{leaseKey: restored}})
+	require.True(t, leased)
+	require.Equal(t, lease.ID, found.ID)
+	require.Equal(t, lease.Consumer, found.Consumer)
+	require.True(t, lease.Expires.Equal(found.Expires))
+}
@@ -9,6 +9,7 @@ import (
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/run/depends"
 	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/store"
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -56,6 +57,19 @@ type Properties struct {
 
 	// Count is how many instances of the resource to provision
 	Count int
+
+	// Lease configures the leasing of instances to consumers
+	Lease Lease
+}
+
+// Lease is the configuration of the leases of instances in the pool
+type Lease struct {
+	// TTL is the duration of a lease when the consumer doesn't ask for one
+	TTL types.Duration
+
+	// Reclaim is true to return released or expired instances to the pool, if the pool is below its count,
+	// instead of destroying them.
+	Reclaim bool
 }
 
 // ModelProperties contain fsm tuning parameters
@@ -96,6 +110,13 @@ type Options struct {
 
 	// DestroyDeadline is the deadline for synchronously calling the plugin to destroy
 	DestroyDeadline types.Duration
+
+	// StateStore is where the state of the collections is saved, so that the controller can continue where
+	// it left off after a restart or a change of leadership.  The state is not saved if nil.
+	StateStore store.Snapshot `json:"-" yaml:"-"`
+
+	// StateSaveInterval is how often the state is saved, in addition to the transitions of the items.
+	StateSaveInterval types.Duration
 }
 
 // Validate validates the controller's options
@@ -8,6 +8,7 @@ import (
 
 	"github.com/docker/infrakit/pkg/controller/internal"
 	resource "github.com/docker/infrakit/pkg/controller/resource/types"
+	"github.com/docker/infrakit/pkg/fsm"
 	"github.com/docker/infrakit/pkg/run/scope"
 	"github.com/docker/infrakit/pkg/spi/event"
 	"github.com/docker/infrakit/pkg/spi/instance"
@@ -92,6 +93,9 @@ func newCollection(scope scope.Scope, options resource.Options) (internal.Manage
 	base.StartFunc = c.run
 	base.StopFunc = c.stop
 	base.UpdateSpecFunc = c.updateSpec
+	base.SetFunc = c.set
+	base.StateStore = options.StateStore
+	base.StateSaveInterval = options.StateSaveInterval.Duration()
 	base.TerminateFunc = c.terminate
 
 	return c, nil
@@ -230,6 +234,14 @@ func (c *collection) run(ctx context.Context) {
 	// Start the model
 	c.model.Start()
 
+	// Continue from the state saved by a previous run, if any
+	if _, err := c.Restore(c.model.Spec()); err != nil {
+		log.Error("Cannot restore state", "err", err)
+	}
+
+	// Publish the transitions under the collection's topic
+	c.PublishTransitions(c.model.Set())
+
 	// channels that aggregate from all the instance accessors
 	type observation struct {
 		name      string
@@ -623,7 +635,7 @@ func (c *collection) run(ctx context.Context) {
 						log.Error("error getting key", "err", err, "instance", n)
 						break
 					}
-					item := c.Collection.Get(k)
+					item := c.Collection.Copy(k)
 					if item == nil {
 						// In this case, the fsm isn't requested.. it's something we get out of band
 						// that somehow shows up (or from previous runs but now the user has
@@ -654,7 +666,7 @@ func (c *collection) run(ctx context.Context) {
 
 					log.Debug("found", "instance", n, "name", found.name, "key", k, "V", debugV2)
 					item.State.Signal(resourceFound)
-					item.Data["instance"] = n
+					c.Update(k, func(item *internal.Item) { item.Data["instance"] = n })
 				}
 
 				c.MetadataExport(accessor.KeyOf, export)
@@ -667,6 +679,9 @@ func (c *collection) run(ctx context.Context) {
 	// Seed the initial fsm instances for each named resource in the config
 	// For each accessor / resource we create one fsm
 	for k := range c.accessors {
+		if c.Get(k) != nil {
+			continue // restored
+		}
 		log.Debug("requesting", "key", k)
 		f := c.model.Requested()
 		c.Put(k, f, c.model.Spec(), nil)
@@ -686,6 +701,13 @@ func (c *collection) terminate() error {
 	return nil
 }
 
+func (c *collection) set() *fsm.Set {
+	if c.model == nil {
+		return nil
+	}
+	return c.model.Set()
+}
+
 func (c *collection) stop() error {
 	log.Info("stop")
 
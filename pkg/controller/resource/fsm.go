@@ -6,6 +6,7 @@ import (
 
 	resource "github.com/docker/infrakit/pkg/controller/resource/types"
 	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/docker/infrakit/pkg/metrics"
 )
 
 // Model encapsulates the workflow / state machines for provisioning resources
@@ -62,6 +63,14 @@ func (m *Model) Unmatched() fsm.FSM {
 	return m.set.Add(unmatched)
 }
 
+// Set returns the set of state machines, or nil if the model isn't started
+func (m *Model) Set() *fsm.Set {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.set
+}
+
 // Spec returns the model description
 func (m *Model) Spec() *fsm.Spec {
 	m.lock.RLock()
@@ -80,6 +89,7 @@ func (m *Model) Start() {
 
 		log.Info("model starting", "options", m.Options.Options)
 		m.set = fsm.NewSet(m.spec, m.clock, m.Options.Options)
+		metrics.RegisterSet("resource", m.set)
 	}
 }
 
@@ -89,6 +99,7 @@ func (m *Model) Stop() {
 	defer m.lock.Unlock()
 
 	if m.set != nil {
+		metrics.UnregisterSet(m.set)
 		m.set.Stop()
 		m.clock.Stop()
 
@@ -9,6 +9,7 @@ import (
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/run/depends"
 	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/store"
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -89,6 +90,13 @@ type Options struct {
 
 	// DestroyDeadline is the deadline for synchronously calling the plugin to destroy
 	DestroyDeadline types.Duration
+
+	// StateStore is where the state of the collections is saved, so that the controller can continue where
+	// it left off after a restart or a change of leadership.  The state is not saved if nil.
+	StateStore store.Snapshot `json:"-" yaml:"-"`
+
+	// StateSaveInterval is how often the state is saved, in addition to the transitions of the items.
+	StateSaveInterval types.Duration
 }
 
 // Validate validates the controller's options
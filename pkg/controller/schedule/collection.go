@@ -0,0 +1,319 @@
+package schedule // import "github.com/docker/infrakit/pkg/controller/schedule"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/infrakit/pkg/controller/internal"
+	schedule "github.com/docker/infrakit/pkg/controller/schedule/types"
+	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/run/scope"
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/spi/group"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/imdario/mergo"
+)
+
+const (
+	// States
+	idle fsm.Index = iota
+	scaled
+	failed
+
+	// Signals
+	scale fsm.Signal = iota
+	fail
+	leave
+)
+
+var (
+	// TopicScale is the topic for groups scaled by the schedule
+	TopicScale = types.PathFromString("scale")
+
+	// TopicScaleErr is the topic for errors scaling groups
+	TopicScaleErr = types.PathFromString("error/scale")
+)
+
+type collection struct {
+	*internal.Collection
+
+	properties schedule.Properties
+	options    schedule.Options
+	lock       sync.RWMutex // guards the properties and options
+
+	spec *fsm.Spec
+	set  *fsm.Set
+
+	// applied are the sizes last set by the schedule, by group
+	applied map[plugin.Name]int
+
+	groups     map[string]group.Plugin
+	groupsLock sync.Mutex
+
+	// now returns the current time
+	now func() time.Time
+
+	cancel func()
+	done   chan struct{} // closed when the checks stop
+}
+
+func buildSpec() (*fsm.Spec, error) {
+	spec, err := fsm.Define(
+		fsm.State{
+			Index: idle,
+			Transitions: map[fsm.Signal]fsm.Index{
+				scale: scaled,
+				fail:  failed,
+			},
+		},
+		fsm.State{
+			Index: scaled,
+			Transitions: map[fsm.Signal]fsm.Index{
+				scale: scaled,
+				fail:  failed,
+				leave: idle,
+			},
+		},
+		fsm.State{
+			Index: failed,
+			Transitions: map[fsm.Signal]fsm.Index{
+				scale: scaled,
+				fail:  failed,
+				leave: idle,
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	spec.SetStateNames(map[fsm.Index]string{
+		idle:   "IDLE",
+		scaled: "SCALED",
+		failed: "SCALE_FAILED",
+	}).SetSignalNames(map[fsm.Signal]string{
+		scale: "scale",
+		fail:  "fail",
+		leave: "leave",
+	})
+	return spec, nil
+}
+
+func newCollection(scope scope.Scope, options schedule.Options) (*collection, error) {
+
+	if err := mergo.Merge(&options, DefaultOptions); err != nil {
+		return nil, err
+	}
+
+	if err := options.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	base, err := internal.NewCollection(scope,
+		TopicScale,
+		TopicScaleErr,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := buildSpec()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &collection{
+		Collection: base,
+		options:    options,
+		spec:       spec,
+		groups:     map[string]group.Plugin{},
+		now:        time.Now,
+	}
+	// set the behaviors
+	base.StartFunc = c.run
+	base.StopFunc = c.stop
+	base.UpdateSpecFunc = c.updateSpec
+	base.PlanFunc = c.plan
+	base.SetFunc = c.fsmSet
+	base.TerminateFunc = c.stop
+
+	return c, nil
+}
+
+func (c *collection) decode(spec types.Spec) (properties schedule.Properties, options schedule.Options, err error) {
+	c.lock.RLock()
+	options = c.options // the plugin options at initialization are the defaults
+	c.lock.RUnlock()
+	if spec.Options != nil {
+		if err = spec.Options.Decode(&options); err != nil {
+			return
+		}
+	}
+	if spec.Properties != nil {
+		if err = spec.Properties.Decode(&properties); err != nil {
+			return
+		}
+	}
+	ctx := context.Background()
+	if err = properties.Validate(ctx); err != nil {
+		return
+	}
+	err = options.Validate(ctx)
+	return
+}
+
+func (c *collection) updateSpec(spec types.Spec, previous *types.Spec) (err error) {
+	properties, options, err := c.decode(spec)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("updateSpec", "properties", properties, "options", options, "V", debugV)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.properties = properties
+	c.options = options
+	return nil
+}
+
+func (c *collection) fsmSet() *fsm.Set {
+	return c.set
+}
+
+func (c *collection) run(ctx context.Context) {
+
+	c.set = fsm.NewSet(c.spec, fsm.NewClock(), fsm.Options{Name: "schedule"})
+	c.applied = map[plugin.Name]int{}
+	c.PublishTransitions(c.set)
+
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	c.lock.RLock()
+	interval := c.options.CheckInterval.Duration()
+	c.lock.RUnlock()
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			c.check(c.now())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *collection) stop() error {
+	log.Info("stop")
+
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+
+		// a check in progress uses the set
+		<-c.done
+	}
+	if c.set != nil {
+		c.set.Stop()
+		c.set = nil
+	}
+	return nil
+}
+
+func (c *collection) groupPlugin(name plugin.Name) (group.Plugin, error) {
+	c.groupsLock.Lock()
+	defer c.groupsLock.Unlock()
+
+	lookup, _ := name.GetLookupAndType()
+	if found, has := c.groups[lookup]; has {
+		return found, nil
+	}
+	found, err := c.Scope().Group(lookup)
+	if err != nil {
+		return nil, err
+	}
+	c.groups[lookup] = found
+	return found, nil
+}
+
+// check sets the sizes of the groups whose targets changed since the last check
+func (c *collection) check(now time.Time) {
+	c.lock.RLock()
+	properties := c.properties
+	c.lock.RUnlock()
+
+	targets, err := properties.Targets(now)
+	if err != nil {
+		log.Error("Cannot evaluate rules", "err", err)
+		return
+	}
+
+	for _, target := range targets {
+
+		key := string(target.Group)
+		item := c.Get(key)
+		if item == nil {
+			item = c.Put(key, c.set.Add(idle), c.spec, nil)
+		}
+		c.Update(key, func(item *internal.Item) { item.Data["target"] = target })
+
+		if target.Size < 0 {
+			if _, has := c.applied[target.Group]; has {
+				delete(c.applied, target.Group)
+				item.State.Signal(leave)
+			}
+			continue
+		}
+
+		if size, has := c.applied[target.Group]; has && size == target.Size {
+			continue
+		}
+
+		err := c.setSize(target)
+		c.Update(key, func(item *internal.Item) { item.Error(err) })
+		if err != nil {
+			log.Error("Cannot scale", "group", target.Group, "size", target.Size, "err", err)
+			item.State.Signal(fail)
+
+			c.EventCh() <- event.Event{
+				Topic:   c.Topic(TopicScaleErr),
+				Type:    event.Type("ScaleErr"),
+				ID:      c.EventID(key),
+				Message: "error scaling group",
+			}.Init().WithError(err)
+			continue
+		}
+
+		log.Info("Scaled", "group", target.Group, "size", target.Size, "rule", target.Rule)
+		c.applied[target.Group] = target.Size
+		c.Update(key, func(item *internal.Item) { item.Data["size"] = target.Size })
+		item.State.Signal(scale)
+
+		c.EventCh() <- event.Event{
+			Topic:   c.Topic(TopicScale),
+			Type:    event.Type("Scale"),
+			ID:      c.EventID(key),
+			Message: fmt.Sprintf("scaled group to %d", target.Size),
+		}.Init().WithDataMust(target)
+	}
+}
+
+func (c *collection) setSize(target schedule.Target) error {
+	groupPlugin, err := c.groupPlugin(target.Group)
+	if err != nil {
+		return err
+	}
+	_, gid := target.Group.GetLookupAndType()
+	return groupPlugin.SetSize(group.ID(gid), target.Size)
+}
@@ -0,0 +1,180 @@
+package schedule // import "github.com/docker/infrakit/pkg/controller/schedule"
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	schedule "github.com/docker/infrakit/pkg/controller/schedule/types"
+	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/spi/group"
+	testing_group "github.com/docker/infrakit/pkg/testing/group"
+	testing_scope "github.com/docker/infrakit/pkg/testing/scope"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+kind: schedule
+metadata:
+  name: ci
+properties:
+  Rules:
+    - Name: workhours
+      Group: group/ci
+      Size: 80
+      Start: 0 8 * * 1-5
+      End: 0 18 * * 1-5
+  Defaults:
+    group/ci: 5
+`
+
+func TestScheduleEnforce(t *testing.T) {
+
+	sizes := make(chan int, 10)
+	current := 5
+
+	scope := testing_scope.DefaultScope()
+	scope.ResolveGroup = func(name string) (group.Plugin, error) {
+		require.Equal(t, "group", name)
+		return &testing_group.Plugin{
+			DoSize: func(id group.ID) (int, error) {
+				require.Equal(t, group.ID("ci"), id)
+				return current, nil
+			},
+			DoSetSize: func(id group.ID, size int) error {
+				require.Equal(t, group.ID("ci"), id)
+				if size < 0 {
+					return fmt.Errorf("bad size")
+				}
+				sizes <- size
+				return nil
+			},
+		}, nil
+	}
+
+	c, err := newCollection(scope, schedule.Options{CheckInterval: types.Duration(10 * time.Millisecond)})
+	require.NoError(t, err)
+
+	now := time.Date(2017, time.December, 1, 7, 0, 0, 0, time.UTC) // friday
+	clock := make(chan time.Time, 1)
+	clock <- now
+	c.now = func() time.Time {
+		select {
+		case now = <-clock:
+		default:
+		}
+		return now
+	}
+
+	events := make(chan *event.Event, 100)
+	c.PublishOn(events)
+
+	spec := types.Spec{}
+	require.NoError(t, types.AnyYAMLMust([]byte(testSpec)).Decode(&spec))
+
+	// dry run
+	_, plan, err := c.Plan(controller.Enforce, spec)
+	require.NoError(t, err)
+	require.Equal(t, []controller.Change{
+		{
+			Type:    controller.ChangeUpdate,
+			Step:    1,
+			ID:      "group/ci",
+			Message: "at 2017-12-01T08:00:00Z: set size to 80 by rule workhours",
+		},
+		{
+			Type:    controller.ChangeUpdate,
+			Step:    2,
+			ID:      "group/ci",
+			Message: "at 2017-12-01T18:00:00Z: set size to default 5",
+		},
+	}, plan.Changes)
+	require.Equal(t, 0, len(sizes)) // nothing changed
+
+	_, err = c.Enforce(spec)
+	require.NoError(t, err)
+	defer c.Stop()
+
+	require.Equal(t, 5, <-sizes) // the default at start
+
+	clock <- time.Date(2017, time.December, 1, 8, 0, 0, 0, time.UTC)
+	require.Equal(t, 80, <-sizes)
+
+	// no more changes until the window closes
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 0, len(sizes))
+
+	clock <- time.Date(2017, time.December, 1, 18, 0, 0, 0, time.UTC)
+	require.Equal(t, 5, <-sizes)
+
+	// the state shows the target of the group
+	time.Sleep(100 * time.Millisecond)
+	object, err := c.Inspect()
+	require.NoError(t, err)
+
+	items := []struct {
+		Key   string
+		State string
+		Data  struct {
+			Size   int
+			Target schedule.Target
+		}
+	}{}
+	require.NoError(t, object.State.Decode(&items))
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "group/ci", items[0].Key)
+	require.Equal(t, "SCALED", items[0].State)
+	require.Equal(t, 5, items[0].Data.Size)
+	require.Equal(t, "", items[0].Data.Target.Rule)
+	require.True(t, time.Date(2017, time.December, 4, 8, 0, 0, 0, time.UTC).Equal(items[0].Data.Target.Next))
+}
+
+func TestScheduleStopWhileChecking(t *testing.T) {
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	scope := testing_scope.DefaultScope()
+	scope.ResolveGroup = func(name string) (group.Plugin, error) {
+		return &testing_group.Plugin{
+			DoSetSize: func(id group.ID, size int) error {
+				close(entered)
+				<-release
+				return nil
+			},
+		}, nil
+	}
+
+	c, err := newCollection(scope, schedule.Options{CheckInterval: types.Duration(10 * time.Millisecond)})
+	require.NoError(t, err)
+	c.now = func() time.Time {
+		return time.Date(2017, time.December, 1, 7, 0, 0, 0, time.UTC)
+	}
+
+	events := make(chan *event.Event, 100)
+	c.PublishOn(events)
+
+	spec := types.Spec{}
+	require.NoError(t, types.AnyYAMLMust([]byte(testSpec)).Decode(&spec))
+
+	_, err = c.Enforce(spec)
+	require.NoError(t, err)
+
+	<-entered // a check is in progress
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- c.Stop()
+	}()
+
+	select {
+	case <-stopped:
+		require.Fail(t, "stopped while checking")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-stopped)
+}
@@ -0,0 +1,54 @@
+package schedule // import "github.com/docker/infrakit/pkg/controller/schedule"
+
+import (
+	"time"
+
+	"github.com/docker/infrakit/pkg/controller/internal"
+	schedule "github.com/docker/infrakit/pkg/controller/schedule/types"
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/run/scope"
+	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/spi/metadata"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+var (
+	log    = logutil.New("module", "controller/schedule")
+	debugV = logutil.V(500)
+
+	// DefaultOptions is the default options of the controller. This can be controlled at starup
+	// and is set once.
+	DefaultOptions = schedule.Options{
+		CheckInterval: types.Duration(30 * time.Second),
+		PlanHorizon:   types.Duration(24 * time.Hour),
+	}
+)
+
+// Components contains a set of components in this controller.
+type Components struct {
+	Controllers func() (map[string]controller.Controller, error)
+	Metadata    func() (map[string]metadata.Plugin, error)
+	Events      event.Plugin
+}
+
+// NewComponents returns a controller implementation
+func NewComponents(scope scope.Scope, options schedule.Options) *Components {
+
+	controller := internal.NewController(
+		// the constructor
+		func(spec types.Spec) (internal.Managed, error) {
+			return newCollection(scope, options)
+		},
+		// the key function
+		func(metadata types.Metadata) string {
+			return metadata.Name
+		},
+	)
+
+	return &Components{
+		Controllers: controller.Controllers,
+		Metadata:    controller.Metadata,
+		Events:      controller,
+	}
+}
@@ -0,0 +1,118 @@
+package schedule // import "github.com/docker/infrakit/pkg/controller/schedule"
+
+import (
+	"fmt"
+	"time"
+
+	schedule "github.com/docker/infrakit/pkg/controller/schedule/types"
+	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/spi/group"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+// plan returns the changes of the group sizes the spec would make, now and at the boundaries of the
+// windows within the plan horizon.  Nothing is changed, so this is the dry run of a schedule.
+func (c *collection) plan(op controller.Operation, spec types.Spec) (plan controller.Plan, err error) {
+
+	if op == controller.Destroy {
+		plan.Message = []string{"stop scheduling; the groups are left as is"}
+		return
+	}
+
+	properties, options, err := c.decode(spec)
+	if err != nil {
+		return
+	}
+
+	now := c.now()
+	targets, err := properties.Targets(now)
+	if err != nil {
+		return
+	}
+
+	// changes now, from the current sizes of the groups
+	last := map[string]int{}
+	for _, target := range targets {
+		if target.Size < 0 {
+			continue
+		}
+		last[string(target.Group)] = target.Size
+
+		message := describe(target)
+		if current, err := c.currentSize(target); err != nil {
+			message += fmt.Sprintf(" (cannot get current size: %v)", err)
+		} else if current == target.Size {
+			continue
+		} else {
+			message += fmt.Sprintf(" (from %d)", current)
+		}
+
+		plan.Changes = append(plan.Changes, controller.Change{
+			Type:    controller.ChangeUpdate,
+			Step:    0,
+			ID:      string(target.Group),
+			Message: message,
+		})
+	}
+
+	// changes at the boundaries
+	horizon := now.Add(options.PlanHorizon.Duration())
+	step := 1
+	for t := next(targets); !t.IsZero() && !t.After(horizon); t = next(targets) {
+
+		targets, err = properties.Targets(t)
+		if err != nil {
+			return
+		}
+
+		changed := false
+		for _, target := range targets {
+			if size, has := last[string(target.Group)]; target.Size < 0 || (has && size == target.Size) {
+				continue
+			}
+			last[string(target.Group)] = target.Size
+			changed = true
+
+			plan.Changes = append(plan.Changes, controller.Change{
+				Type:    controller.ChangeUpdate,
+				Step:    step,
+				ID:      string(target.Group),
+				Message: fmt.Sprintf("at %s: %s", t.Format(time.RFC3339), describe(target)),
+			})
+		}
+		if changed {
+			step++
+		}
+	}
+
+	plan.Message = []string{
+		fmt.Sprintf("%d changes of group sizes until %s", len(plan.Changes), horizon.Format(time.RFC3339)),
+	}
+	return
+}
+
+// next returns the earliest time when one of the targets may change
+func next(targets []schedule.Target) (t time.Time) {
+	for _, target := range targets {
+		if !target.Next.IsZero() && (t.IsZero() || target.Next.Before(t)) {
+			t = target.Next
+		}
+	}
+	return
+}
+
+func describe(target schedule.Target) string {
+	if target.Rule == "" {
+		return fmt.Sprintf("set size to default %d", target.Size)
+	}
+	return fmt.Sprintf("set size to %d by rule %s", target.Size, target.Rule)
+}
+
+func (c *collection) currentSize(target schedule.Target) (int, error) {
+	groupPlugin, err := c.groupPlugin(target.Group)
+	if err != nil {
+		return 0, err
+	}
+	_, gid := target.Group.GetLookupAndType()
+	return groupPlugin.Size(group.ID(gid))
+}
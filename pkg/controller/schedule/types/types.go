@@ -0,0 +1,248 @@
+package types // import "github.com/docker/infrakit/pkg/controller/schedule/types"
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/run/depends"
+	"github.com/docker/infrakit/pkg/spi/controller"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+func init() {
+	depends.Register("schedule", types.InterfaceSpec(controller.InterfaceSpec), ResolveDependencies)
+}
+
+// ResolveDependencies returns a list of dependencies by parsing the opaque Properties blob.
+func ResolveDependencies(spec types.Spec) (depends.Runnables, error) {
+	if spec.Properties == nil {
+		return nil, nil
+	}
+
+	properties := Properties{}
+	err := spec.Properties.Decode(&properties)
+	if err != nil {
+		return nil, err
+	}
+
+	dep := depends.Runnables{}
+	seen := map[string]bool{}
+	for _, rule := range properties.Rules {
+		lookup := rule.Group.Lookup()
+		if seen[lookup] {
+			continue
+		}
+		seen[lookup] = true
+		dep = append(dep, depends.AsRunnable(
+			types.Spec{
+				Kind: lookup,
+				Metadata: types.Metadata{
+					Name: lookup,
+				},
+			},
+		))
+	}
+	return dep, nil
+}
+
+// Rule sets the size of a group during a recurring window of time
+type Rule struct {
+	// Name identifies the rule in plans and states.  Defaults to the position of the rule.
+	Name string
+
+	// Group is the name of the group, as in group/workers
+	Group plugin.Name
+
+	// Size is the size of the group while the window is open
+	Size int
+
+	// Start is the cron expression for when the window opens, as in 0 8 * * 1-5
+	Start string
+
+	// End is the cron expression for when the window closes, as in 0 18 * * 1-5
+	End string
+
+	// Timezone is the name of the time zone of the cron expressions, as in America/New_York.  Defaults to UTC.
+	Timezone string
+
+	// Priority decides between the rules of a group with overlapping windows.  The rule with the highest
+	// priority wins, then the rule whose window opened last.
+	Priority int
+}
+
+// Properties is the schema of the configuration in the types.Spec.Properties
+type Properties struct {
+	// Rules are the rules of the schedule
+	Rules []Rule
+
+	// Defaults are the sizes of the groups, by group name, when none of their windows are open.  A group
+	// without a default is left as is when its windows close.
+	Defaults map[string]int `json:",omitempty" yaml:",omitempty"`
+}
+
+// Options is the controller options that is used at start up of the process.  It's one-time
+type Options struct {
+	// CheckInterval is the interval for checking the rules
+	CheckInterval types.Duration
+
+	// PlanHorizon is how far ahead a plan lists the changes of the group sizes
+	PlanHorizon types.Duration
+}
+
+// Validate validates the controller's options
+func (p Options) Validate(ctx context.Context) error {
+	if p.CheckInterval.Duration() <= 0 {
+		return fmt.Errorf("bad check interval: %v", p.CheckInterval)
+	}
+	if p.PlanHorizon.Duration() < 0 {
+		return fmt.Errorf("bad plan horizon: %v", p.PlanHorizon)
+	}
+	return nil
+}
+
+// Validate validates the input properties
+func (p Properties) Validate(ctx context.Context) error {
+	for i, rule := range p.Rules {
+		if _, gid := rule.Group.GetLookupAndType(); gid == "" {
+			return fmt.Errorf("rule %d: group must be like group/workers: %q", i, rule.Group)
+		}
+		if rule.Size < 0 {
+			return fmt.Errorf("rule %d: bad size %d", i, rule.Size)
+		}
+		if _, _, _, err := rule.compile(); err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+	}
+	for group, size := range p.Defaults {
+		if size < 0 {
+			return fmt.Errorf("bad default size %d of %s", size, group)
+		}
+	}
+	return nil
+}
+
+func (r Rule) compile() (start, end *Cron, loc *time.Location, err error) {
+	if start, err = ParseCron(r.Start); err != nil {
+		return
+	}
+	if end, err = ParseCron(r.End); err != nil {
+		return
+	}
+	loc, err = time.LoadLocation(r.Timezone)
+	return
+}
+
+// Window returns the window of the rule that's open at t.  It returns false if none is open.
+func (r Rule) Window(t time.Time) (start, end time.Time, open bool, err error) {
+	startCron, endCron, loc, err := r.compile()
+	if err != nil {
+		return
+	}
+	start = startCron.Prev(t.In(loc))
+	if start.IsZero() {
+		return
+	}
+	end = endCron.Next(start)
+	open = end.IsZero() || t.Before(end)
+	return
+}
+
+// NextBoundary returns the next time after t when a window of the rule opens or closes.  It's zero if
+// there is none.
+func (r Rule) NextBoundary(t time.Time) (time.Time, error) {
+	startCron, endCron, loc, err := r.compile()
+	if err != nil {
+		return time.Time{}, err
+	}
+	t = t.In(loc)
+	next, end := startCron.Next(t), endCron.Next(t)
+	if next.IsZero() || (!end.IsZero() && end.Before(next)) {
+		next = end
+	}
+	return next, nil
+}
+
+// Target is the size of a group at a point in time, as decided by the rules
+type Target struct {
+	// Group is the name of the group
+	Group plugin.Name
+
+	// Size is the size of the group.  It's -1 if the group is left as is.
+	Size int
+
+	// Rule is the name of the rule that decides the size.  It's empty if it's the default size.
+	Rule string `json:",omitempty" yaml:",omitempty"`
+
+	// Since is when the window of the rule opened
+	Since time.Time `json:",omitempty" yaml:",omitempty"`
+
+	// Next is the next time the target may change.  It's zero if the target never changes.
+	Next time.Time `json:",omitempty" yaml:",omitempty"`
+}
+
+// Targets returns the targets of all the groups of the rules and the defaults at t, by group name.
+func (p Properties) Targets(t time.Time) ([]Target, error) {
+	targets := map[plugin.Name]*Target{}
+	target := func(group plugin.Name) *Target {
+		if found, has := targets[group]; has {
+			return found
+		}
+		found := &Target{Group: group, Size: -1}
+		if size, has := p.Defaults[string(group)]; has {
+			found.Size = size
+		}
+		targets[group] = found
+		return found
+	}
+	for group := range p.Defaults {
+		target(plugin.Name(group))
+	}
+
+	priorities := map[plugin.Name]int{}
+	for i, rule := range p.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i)
+		}
+
+		current := target(rule.Group)
+
+		next, err := rule.NextBoundary(t)
+		if err != nil {
+			return nil, err
+		}
+		if !next.IsZero() && (current.Next.IsZero() || next.Before(current.Next)) {
+			current.Next = next
+		}
+
+		start, _, open, err := rule.Window(t)
+		if err != nil {
+			return nil, err
+		}
+		if !open {
+			continue
+		}
+
+		// precedence: higher priority, then the window that opened last
+		if current.Rule != "" {
+			priority := priorities[rule.Group]
+			if rule.Priority < priority || (rule.Priority == priority && !start.After(current.Since)) {
+				continue
+			}
+		}
+		current.Rule = name
+		current.Size = rule.Size
+		current.Since = start
+		priorities[rule.Group] = rule.Priority
+	}
+
+	result := []Target{}
+	for _, target := range targets {
+		result = append(result, *target)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Group < result[j].Group })
+	return result, nil
+}
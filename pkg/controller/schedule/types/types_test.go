@@ -0,0 +1,118 @@
+package types // import "github.com/docker/infrakit/pkg/controller/schedule/types"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCron(t *testing.T) {
+
+	_, err := ParseCron("0 8 * *")
+	require.Error(t, err)
+	_, err = ParseCron("0 24 * * *")
+	require.Error(t, err)
+	_, err = ParseCron("*/0 8 * * *")
+	require.Error(t, err)
+
+	weekdays, err := ParseCron("30 8-18/2 * * 1-5")
+	require.NoError(t, err)
+
+	friday := time.Date(2017, time.December, 1, 19, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Friday, friday.Weekday())
+
+	require.True(t, weekdays.Matches(time.Date(2017, time.December, 1, 10, 30, 0, 0, time.UTC)))
+	require.False(t, weekdays.Matches(time.Date(2017, time.December, 1, 9, 30, 0, 0, time.UTC)))
+
+	// next is monday morning
+	require.Equal(t, time.Date(2017, time.December, 4, 8, 30, 0, 0, time.UTC), weekdays.Next(friday))
+	require.Equal(t, time.Date(2017, time.December, 1, 18, 30, 0, 0, time.UTC), weekdays.Prev(friday))
+
+	// prev is at or before
+	require.Equal(t, time.Date(2017, time.December, 1, 18, 30, 0, 0, time.UTC),
+		weekdays.Prev(time.Date(2017, time.December, 1, 18, 30, 59, 0, time.UTC)))
+
+	// day of month or day of week, and 7 is sunday
+	either, err := ParseCron("0 0 1 * 7")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2017, time.December, 3, 0, 0, 0, 0, time.UTC), either.Next(friday))
+	require.Equal(t, time.Date(2017, time.December, 1, 0, 0, 0, 0, time.UTC), either.Prev(friday))
+
+	never, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	require.True(t, never.Next(friday).IsZero())
+}
+
+func TestTargets(t *testing.T) {
+
+	properties := Properties{}
+	require.NoError(t, types.AnyYAMLMust([]byte(`
+Rules:
+  - Name: workhours
+    Group: group/ci
+    Size: 80
+    Start: 0 8 * * 1-5
+    End: 0 18 * * 1-5
+    Timezone: America/Los_Angeles
+  - Name: release
+    Group: group/ci
+    Size: 100
+    Start: 0 12 1 12 *
+    End: 0 14 1 12 *
+    Timezone: America/Los_Angeles
+    Priority: 1
+  - Name: lunch
+    Group: group/ci
+    Size: 40
+    Start: 0 12 * * 1-5
+    End: 0 13 * * 1-5
+    Timezone: America/Los_Angeles
+Defaults:
+  group/ci: 5
+  group/other: 2
+`)).Decode(&properties))
+	require.NoError(t, properties.Validate(context.Background()))
+
+	la, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+
+	at := func(day, hour, minute int) []Target {
+		targets, err := properties.Targets(time.Date(2017, time.December, day, hour, minute, 0, 0, la))
+		require.NoError(t, err)
+		require.Equal(t, 2, len(targets))
+		require.Equal(t, Target{Group: "group/other", Size: 2}, targets[1])
+		return targets
+	}
+
+	// friday night: default until monday morning
+	target := at(1, 20, 0)[0]
+	require.Equal(t, 5, target.Size)
+	require.Equal(t, "", target.Rule)
+	require.True(t, time.Date(2017, time.December, 4, 8, 0, 0, 0, la).Equal(target.Next))
+
+	// friday morning
+	target = at(1, 9, 0)[0]
+	require.Equal(t, 80, target.Size)
+	require.Equal(t, "workhours", target.Rule)
+	require.True(t, time.Date(2017, time.December, 1, 8, 0, 0, 0, la).Equal(target.Since))
+	require.True(t, time.Date(2017, time.December, 1, 12, 0, 0, 0, la).Equal(target.Next))
+
+	// the release has priority over lunch, which opened at the same time
+	target = at(1, 12, 30)[0]
+	require.Equal(t, 100, target.Size)
+	require.Equal(t, "release", target.Rule)
+
+	// on monday lunch opened after work hours
+	target = at(4, 12, 30)[0]
+	require.Equal(t, 40, target.Size)
+	require.Equal(t, "lunch", target.Rule)
+
+	bad := Properties{Rules: []Rule{{Group: "group", Start: "* * * * *", End: "* * * * *"}}}
+	require.Error(t, bad.Validate(context.Background()))
+
+	bad = Properties{Rules: []Rule{{Group: "group/ci", Start: "* * * * *", End: "* * * * *", Timezone: "Mars/Base"}}}
+	require.Error(t, bad.Validate(context.Background()))
+}
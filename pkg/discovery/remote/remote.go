@@ -11,6 +11,7 @@ import (
 	"github.com/docker/infrakit/pkg/discovery"
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/rpc/client"
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -77,6 +78,13 @@ func (r *remotePluginDiscovery) List() (map[string]*plugin.Endpoint, error) {
 		// for each remote, we issue the OPTIONS call to get information about
 		// master, plugins
 		c := &http.Client{}
+		if remote.Scheme == "https" {
+			config, err := client.TLSConfig(remote.Host)
+			if err != nil {
+				return nil, err
+			}
+			c.Transport = &http.Transport{TLSClientConfig: config}
+		}
 
 		// List of plugins and leadership information is available via HTTP OPTIONS call
 		body, err := doHTTPOptions(remote, nil, c)
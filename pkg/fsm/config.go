@@ -0,0 +1,301 @@
+package fsm // import "github.com/docker/infrakit/pkg/fsm"
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+)
+
+// Config is the declarative form of a spec, suitable for YAML or JSON.  States and signals are
+// referenced by their names.
+type Config struct {
+
+	// States are the states of the fsm.  A state without an index gets its position in the list.
+	States []StateConfig
+
+	// Signals are the indexes of the signals by name.  Signals that are not listed are numbered, in the
+	// order of their names, after the largest index listed.
+	Signals map[string]Signal `json:",omitempty" yaml:",omitempty"`
+}
+
+// StateConfig is the declarative form of a state
+type StateConfig struct {
+
+	// Name is the name of the state
+	Name string
+
+	// Index is the index of the state.  Optional.
+	Index *Index `json:",omitempty" yaml:",omitempty"`
+
+	// Transitions are the next states by the name of the signals
+	Transitions map[string]string `json:",omitempty" yaml:",omitempty"`
+
+	// Actions are the names of the actions to run by the name of the signals
+	Actions map[string]string `json:",omitempty" yaml:",omitempty"`
+
+	// Errors are the states to go to when the actions fail, by the name of the signals
+	Errors map[string]string `json:",omitempty" yaml:",omitempty"`
+
+	// TTL specifies how long the fsm can be in this state before a signal is raised
+	TTL *ExpiryConfig `json:",omitempty" yaml:",omitempty"`
+
+	// Visit specifies how many times the fsm can visit this state before a signal is raised
+	Visit *LimitConfig `json:",omitempty" yaml:",omitempty"`
+}
+
+// ExpiryConfig is the declarative form of Expiry
+type ExpiryConfig struct {
+	TTL   Tick
+	Raise string
+}
+
+// LimitConfig is the declarative form of Limit
+type LimitConfig struct {
+	Value int
+	Raise string
+}
+
+var (
+	actions     = map[string]Action{}
+	actionsLock sync.RWMutex
+)
+
+// RegisterAction registers an action by name so that it can be referenced in a Config
+func RegisterAction(name string, action Action) {
+	actionsLock.Lock()
+	defer actionsLock.Unlock()
+
+	actions[name] = action
+}
+
+func registeredAction(name string) (action Action, has bool) {
+	actionsLock.RLock()
+	defer actionsLock.RUnlock()
+
+	action, has = actions[name]
+	return
+}
+
+// ParseConfig parses the config from YAML or JSON
+func ParseConfig(buff []byte) (Config, error) {
+	config := Config{}
+	err := yaml.Unmarshal(buff, &config)
+	return config, err
+}
+
+// signals returns the indexes of all the signals referenced in the config
+func (c Config) signals() map[string]Signal {
+	signals := map[string]Signal{}
+	next := Signal(0)
+	for name, signal := range c.Signals {
+		signals[name] = signal
+		if signal >= next {
+			next = signal + 1
+		}
+	}
+
+	names := []string{}
+	for _, st := range c.States {
+		for _, m := range []map[string]string{st.Transitions, st.Actions, st.Errors} {
+			for name := range m {
+				names = append(names, name)
+			}
+		}
+		if st.TTL != nil {
+			names = append(names, st.TTL.Raise)
+		}
+		if st.Visit != nil {
+			names = append(names, st.Visit.Raise)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, has := signals[name]; !has {
+			signals[name] = next
+			next++
+		}
+	}
+	return signals
+}
+
+// Define returns the compiled spec.  The actions are looked up by name first in the given map, which
+// is optional, and then in the actions registered with RegisterAction.
+func (c Config) Define(actions map[string]Action) (*Spec, error) {
+	if len(c.States) == 0 {
+		return nil, fmt.Errorf("no states")
+	}
+
+	states := map[string]Index{}
+	for i, st := range c.States {
+		index := Index(i)
+		if st.Index != nil {
+			index = *st.Index
+		}
+		if _, has := states[st.Name]; has {
+			return nil, fmt.Errorf("duplicate state name: %s", st.Name)
+		}
+		states[st.Name] = index
+	}
+
+	signals := c.signals()
+
+	stateNames := map[Index]string{}
+	for name, index := range states {
+		stateNames[index] = name
+	}
+	signalNames := map[Signal]string{}
+	for name, signal := range signals {
+		signalNames[signal] = name
+	}
+
+	transfer := func(m map[string]string) (map[Signal]Index, error) {
+		if len(m) == 0 {
+			return nil, nil
+		}
+		out := map[Signal]Index{}
+		for signal, next := range m {
+			index, has := states[next]
+			if !has {
+				return nil, fmt.Errorf("unknown state: %s", next)
+			}
+			out[signals[signal]] = index
+		}
+		return out, nil
+	}
+
+	actionNames := map[Index]map[Signal]string{}
+	defined := []State{}
+	for _, st := range c.States {
+		state := State{Index: states[st.Name]}
+
+		var err error
+		if state.Transitions, err = transfer(st.Transitions); err != nil {
+			return nil, err
+		}
+		if state.Errors, err = transfer(st.Errors); err != nil {
+			return nil, err
+		}
+
+		if len(st.Actions) > 0 {
+			state.Actions = map[Signal]Action{}
+			actionNames[state.Index] = map[Signal]string{}
+		}
+		for signal, name := range st.Actions {
+			action, has := actions[name]
+			if !has {
+				action, has = registeredAction(name)
+			}
+			if !has {
+				return nil, fmt.Errorf("unknown action: %s", name)
+			}
+			state.Actions[signals[signal]] = action
+			actionNames[state.Index][signals[signal]] = name
+		}
+
+		if st.TTL != nil {
+			state.TTL = Expiry{TTL: st.TTL.TTL, Raise: signals[st.TTL.Raise]}
+		}
+		if st.Visit != nil {
+			state.Visit = Limit{Value: st.Visit.Value, Raise: signals[st.Visit.Raise]}
+		}
+		defined = append(defined, state)
+	}
+
+	spec, err := With(stateNames, signalNames).Define(defined[0], defined[1:]...)
+	if err != nil {
+		return nil, err
+	}
+	spec.actionNames = actionNames
+	return spec, nil
+}
+
+// NoopActions returns actions that do nothing for all the actions named in the config, so that the config
+// can be defined without resolving the actions.
+func (c Config) NoopActions() map[string]Action {
+	noop := func(FSM) error { return nil }
+	actions := map[string]Action{}
+	for _, st := range c.States {
+		for _, name := range st.Actions {
+			actions[name] = noop
+		}
+	}
+	return actions
+}
+
+// Validate checks the config without resolving the actions
+func (c Config) Validate() error {
+	_, err := c.Define(c.NoopActions())
+	return err
+}
+
+// actionName returns the name of the action for the signal in the state
+func (s *Spec) actionName(state Index, signal Signal, action Action) string {
+	if names, has := s.actionNames[state]; has {
+		if name, has := names[signal]; has {
+			return name
+		}
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(action).Pointer()).Name()
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// Config returns the declarative form of the spec.  Actions that were not defined by a Config are
+// named after their functions.
+func (s *Spec) Config() Config {
+	config := Config{
+		States:  []StateConfig{},
+		Signals: map[string]Signal{},
+	}
+
+	for signal := range s.signals {
+		config.Signals[s.SignalName(signal)] = signal
+	}
+
+	indexes := []int{}
+	for index := range s.states {
+		indexes = append(indexes, int(index))
+	}
+	sort.Ints(indexes)
+
+	transfer := func(m map[Signal]Index) map[string]string {
+		if len(m) == 0 {
+			return nil
+		}
+		out := map[string]string{}
+		for signal, next := range m {
+			out[s.SignalName(signal)] = s.StateName(next)
+		}
+		return out
+	}
+
+	for _, i := range indexes {
+		st := s.states[Index(i)]
+		index := st.Index
+		state := StateConfig{
+			Name:        s.StateName(st.Index),
+			Index:       &index,
+			Transitions: transfer(st.Transitions),
+			Errors:      transfer(st.Errors),
+		}
+		if len(st.Actions) > 0 {
+			state.Actions = map[string]string{}
+			for signal, action := range st.Actions {
+				state.Actions[s.SignalName(signal)] = s.actionName(st.Index, signal, action)
+			}
+		}
+		if st.TTL.TTL > 0 {
+			state.TTL = &ExpiryConfig{TTL: st.TTL.TTL, Raise: s.SignalName(st.TTL.Raise)}
+		}
+		if st.Visit.Value > 0 {
+			state.Visit = &LimitConfig{Value: st.Visit.Value, Raise: s.SignalName(st.Visit.Raise)}
+		}
+		config.States = append(config.States, state)
+	}
+	return config
+}
@@ -0,0 +1,140 @@
+package fsm // import "github.com/docker/infrakit/pkg/fsm"
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+States:
+  - Name: requested
+    Transitions:
+      provision: provisioning
+    TTL:
+      TTL: 5
+      Raise: provision
+  - Name: provisioning
+    Transitions:
+      found: ready
+      provision: provisioning
+    Actions:
+      provision: createInstance
+    Errors:
+      provision: failed
+    Visit:
+      Value: 3
+      Raise: found
+  - Name: ready
+  - Name: failed
+    Index: 10
+Signals:
+  provision: 7
+`
+
+func TestConfigDefine(t *testing.T) {
+
+	config, err := ParseConfig([]byte(testConfig))
+	require.NoError(t, err)
+	require.Equal(t, 4, len(config.States))
+
+	_, err = config.Define(nil)
+	require.Error(t, err) // unknown action
+
+	require.NoError(t, config.Validate())
+
+	noop := config.NoopActions()
+	require.Equal(t, 1, len(noop))
+	require.NotNil(t, noop["createInstance"])
+
+	created := 0
+	RegisterAction("createInstance", func(FSM) error {
+		created++
+		return nil
+	})
+
+	spec, err := config.Define(nil)
+	require.NoError(t, err)
+
+	require.Equal(t, "requested", spec.StateName(0))
+	require.Equal(t, "failed", spec.StateName(10))
+	require.Equal(t, "provision", spec.SignalName(7))
+	require.Equal(t, "found", spec.SignalName(8))
+
+	next, action, err := spec.transition(Index(1), Signal(7))
+	require.NoError(t, err)
+	require.Equal(t, Index(1), next)
+	require.NoError(t, action(nil))
+	require.Equal(t, 1, created)
+
+	next, err = spec.error(Index(1), Signal(7))
+	require.NoError(t, err)
+	require.Equal(t, Index(10), next)
+
+	expiry, err := spec.expiry(Index(0))
+	require.NoError(t, err)
+	require.Equal(t, Expiry{TTL: 5, Raise: 7}, *expiry)
+
+	limit, err := spec.visit(Index(1))
+	require.NoError(t, err)
+	require.Equal(t, Limit{Value: 3, Raise: 8}, *limit)
+
+	// round trip
+	exported := spec.Config()
+	require.Equal(t, "createInstance", exported.States[1].Actions["provision"])
+	require.Equal(t, Signal(8), exported.Signals["found"])
+
+	spec2, err := exported.Define(nil)
+	require.NoError(t, err)
+	require.Equal(t, exported, spec2.Config())
+
+	// bad references
+	bad, err := ParseConfig([]byte(`
+States:
+  - Name: a
+    Transitions:
+      go: b
+`))
+	require.NoError(t, err)
+	require.Error(t, bad.Validate())
+}
+
+func TestSpecDot(t *testing.T) {
+
+	const (
+		requested Index = iota
+		ready
+
+		found Signal = iota
+	)
+
+	spec, err := Define(
+		State{
+			Index: requested,
+			Transitions: map[Signal]Index{
+				found: ready,
+			},
+			Actions: map[Signal]Action{
+				found: func(FSM) error { return nil },
+			},
+			TTL: Expiry{TTL: 2, Raise: found},
+		},
+		State{
+			Index: ready,
+		},
+	)
+	require.NoError(t, err)
+	spec.SetStateNames(map[Index]string{requested: "requested", ready: "ready"}).
+		SetSignalNames(map[Signal]string{found: "found"})
+
+	dot := spec.Dot("test")
+	require.True(t, strings.HasPrefix(dot, `digraph "test" {`))
+	require.Contains(t, dot, `"requested" [label="requested\nTTL 2: found"];`)
+	require.Contains(t, dot, `"requested" -> "ready" [label="found / fsm.TestSpecDot.func1"];`)
+
+	config, err := ParseConfig([]byte(testConfig))
+	require.NoError(t, err)
+	require.Contains(t, config.Dot("pool"),
+		`"provisioning" -> "failed" [label="provision (error)", style=dashed, color=red];`)
+}
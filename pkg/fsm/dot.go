@@ -0,0 +1,61 @@
+package fsm // import "github.com/docker/infrakit/pkg/fsm"
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Dot returns the config as a graph in the Graphviz DOT language.  Transitions are solid edges labeled
+// with the signal and the action, if any.  Error transitions are dashed red edges.  States with a TTL
+// or a visit limit show the signal raised.
+func (c Config) Dot(name string) string {
+	out := &bytes.Buffer{}
+
+	fmt.Fprintf(out, "digraph %s {\n", strconv.Quote(name))
+	fmt.Fprintln(out, "  node [shape=box, style=rounded];")
+
+	for _, st := range c.States {
+		label := st.Name
+		if st.TTL != nil {
+			label += fmt.Sprintf("\nTTL %v: %s", st.TTL.TTL, st.TTL.Raise)
+		}
+		if st.Visit != nil {
+			label += fmt.Sprintf("\nvisits %v: %s", st.Visit.Value, st.Visit.Raise)
+		}
+		fmt.Fprintf(out, "  %s [label=%s];\n", strconv.Quote(st.Name), strconv.Quote(label))
+	}
+
+	for _, st := range c.States {
+		for _, signal := range sortedKeys(st.Transitions) {
+			label := signal
+			if action, has := st.Actions[signal]; has {
+				label += " / " + action
+			}
+			fmt.Fprintf(out, "  %s -> %s [label=%s];\n",
+				strconv.Quote(st.Name), strconv.Quote(st.Transitions[signal]), strconv.Quote(label))
+		}
+		for _, signal := range sortedKeys(st.Errors) {
+			fmt.Fprintf(out, "  %s -> %s [label=%s, style=dashed, color=red];\n",
+				strconv.Quote(st.Name), strconv.Quote(st.Errors[signal]), strconv.Quote(signal+" (error)"))
+		}
+	}
+
+	fmt.Fprintln(out, "}")
+	return out.String()
+}
+
+// Dot returns the spec as a graph in the Graphviz DOT language
+func (s *Spec) Dot(name string) string {
+	return s.Config().Dot(name)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -118,6 +118,8 @@ type Spec struct {
 
 	stateNames  map[Index]string  // optional
 	signalNames map[Signal]string // optional
+
+	actionNames map[Index]map[Signal]string // optional, when defined by a Config
 }
 
 func newSpec() *Spec {
@@ -182,6 +184,7 @@ func (s *Spec) SetAction(state Index, signal Signal, action Action) error {
 	}
 	st.Actions[signal] = action
 	s.states[state] = st // Update the map because the map returned a copy of the state.
+	delete(s.actionNames[state], signal)
 	return nil
 }
 
@@ -0,0 +1,111 @@
+package fsm // import "github.com/docker/infrakit/pkg/fsm"
+
+import (
+	"fmt"
+
+	event_spi "github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+const (
+	defaultHistorySize = 16
+)
+
+var (
+	// TopicTransition is the topic of the events published on each transition
+	TopicTransition = types.PathFromString("transition")
+)
+
+// Transition is a record of a signal received by an instance
+type Transition struct {
+
+	// ID is the id of the instance
+	ID ID
+
+	// From is the state when the signal was received
+	From Index
+
+	// Signal is the signal received
+	Signal Signal
+
+	// To is the state after the signal is processed.  It's the same as From if the signal isn't valid.
+	To Index
+
+	// Time is the time of the set when the signal was processed
+	Time Time
+
+	// Error is the error, if the signal isn't valid or the action failed
+	Error string `json:",omitempty" yaml:",omitempty"`
+}
+
+// Describe returns a human-readable description of the transition
+func (s *Spec) Describe(t Transition) string {
+	description := fmt.Sprintf("%v: %s + %s -> %s", t.Time,
+		s.StateName(t.From), s.SignalName(t.Signal), s.StateName(t.To))
+	if t.Error != "" {
+		description += " (" + t.Error + ")"
+	}
+	return description
+}
+
+// History returns the last transitions of the instance, oldest first
+func (s *Set) History(id ID) (history []Transition) {
+	blocker := make(chan struct{})
+	s.reads <- func(set Set) {
+		defer close(blocker)
+		if m, has := set.members[id]; has {
+			history = append([]Transition{}, m.history...)
+		}
+	}
+	<-blocker
+	return
+}
+
+// PublishOn sets the channel to publish the transitions on.  Implements event.Publisher
+func (s *Set) PublishOn(events chan<- *event_spi.Event) {
+	published := make(chan *event_spi.Event, s.options.BufferSize)
+	s.reads <- func(set Set) {
+		s.published = published
+	}
+	go func() {
+		defer close(events)
+		for {
+			select {
+			case <-s.stop:
+				return
+			case evt := <-published:
+				events <- evt
+			}
+		}
+	}()
+}
+
+// record records the transition in the instance's history and publishes it
+func (s *Set) record(instance *instance, t Transition) {
+	t.ID = instance.id
+	t.Time = s.ct()
+
+	if s.options.HistorySize > 0 {
+		instance.history = append(instance.history, t)
+		if len(instance.history) > s.options.HistorySize {
+			instance.history = instance.history[len(instance.history)-s.options.HistorySize:]
+		}
+	}
+
+	if s.published == nil {
+		return
+	}
+
+	evt := event_spi.Event{
+		Topic:   TopicTransition,
+		Type:    event_spi.Type("Transition"),
+		ID:      fmt.Sprintf("%v", t.ID),
+		Message: s.spec.Describe(t),
+	}.Init().WithDataMust(t)
+
+	select {
+	case s.published <- evt:
+	default:
+		log.Warn("Dropped transition event", "name", s.options.Name, "id", t.ID)
+	}
+}
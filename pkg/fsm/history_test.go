@@ -0,0 +1,120 @@
+package fsm // import "github.com/docker/infrakit/pkg/fsm"
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	event_spi "github.com/docker/infrakit/pkg/spi/event"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetHistory(t *testing.T) {
+
+	const (
+		requested Index = iota
+		provisioning
+		failed
+		ready
+	)
+
+	const (
+		provision Signal = iota
+		found
+	)
+
+	spec, err := Define(
+		State{
+			Index: requested,
+			Transitions: map[Signal]Index{
+				provision: provisioning,
+			},
+			Actions: map[Signal]Action{
+				provision: func(FSM) error { return fmt.Errorf("boom") },
+			},
+			Errors: map[Signal]Index{
+				provision: failed,
+			},
+		},
+		State{
+			Index: provisioning,
+			Transitions: map[Signal]Index{
+				found: ready,
+			},
+		},
+		State{
+			Index: failed,
+			Transitions: map[Signal]Index{
+				provision: provisioning,
+			},
+		},
+		State{
+			Index: ready,
+			Transitions: map[Signal]Index{
+				found: ready,
+			},
+		},
+	)
+	require.NoError(t, err)
+	spec.SetStateNames(map[Index]string{
+		requested:    "requested",
+		provisioning: "provisioning",
+		failed:       "failed",
+		ready:        "ready",
+	}).SetSignalNames(map[Signal]string{
+		provision: "provision",
+		found:     "found",
+	})
+
+	clock := NewClock()
+	set := NewSet(spec, clock, Options{HistorySize: 3})
+	defer set.Stop()
+
+	events := make(chan *event_spi.Event, 10)
+	set.PublishOn(events)
+
+	instance := set.Add(requested)
+
+	require.NoError(t, instance.Signal(provision)) // action fails -> failed
+	require.NoError(t, instance.Signal(found))     // not a valid signal in failed
+	require.NoError(t, instance.Signal(provision)) // -> provisioning
+	require.NoError(t, instance.Signal(found))     // -> ready
+
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, ready, instance.State())
+
+	history := set.History(instance.ID())
+	require.Equal(t, 3, len(history)) // bounded
+	require.Equal(t, Transition{ID: instance.ID(), From: failed, Signal: found, To: failed,
+		Error: history[0].Error}, history[0])
+	require.NotEqual(t, "", history[0].Error)
+	require.Equal(t, Transition{ID: instance.ID(), From: failed, Signal: provision, To: provisioning}, history[1])
+	require.Equal(t, Transition{ID: instance.ID(), From: provisioning, Signal: found, To: ready}, history[2])
+	require.Equal(t, "0: provisioning + found -> ready", spec.Describe(history[2]))
+
+	// all the transitions are published
+	published := []Transition{}
+	for i := 0; i < 4; i++ {
+		evt := <-events
+		require.Equal(t, TopicTransition, evt.Topic)
+		tr := Transition{}
+		require.NoError(t, evt.Data.Decode(&tr))
+		published = append(published, tr)
+	}
+	require.Equal(t, Transition{ID: instance.ID(), From: requested, Signal: provision, To: failed,
+		Error: "boom"}, published[0])
+	require.Equal(t, history, published[1:])
+
+	// the history is kept in the snapshot
+	snapshot := set.Snapshot()
+	require.Equal(t, history, snapshot.Members[0].History)
+
+	// no history
+	set2 := NewSet(spec, NewClock(), Options{HistorySize: -1})
+	defer set2.Stop()
+	instance = set2.Add(requested)
+	require.NoError(t, instance.Signal(provision))
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, failed, instance.State())
+	require.Equal(t, 0, len(set2.History(instance.ID())))
+}
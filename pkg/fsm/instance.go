@@ -16,6 +16,7 @@ type instance struct {
 	deadline Time
 	index    int // index used in the deadlines queue
 	visits   map[Index]int
+	history  []Transition
 
 	lock sync.RWMutex
 }
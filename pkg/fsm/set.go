@@ -20,6 +20,9 @@ func NewSet(spec *Spec, clock *Clock, optional ...Options) *Set {
 	if options.BufferSize == 0 {
 		options.BufferSize = defaultBufferSize
 	}
+	if options.HistorySize == 0 {
+		options.HistorySize = defaultHistorySize
+	}
 
 	set := &Set{
 		options:      options,
@@ -30,6 +33,7 @@ func NewSet(spec *Spec, clock *Clock, optional ...Options) *Set {
 		bystate:      map[Index]map[ID]*instance{},
 		reads:        make(chan func(Set)),
 		add:          make(chan addOp),
+		restore:      make(chan restoreOp),
 		delete:       make(chan ID),
 		errors:       make(chan error),
 		events:       make(chan *event),
@@ -77,6 +81,20 @@ func (s *Set) CountByState(state Index) int {
 	return <-total
 }
 
+// StateCounts returns a consistent count of instances in every state, keyed by the friendly name of the state.
+func (s *Set) StateCounts() map[string]int {
+	result := make(chan map[string]int, 1)
+	defer close(result)
+	s.reads <- func(view Set) {
+		counts := map[string]int{}
+		for state, members := range view.bystate {
+			counts[view.spec.StateName(state)] = len(members)
+		}
+		result <- counts
+	}
+	return <-result
+}
+
 // ForEach iterates through the set and provides a consistent view of the instances
 func (s *Set) ForEach(view func(ID, Index, interface{}) bool) {
 	blocker := make(chan struct{})
@@ -401,6 +419,7 @@ func (s *Set) handleEvent(tid int64, event *event) error {
 	current := instance.state
 	next, action, err := s.spec.transition(current, event.signal)
 	if err != nil {
+		s.record(instance, Transition{From: current, Signal: event.signal, To: current, Error: err.Error()})
 		return err
 	}
 
@@ -424,6 +443,7 @@ func (s *Set) handleEvent(tid int64, event *event) error {
 
 			log.Debug("Flapping", "tid", tid, "flaps", flaps,
 				"instance", instance.id, "state", instance.state, "raise", limit.Raise)
+			s.record(instance, Transition{From: current, Signal: event.signal, To: current, Error: "flapping"})
 			s.raise(tid, instance.id, limit.Raise, instance.state)
 
 			return nil // done -- another transition
@@ -436,6 +456,7 @@ func (s *Set) handleEvent(tid int64, event *event) error {
 	}
 
 	// call action before transitiion
+	var actionErr error
 	if action != nil {
 
 		log.Debug("Invoking action",
@@ -450,6 +471,7 @@ func (s *Set) handleEvent(tid int64, event *event) error {
 		if err := action(instance); err != nil {
 
 			log.Debug("Error transition", "err", err)
+			actionErr = err
 
 			if alternate, err := s.spec.error(current, event.signal); err != nil {
 
@@ -467,6 +489,12 @@ func (s *Set) handleEvent(tid int64, event *event) error {
 
 	// Action has been run... We landed in the new state (next)
 
+	transition := Transition{From: current, Signal: event.signal, To: next}
+	if actionErr != nil {
+		transition.Error = actionErr.Error()
+	}
+	s.record(instance, transition)
+
 	// process deadline, if any
 	if err := s.processDeadline(tid, instance, next); err != nil {
 		return err
@@ -552,6 +580,20 @@ func (s *Set) run() {
 					},
 				}
 
+			case op, ok := <-s.restore:
+				// restore instances from a snapshot
+				if !ok {
+					break loop
+				}
+				tx = &txn{
+					tid: tid,
+					Func: func(tid int64) (interface{}, error) {
+						err := s.handleRestore(tid, op.snapshot)
+						op.result <- err
+						return len(op.snapshot.Members), err
+					},
+				}
+
 			case id, ok := <-s.delete:
 				// delete instance
 				if !ok {
@@ -0,0 +1,149 @@
+package fsm // import "github.com/docker/infrakit/pkg/fsm"
+
+import (
+	"fmt"
+
+	"github.com/docker/infrakit/pkg/store"
+)
+
+// Member is the state of an instance in a snapshot of the set
+type Member struct {
+
+	// ID is the id of the instance
+	ID ID
+
+	// State is the current state of the instance
+	State Index
+
+	// Data is the custom data attached to the instance.  It's restored as decoded from json.
+	Data interface{} `json:",omitempty" yaml:",omitempty"`
+
+	// TTL is the number of ticks left before the deadline of the current state is reached.  0 if no deadline.
+	TTL Tick `json:",omitempty" yaml:",omitempty"`
+
+	// Visits is the number of visits of each state
+	Visits map[Index]int `json:",omitempty" yaml:",omitempty"`
+
+	// Flaps is the history of transitions used for flap detection
+	Flaps []Index `json:",omitempty" yaml:",omitempty"`
+
+	// History is the last transitions of the instance
+	History []Transition `json:",omitempty" yaml:",omitempty"`
+}
+
+// Snapshot is a consistent view of all the instances in a set.  It can be saved and restored so that
+// the instances keep their states and deadlines across restarts.
+type Snapshot struct {
+
+	// Next is the id of the next instance to be added
+	Next ID
+
+	// Members are the instances in the set
+	Members []Member
+}
+
+type restoreOp struct {
+	snapshot Snapshot
+	result   chan error
+}
+
+// Snapshot returns a snapshot of the instances in the set
+func (s *Set) Snapshot() Snapshot {
+	result := make(chan Snapshot, 1)
+	s.reads <- func(view Set) {
+		snapshot := Snapshot{Next: view.next, Members: []Member{}}
+		for _, m := range view.members {
+			member := Member{
+				ID:     m.id,
+				State:  m.state,
+				Data:   m.data,
+				Visits: map[Index]int{},
+				Flaps:  append([]Index{}, m.flaps.history...),
+			}
+			if len(m.history) > 0 {
+				member.History = append([]Transition{}, m.history...)
+			}
+			for k, v := range m.visits {
+				member.Visits[k] = v
+			}
+			if m.index > -1 && m.deadline > view.now {
+				member.TTL = Tick(m.deadline - view.now)
+			}
+			snapshot.Members = append(snapshot.Members, member)
+		}
+		result <- snapshot
+	}
+	return <-result
+}
+
+// Restore adds the instances in the snapshot to the set.  The instances keep their ids, and
+// the deadlines of their states are counted from now.
+func (s *Set) Restore(snapshot Snapshot) error {
+	op := restoreOp{snapshot: snapshot, result: make(chan error, 1)}
+	s.restore <- op
+	return <-op.result
+}
+
+// Save saves a snapshot of the set in the store
+func (s *Set) Save(store store.Snapshot) error {
+	return store.Save(s.Snapshot())
+}
+
+// Load restores the instances from the snapshot in the store, if any.  It returns the number of instances restored.
+func (s *Set) Load(store store.Snapshot) (int, error) {
+	snapshot := Snapshot{}
+	if err := store.Load(&snapshot); err != nil {
+		return 0, err
+	}
+	if err := s.Restore(snapshot); err != nil {
+		return 0, err
+	}
+	return len(snapshot.Members), nil
+}
+
+func (s *Set) handleRestore(tid int64, snapshot Snapshot) error {
+	for _, member := range snapshot.Members {
+		if _, has := s.members[member.ID]; has {
+			return fmt.Errorf("duplicate instance %v", member.ID)
+		}
+		if _, has := s.spec.states[member.State]; !has {
+			return ErrUnknownState(member.State)
+		}
+	}
+
+	now := s.ct()
+	for _, member := range snapshot.Members {
+		restored := &instance{
+			id:      member.ID,
+			state:   member.State,
+			data:    member.Data,
+			parent:  s,
+			flaps:   flaps{history: append([]Index{}, member.Flaps...)},
+			history: append([]Transition{}, member.History...),
+			start:   now,
+			index:   -1,
+			visits:  map[Index]int{},
+		}
+		for k, v := range member.Visits {
+			restored.visits[k] = v
+		}
+		if member.TTL > 0 {
+			restored.deadline = now + Time(member.TTL)
+			s.deadlines.enqueue(restored)
+		}
+
+		s.members[restored.id] = restored
+		s.bystate[restored.state][restored.id] = restored
+
+		if restored.id >= s.next {
+			s.next = restored.id + 1
+		}
+
+		log.Debug("Restored", "name", s.options.Name, "tid", tid, "id", restored.id,
+			"state", s.spec.StateName(restored.state), "deadline", restored.deadline)
+	}
+	if snapshot.Next > s.next {
+		s.next = snapshot.Next
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package fsm // import "github.com/docker/infrakit/pkg/fsm"
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memSnapshot is a store.Snapshot that keeps the json in memory
+type memSnapshot struct {
+	buff []byte
+}
+
+func (s *memSnapshot) Save(obj interface{}) (err error) {
+	s.buff, err = json.Marshal(obj)
+	return
+}
+
+func (s *memSnapshot) Load(obj interface{}) error {
+	if s.buff == nil {
+		return nil
+	}
+	return json.Unmarshal(s.buff, obj)
+}
+
+func (s *memSnapshot) Close() error {
+	return nil
+}
+
+func TestSetSnapshot(t *testing.T) {
+
+	const (
+		running Index = iota
+		wait
+	)
+
+	const (
+		start Signal = iota
+	)
+
+	spec, err := Define(
+		State{
+			Index: wait,
+			Transitions: map[Signal]Index{
+				start: running,
+			},
+			TTL: Expiry{5, start},
+		},
+		State{
+			Index: running,
+		},
+	)
+	require.NoError(t, err)
+
+	clock := NewClock()
+	set := NewSet(spec, clock)
+	defer set.Stop()
+
+	for i := 0; i < 10; i++ {
+		set.Add(wait)
+	}
+
+	clock.Ticks(2)
+	require.NoError(t, set.Get(ID(3)).Signal(start, "started"))
+	require.Equal(t, 1, set.CountByState(running))
+
+	store := &memSnapshot{}
+	require.NoError(t, set.Save(store))
+
+	// a new set, as after a restart
+	clock2 := NewClock()
+	set2 := NewSet(spec, clock2)
+	defer set2.Stop()
+
+	restored, err := set2.Load(store)
+	require.NoError(t, err)
+	require.Equal(t, 10, restored)
+	require.Equal(t, 10, set2.Size())
+	require.Equal(t, 1, set2.CountByState(running))
+	require.Equal(t, 9, set2.CountByState(wait))
+	require.Equal(t, []interface{}{"started"}, set2.Get(ID(3)).Data())
+
+	// the ids are not reused
+	require.Equal(t, ID(10), set2.Add(running).ID())
+
+	// restoring the same instances again fails
+	require.Error(t, set2.Restore(set.Snapshot()))
+
+	// the deadlines continue where they were left
+	clock2.Ticks(2)
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, 9, set2.CountByState(wait))
+
+	clock2.Ticks(1)
+	time.Sleep(1 * time.Second) // give a little time for the set to settle
+	require.Equal(t, 11, set2.CountByState(running))
+	require.Equal(t, 0, set2.CountByState(wait))
+
+	// nothing is restored from an empty store
+	restored, err = NewSet(spec, NewClock()).Load(&memSnapshot{})
+	require.NoError(t, err)
+	require.Equal(t, 0, restored)
+}
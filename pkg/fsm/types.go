@@ -1,5 +1,9 @@
 package fsm // import "github.com/docker/infrakit/pkg/fsm"
 
+import (
+	event_spi "github.com/docker/infrakit/pkg/spi/event"
+)
+
 // ID is the id of the instance in a given set.  It's unique in that set.
 type ID uint64
 
@@ -107,6 +111,9 @@ type Options struct {
 
 	// IgnoreUndefinedSignals will not report error from undefined signal for the state on Error() chan, if true
 	IgnoreUndefinedSignals bool
+
+	// HistorySize is the number of transitions kept for each instance.  Negative to keep none.
+	HistorySize int
 }
 
 type addOp struct {
@@ -127,10 +134,12 @@ type Set struct {
 	reads        chan func(Set) // given a view which is a copy of the Set
 	stop         chan struct{}
 	add          chan addOp
+	restore      chan restoreOp
 	delete       chan ID // delete an instance with id
 	errors       chan error
 	events       chan *event
 	transactions chan *txn
 	deadlines    *queue
+	published    chan *event_spi.Event
 	running      bool
 }
@@ -0,0 +1,176 @@
+package consul // import "github.com/docker/infrakit/pkg/leader/consul"
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/docker/infrakit/pkg/leader"
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/util/consul"
+)
+
+var log = logutil.New("module", "consul/leader")
+
+const (
+
+	// DefaultLockKey is the key locked by the session of the leader
+	DefaultLockKey = "infrakit/leader/lock"
+
+	// DefaultKey is the key used to persist the location
+	DefaultKey = "infrakit/leader/location"
+
+	// minSessionTTL is the minimum ttl of a consul session
+	minSessionTTL = 10 * time.Second
+)
+
+// Detector determines leadership by acquiring a lock on a key with a consul session.  The session
+// is renewed at each poll and expires if the node stops polling, so that another node can take over.
+type Detector struct {
+	*leader.Poller
+
+	client   *consul.Client
+	id       string
+	ttl      time.Duration
+	session  string
+	resigned time.Time
+	lock     sync.Mutex
+}
+
+// NewDetector return an implementation of leader detector.  The id identifies this node as the holder of the lock.
+func NewDetector(pollInterval time.Duration, client *consul.Client, id string) *Detector {
+	ttl := 3 * pollInterval
+	if ttl < minSessionTTL {
+		ttl = minSessionTTL
+	}
+	d := &Detector{
+		client: client,
+		id:     id,
+		ttl:    ttl,
+	}
+	d.Poller = leader.NewPoller(pollInterval, d.AmILeader)
+	return d
+}
+
+// AmILeader checks if this node is a leader by acquiring the lock.  The lock is held as long as the
+// session is valid so this returns true for the node that already holds it.
+func (d *Detector) AmILeader() (bool, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.session != "" {
+		err := d.client.RenewSession(d.session)
+		if err == consul.ErrNotFound {
+			log.Warn("Session expired", "session", d.session, "id", d.id)
+			d.session = ""
+		} else if err != nil {
+			return false, err
+		}
+	}
+
+	if d.session == "" {
+		session, err := d.client.CreateSession("infrakit-"+d.id, d.ttl)
+		if err != nil {
+			return false, err
+		}
+		log.Info("Created session", "session", session, "id", d.id, "ttl", d.ttl)
+		d.session = session
+	}
+
+	if time.Since(d.resigned) <= d.ttl {
+		// give the others the chance to acquire the lock after stepping down
+		return false, nil
+	}
+
+	isLeader, err := d.client.Acquire(DefaultLockKey, []byte(d.id), d.session)
+	log.Debug("Acquire lock", "key", DefaultLockKey, "session", d.session, "leader", isLeader, "err", err)
+	return isLeader, err
+}
+
+// CanStepDown returns an error if a successor is nominated, since the nodes race for the lock.
+func (d *Detector) CanStepDown(successor string) error {
+	if successor != "" {
+		return fmt.Errorf("cannot nominate %s: the nodes race for the lock", successor)
+	}
+	return nil
+}
+
+// StepDown releases the lock so that another node can acquire it.  This node does not try to acquire
+// the lock again for the ttl of the session.
+func (d *Detector) StepDown(successor string) error {
+	if err := d.CanStepDown(successor); err != nil {
+		return err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.session == "" {
+		return fmt.Errorf("%s is not the leader", d.id)
+	}
+	released, err := d.client.Release(DefaultLockKey, d.session)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return fmt.Errorf("%s is not the leader", d.id)
+	}
+	log.Info("Stepped down", "key", DefaultLockKey, "session", d.session, "id", d.id)
+	d.resigned = time.Now()
+	return nil
+}
+
+// Stop stops polling and gives up the leadership
+func (d *Detector) Stop() {
+	d.Poller.Stop()
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.session == "" {
+		return
+	}
+	// release first so that the lock is available to the others right away.  Destroying a session
+	// holding locks blocks the others from acquiring them until the lock delay has passed.
+	if _, err := d.client.Release(DefaultLockKey, d.session); err != nil {
+		log.Warn("Cannot release lock", "key", DefaultLockKey, "session", d.session, "err", err)
+	}
+	if err := d.client.DestroySession(d.session); err != nil {
+		log.Warn("Cannot destroy session", "session", d.session, "err", err)
+	}
+	d.session = ""
+}
+
+// Store uses consul as the backend for registration of leader location
+type Store struct {
+	client *consul.Client
+}
+
+// NewStore returns a store for registration of leader location
+func NewStore(c *consul.Client) leader.Store {
+	return &Store{client: c}
+}
+
+// UpdateLocation writes the location to consul.
+func (s Store) UpdateLocation(location *url.URL) error {
+	err := s.client.Put(DefaultKey, []byte(location.String()))
+	if err != nil {
+		log.Warn("Cannot update location", "err", err)
+	}
+	return err
+}
+
+// GetLocation returns the location of the leader
+func (s Store) GetLocation() (*url.URL, error) {
+	pair, err := s.client.Get(DefaultKey)
+	if err != nil {
+		log.Warn("Cannot get location", "err", err)
+		return nil, err
+	}
+	if pair == nil {
+		// no data. therefore no effect on the input
+		return nil, nil
+	}
+	return url.Parse(string(pair.Value))
+}
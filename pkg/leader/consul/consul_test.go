@@ -0,0 +1,104 @@
+package consul // import "github.com/docker/infrakit/pkg/leader/consul"
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	testutil "github.com/docker/infrakit/pkg/testing"
+	"github.com/docker/infrakit/pkg/util/consul"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRealConsul(t *testing.T) {
+
+	if testutil.SkipTests("consul") {
+		t.SkipNow()
+	}
+
+	containerName := "test-consul-leader"
+
+	err := consul.RunContainer.Start(containerName)
+	require.NoError(t, err)
+
+	defer consul.StopContainer.Start(containerName)
+
+	client := testClient(t)
+
+	// wait until ready
+	for {
+		<-time.After(1 * time.Second)
+		if _, err := client.Get(DefaultKey); err == nil {
+			log.Info("consul running")
+			break
+		}
+	}
+
+	t.Run("AmILeader", testAmILeader)
+
+	t.Run("StoreTest", testStore)
+}
+
+func testClient(t *testing.T) *consul.Client {
+	client, err := consul.NewClient(consul.Options{
+		Address:        "127.0.0.1:8500",
+		RequestTimeout: 1 * time.Second,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func testStore(t *testing.T) {
+
+	client := testClient(t)
+	defer client.Close()
+
+	store := NewStore(client)
+
+	loc := "tcp://10.10.1.100:24864"
+	u, err := url.Parse(loc)
+	require.NoError(t, err)
+
+	err = store.UpdateLocation(u)
+	require.NoError(t, err)
+
+	uu, err := store.GetLocation()
+	require.NoError(t, err)
+
+	require.Equal(t, u, uu)
+}
+
+func testAmILeader(t *testing.T) {
+
+	client := testClient(t)
+	defer client.Close()
+
+	manager1 := NewDetector(1*time.Second, client, "manager1")
+	manager2 := NewDetector(1*time.Second, client, "manager2")
+
+	isLeader, err := manager1.AmILeader()
+	require.NoError(t, err)
+	require.True(t, isLeader)
+
+	isLeader, err = manager2.AmILeader()
+	require.NoError(t, err)
+	require.False(t, isLeader)
+
+	// still the leader at the next poll
+	isLeader, err = manager1.AmILeader()
+	require.NoError(t, err)
+	require.True(t, isLeader)
+
+	// steps down and the other takes over
+	manager1.Stop()
+
+	isLeader, err = manager2.AmILeader()
+	require.NoError(t, err)
+	require.True(t, isLeader)
+
+	pair, err := client.Get(DefaultLockKey)
+	require.NoError(t, err)
+	require.Equal(t, "manager2", string(pair.Value))
+
+	manager2.Stop()
+}
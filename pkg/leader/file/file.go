@@ -17,30 +17,65 @@ var (
 	debugV = logutil.V(1000)
 )
 
+// Detector is the leader detector that matches the content of a file to the id of this instance
+type Detector struct {
+	*leader.Poller
+
+	filename string
+	id       string
+}
+
 // NewDetector return an implementation of leader detector
 // This implementation checks a file for its content.  If the content matches the id of the detector
 // then this instance is the leader.
-func NewDetector(pollInterval time.Duration, filename, id string) (*leader.Poller, error) {
+func NewDetector(pollInterval time.Duration, filename, id string) (*Detector, error) {
 	// file must exist
 	info, err := os.Stat(filename)
 	if err != nil {
 		return nil, err
 	}
-
 	if info.IsDir() {
 		return nil, fmt.Errorf("file %s must be a file", filename)
 	}
 
-	return leader.NewPoller(pollInterval,
-		func() (bool, error) {
-			content, err := ioutil.ReadFile(filename)
+	d := &Detector{
+		filename: filename,
+		id:       id,
+	}
+	d.Poller = leader.NewPoller(pollInterval, d.AmILeader)
+	return d, nil
+}
 
-			match := strings.Trim(string(content), " \t\n")
+// AmILeader returns true if the content of the file matches the id
+func (d *Detector) AmILeader() (bool, error) {
+	content, err := ioutil.ReadFile(d.filename)
+	match := strings.Trim(string(content), " \t\n")
+	log.Debug("poll for leadership", "id", d.id, "file", d.filename, "match", match, "err", err, "V", debugV)
+	return match == d.id, err
+}
 
-			log.Debug("poll for leadership", "id", id, "file", filename, "match", match, "err", err, "V", debugV)
+// CanStepDown returns an error if the successor is empty, since the leader is whoever is named in the file.
+func (d *Detector) CanStepDown(successor string) error {
+	if successor == "" {
+		return fmt.Errorf("successor required: the leader is the node named in %s", d.filename)
+	}
+	return nil
+}
 
-			return match == id, err
-		}), nil
+// StepDown hands the leadership to the successor by writing its id to the file.
+func (d *Detector) StepDown(successor string) error {
+	if err := d.CanStepDown(successor); err != nil {
+		return err
+	}
+	isLeader, err := d.AmILeader()
+	if err != nil {
+		return err
+	}
+	if !isLeader {
+		return fmt.Errorf("%s is not the leader", d.id)
+	}
+	log.Info("Stepping down", "id", d.id, "successor", successor, "file", d.filename)
+	return ioutil.WriteFile(d.filename, []byte(successor), 0644)
 }
 
 // Store is the location of a file that stores the location of the leader
@@ -103,3 +103,32 @@ loop:
 
 	require.Equal(t, []string{"instance1", "instance2"}, leader)
 }
+
+func TestFileDetectorStepDown(t *testing.T) {
+
+	file, err := ioutil.TempFile(os.TempDir(), "infrakit-file-test")
+	require.NoError(t, err)
+
+	err = ioutil.WriteFile(file.Name(), []byte("instance1\n"), 0644)
+	require.NoError(t, err)
+
+	detector1, err := NewDetector(10*time.Millisecond, file.Name(), "instance1")
+	require.NoError(t, err)
+	detector2, err := NewDetector(10*time.Millisecond, file.Name(), "instance2")
+	require.NoError(t, err)
+
+	require.Error(t, detector1.CanStepDown(""))
+	require.NoError(t, detector1.CanStepDown("instance2"))
+	require.Error(t, detector1.StepDown(""))
+	require.Error(t, detector2.StepDown("instance1"))
+
+	require.NoError(t, detector1.StepDown("instance2"))
+
+	isLeader, err := detector1.AmILeader()
+	require.NoError(t, err)
+	require.False(t, isLeader)
+
+	isLeader, err = detector2.AmILeader()
+	require.NoError(t, err)
+	require.True(t, isLeader)
+}
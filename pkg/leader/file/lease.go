@@ -0,0 +1,258 @@
+package file // import "github.com/docker/infrakit/pkg/leader/file"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/docker/infrakit/pkg/leader"
+)
+
+const (
+	// mutexWait is how long to wait for the mutex guarding the lease file
+	mutexWait = 1 * time.Second
+
+	// mutexStale is the age of a mutex file after which its owner is assumed to be gone
+	mutexStale = 10 * time.Second
+)
+
+// Lease is the content of the lease file
+type Lease struct {
+
+	// Holder is the id of the leader
+	Holder string
+
+	// Token is the fencing token.  It's incremented each time a different node takes over.
+	Token uint64
+
+	// Renewed is the last heartbeat of the holder
+	Renewed time.Time
+
+	// TTL is how long the lease is valid after the last heartbeat
+	TTL time.Duration
+
+	// Nominee is the id of the node the leader stepped down for.  Only the nominee can take over
+	// until the TTL has passed.
+	Nominee string `json:",omitempty"`
+}
+
+// expired returns true if the lease has no holder or the holder has not renewed it in time
+func (l Lease) expired(now time.Time) bool {
+	return l.Holder == "" || now.Sub(l.Renewed) > l.TTL
+}
+
+// LeaseDetector elects the leader with a lease in a file on a shared filesystem.  The leader renews
+// the lease at each poll.  If the lease is not renewed within the TTL, the next node that polls takes over
+// and increments the fencing token.  Updates of the lease are guarded by a mutex file that is created exclusively.
+type LeaseDetector struct {
+	*leader.Poller
+
+	filename string
+	id       string
+	ttl      time.Duration
+	token    uint64
+	resigned time.Time
+	now      func() time.Time
+	lock     sync.Mutex
+}
+
+// NewLeaseDetector returns a detector that holds a lease in the given file while this node is the leader.
+// The ttl must be longer than the poll interval.
+func NewLeaseDetector(pollInterval, ttl time.Duration, filename, id string) (*LeaseDetector, error) {
+	if ttl <= pollInterval {
+		return nil, fmt.Errorf("lease ttl %v must be longer than the poll interval %v", ttl, pollInterval)
+	}
+
+	info, err := os.Stat(filepath.Dir(filename))
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s must be a directory", filepath.Dir(filename))
+	}
+
+	d := &LeaseDetector{
+		filename: filename,
+		id:       id,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+	d.Poller = leader.NewPoller(pollInterval, d.AmILeader)
+	return d, nil
+}
+
+// AmILeader renews the lease if this node holds it or takes it over if it has expired.
+func (d *LeaseDetector) AmILeader() (isLeader bool, err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	err = d.withMutex(func() error {
+		lease := d.readLease()
+		now := d.now()
+
+		switch {
+		case lease.Holder == d.id:
+			// still ours, even if expired, since nobody has taken over
+		case lease.expired(now) && d.mayTakeOver(lease, now):
+			log.Info("Taking over lease", "id", d.id, "previous", lease.Holder, "token", lease.Token+1)
+			lease.Holder = d.id
+			lease.Token++
+			lease.Nominee = ""
+		default:
+			return nil
+		}
+
+		lease.Renewed = now
+		lease.TTL = d.ttl
+		if err := d.writeLease(lease); err != nil {
+			return err
+		}
+		d.token = lease.Token
+		isLeader = true
+		return nil
+	})
+
+	log.Debug("poll for leadership", "id", d.id, "file", d.filename, "leader", isLeader, "err", err, "V", debugV)
+	return
+}
+
+// mayTakeOver returns false while the nominee of the last leader or the others have the chance to take over
+func (d *LeaseDetector) mayTakeOver(lease Lease, now time.Time) bool {
+	if lease.Nominee != "" && lease.Nominee != d.id && now.Sub(lease.Renewed) <= lease.TTL {
+		return false
+	}
+	return now.Sub(d.resigned) > d.ttl
+}
+
+// CanStepDown returns nil since the lease can be released with or without a successor.
+func (d *LeaseDetector) CanStepDown(successor string) error {
+	return nil
+}
+
+// StepDown releases the lease.  If the successor is not empty, only the successor can take over until
+// the TTL has passed.  This node does not take the lease back before the TTL has passed.
+func (d *LeaseDetector) StepDown(successor string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.withMutex(func() error {
+		lease := d.readLease()
+		if lease.Holder != d.id {
+			return fmt.Errorf("%s does not hold the lease", d.id)
+		}
+		log.Info("Stepping down", "id", d.id, "successor", successor, "token", lease.Token)
+
+		now := d.now()
+		lease.Holder = ""
+		lease.Nominee = successor
+		lease.Renewed = now
+		if err := d.writeLease(lease); err != nil {
+			return err
+		}
+		d.resigned = now
+		return nil
+	})
+}
+
+// Token returns the fencing token of the last term this node held the lease
+func (d *LeaseDetector) Token() uint64 {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.token
+}
+
+// Fence calls the write function only if the token is the token of the lease held by this node
+func (d *LeaseDetector) Fence(token uint64, write func() error) error {
+	return d.withMutex(func() error {
+		lease := d.readLease()
+		if lease.Holder != d.id || lease.Token != token {
+			log.Warn("Fenced write", "id", d.id, "token", token, "holder", lease.Holder, "current", lease.Token)
+			return leader.ErrFenced
+		}
+		return write()
+	})
+}
+
+// Stop stops polling and releases the lease so that another node can take over without waiting for the TTL.
+func (d *LeaseDetector) Stop() {
+	d.Poller.Stop()
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	err := d.withMutex(func() error {
+		lease := d.readLease()
+		if lease.Holder != d.id {
+			return nil
+		}
+		lease.Renewed = time.Time{}
+		return d.writeLease(lease)
+	})
+	if err != nil {
+		log.Warn("Cannot release lease", "id", d.id, "file", d.filename, "err", err)
+	}
+}
+
+func (d *LeaseDetector) readLease() Lease {
+	lease := Lease{}
+	buff, err := ioutil.ReadFile(d.filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn("Cannot read lease", "file", d.filename, "err", err)
+		}
+		return lease
+	}
+	if err := json.Unmarshal(buff, &lease); err != nil {
+		// e.g. the file of the detector that matches the content to the id
+		log.Warn("Ignoring bad lease", "file", d.filename, "err", err)
+		return Lease{}
+	}
+	return lease
+}
+
+// writeLease replaces the lease file so that readers never see a partial write
+func (d *LeaseDetector) writeLease(lease Lease) error {
+	buff, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.%s.tmp", d.filename, d.id)
+	if err := ioutil.WriteFile(tmp, buff, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.filename)
+}
+
+// withMutex calls the function while holding the mutex file.  A mutex file left behind by a node that
+// crashed is removed once it's stale.
+func (d *LeaseDetector) withMutex(f func() error) error {
+	mutex := d.filename + ".lock"
+	deadline := time.Now().Add(mutexWait)
+	for {
+		file, err := os.OpenFile(mutex, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			file.Close()
+			break
+		}
+		if !os.IsExist(err) {
+			return err
+		}
+		if info, err := os.Stat(mutex); err == nil && time.Since(info.ModTime()) > mutexStale {
+			log.Warn("Removing stale mutex", "file", mutex)
+			os.Remove(mutex)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s", mutex)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer os.Remove(mutex)
+
+	return f()
+}
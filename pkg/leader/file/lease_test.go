@@ -0,0 +1,144 @@
+package file // import "github.com/docker/infrakit/pkg/leader/file"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/infrakit/pkg/leader"
+	"github.com/stretchr/testify/require"
+)
+
+func testLeaseDetectors(t *testing.T, ids ...string) ([]*LeaseDetector, *time.Time) {
+	dir, err := ioutil.TempDir("", "infrakit-lease-test")
+	require.NoError(t, err)
+
+	now := time.Now()
+	detectors := []*LeaseDetector{}
+	for _, id := range ids {
+		d, err := NewLeaseDetector(1*time.Second, 5*time.Second, filepath.Join(dir, "leader"), id)
+		require.NoError(t, err)
+		d.now = func() time.Time { return now }
+		detectors = append(detectors, d)
+	}
+	return detectors, &now
+}
+
+func requireLeader(t *testing.T, d *LeaseDetector, expect bool) {
+	isLeader, err := d.AmILeader()
+	require.NoError(t, err)
+	require.Equal(t, expect, isLeader)
+}
+
+func TestLeaseDetector(t *testing.T) {
+
+	_, err := NewLeaseDetector(5*time.Second, 5*time.Second, filepath.Join(os.TempDir(), "leader"), "m1")
+	require.Error(t, err)
+
+	detectors, now := testLeaseDetectors(t, "m1", "m2")
+	m1, m2 := detectors[0], detectors[1]
+
+	requireLeader(t, m1, true)
+	requireLeader(t, m2, false)
+	require.Equal(t, uint64(1), m1.Token())
+	require.Equal(t, uint64(0), m2.Token())
+
+	// m1 keeps renewing
+	for i := 0; i < 10; i++ {
+		*now = now.Add(1 * time.Second)
+		requireLeader(t, m1, true)
+		requireLeader(t, m2, false)
+	}
+	require.Equal(t, uint64(1), m1.Token())
+
+	// m1 stops heartbeating and m2 takes over after the ttl
+	*now = now.Add(5 * time.Second)
+	requireLeader(t, m2, false)
+	*now = now.Add(1 * time.Second)
+	requireLeader(t, m2, true)
+	require.Equal(t, uint64(2), m2.Token())
+
+	// m1 comes back and finds it's deposed
+	requireLeader(t, m1, false)
+
+	written := []string{}
+	write := func(who string) func() error {
+		return func() error {
+			written = append(written, who)
+			return nil
+		}
+	}
+	require.Equal(t, leader.ErrFenced, m1.Fence(m1.Token(), write("m1")))
+	require.NoError(t, m2.Fence(m2.Token(), write("m2")))
+	require.Equal(t, []string{"m2"}, written)
+
+	// the lease is released on stop so m1 takes over without waiting for the ttl
+	m2.Stop()
+	requireLeader(t, m1, true)
+	require.Equal(t, uint64(3), m1.Token())
+	require.Equal(t, leader.ErrFenced, m2.Fence(m2.Token(), write("m2")))
+}
+
+func TestLeaseDetectorIgnoresLeaderFile(t *testing.T) {
+	detectors, _ := testLeaseDetectors(t, "m1")
+	m1 := detectors[0]
+
+	// the leader file of the detector that matches the content
+	require.NoError(t, ioutil.WriteFile(m1.filename, []byte("m2"), 0644))
+	requireLeader(t, m1, true)
+	require.Equal(t, uint64(1), m1.Token())
+}
+
+func TestLeaseDetectorStaleMutex(t *testing.T) {
+	detectors, _ := testLeaseDetectors(t, "m1")
+	m1 := detectors[0]
+
+	mutex := m1.filename + ".lock"
+	require.NoError(t, ioutil.WriteFile(mutex, nil, 0644))
+
+	// held by another node
+	_, err := m1.AmILeader()
+	require.Error(t, err)
+
+	// left behind by a crashed node
+	stale := time.Now().Add(-2 * mutexStale)
+	require.NoError(t, os.Chtimes(mutex, stale, stale))
+	requireLeader(t, m1, true)
+
+	_, err = os.Stat(mutex)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestLeaseDetectorStepDown(t *testing.T) {
+
+	detectors, now := testLeaseDetectors(t, "m1", "m2", "m3")
+	m1, m2, m3 := detectors[0], detectors[1], detectors[2]
+
+	requireLeader(t, m1, true)
+	require.Error(t, m2.StepDown(""))
+
+	// only the nominee takes over
+	require.NoError(t, m1.StepDown("m3"))
+	requireLeader(t, m1, false)
+	requireLeader(t, m2, false)
+	requireLeader(t, m3, true)
+	require.Equal(t, uint64(2), m3.Token())
+
+	// without a nominee anyone who has not stepped down recently takes over
+	*now = now.Add(1 * time.Second)
+	require.NoError(t, m3.StepDown(""))
+	requireLeader(t, m3, false)
+	requireLeader(t, m1, false)
+	requireLeader(t, m2, true)
+	require.Equal(t, uint64(3), m2.Token())
+
+	// the nominee did not show up in time
+	*now = now.Add(1 * time.Second)
+	require.NoError(t, m2.StepDown("m1"))
+	*now = now.Add(5 * time.Second)
+	requireLeader(t, m3, false)
+	*now = now.Add(1 * time.Second)
+	requireLeader(t, m3, true)
+}
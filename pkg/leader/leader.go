@@ -1,6 +1,7 @@
 package leader // import "github.com/docker/infrakit/pkg/leader"
 
 import (
+	"errors"
 	"net/url"
 )
 
@@ -54,6 +55,35 @@ type Detector interface {
 	Stop()
 }
 
+// Handoff is implemented by detectors that can give up the leadership voluntarily, e.g. before the host
+// of the leader is taken down.
+type Handoff interface {
+
+	// CanStepDown returns an error if the leadership cannot be handed to the successor.  It is checked
+	// before the leader stops its controllers so that a step down the backend rejects causes no outage.
+	CanStepDown(successor string) error
+
+	// StepDown releases the leadership held by this instance.  If the successor is not empty, the leadership
+	// is handed to the instance of that id.  An error is returned if the backend cannot nominate a successor.
+	StepDown(successor string) error
+}
+
+// ErrFenced is returned when a write carries the fencing token of a term of leadership that has ended
+var ErrFenced = errors.New("fenced: not the current leader")
+
+// Fencing is implemented by detectors that issue a monotonically increasing fencing token for each
+// term of leadership.  Writes made with the token of an earlier term are rejected so that a deposed
+// leader that still believes it leads cannot overwrite the state written by the new leader.
+type Fencing interface {
+
+	// Token returns the fencing token of the current term, or 0 if this instance has not been the leader.
+	Token() uint64
+
+	// Fence calls the write function only if the token is the token of the current term.  Otherwise
+	// ErrFenced is returned.
+	Fence(token uint64, write func() error) error
+}
+
 // Always is a trivial implementation that asserts the current instance to always be the leader (or not)
 func Always(leader bool) CheckLeaderFunc {
 	return func() (bool, error) {
@@ -1,6 +1,7 @@
 package swarm // import "github.com/docker/infrakit/pkg/leader/swarm"
 
 import (
+	"fmt"
 	"net/url"
 	"time"
 
@@ -16,11 +17,62 @@ var (
 	debugV = logutil.V(1000)
 )
 
+// Detector determines leadership by checking if this node is the leader of the swarm managers
+type Detector struct {
+	*leader.Poller
+
+	// Demote allows StepDown to demote this node to a worker.  It is off by default since the node leaves
+	// the managers of the swarm until it is promoted again.
+	Demote bool
+
+	client docker.APIClientCloser
+}
+
 // NewDetector return an implementation of leader detector
-func NewDetector(pollInterval time.Duration, client docker.APIClientCloser) *leader.Poller {
-	return leader.NewPoller(pollInterval, func() (bool, error) {
+func NewDetector(pollInterval time.Duration, client docker.APIClientCloser) *Detector {
+	d := &Detector{client: client}
+	d.Poller = leader.NewPoller(pollInterval, func() (bool, error) {
 		return amISwarmLeader(context.Background(), client)
 	})
+	return d
+}
+
+// CanStepDown returns an error if Demote is not set or a successor is nominated, since the swarm elects
+// the leader among the managers.
+func (d *Detector) CanStepDown(successor string) error {
+	if !d.Demote {
+		return fmt.Errorf("stepping down demotes this node to a worker and is not enabled")
+	}
+	if successor != "" {
+		return fmt.Errorf("cannot nominate %s: the leader is elected by the swarm managers", successor)
+	}
+	return nil
+}
+
+// StepDown demotes this node to a worker so that the remaining managers elect a new leader, if Demote is
+// set.  The node can be promoted again with docker node promote once the new leader is elected.
+func (d *Detector) StepDown(successor string) error {
+	if err := d.CanStepDown(successor); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	info, err := d.client.Info(ctx)
+	if err != nil {
+		return err
+	}
+	node, _, err := d.client.NodeInspectWithRaw(ctx, info.Swarm.NodeID)
+	if err != nil {
+		return err
+	}
+	if node.ManagerStatus == nil || !node.ManagerStatus.Leader {
+		return fmt.Errorf("node %s is not the leader", node.ID)
+	}
+
+	log.Info("Stepping down by demoting node", "node", node.ID)
+	spec := node.Spec
+	spec.Role = swarm.NodeRoleWorker
+	return d.client.NodeUpdate(ctx, node.ID, node.Version, spec)
 }
 
 // amISwarmLeader determines if the current node is the swarm manager leader
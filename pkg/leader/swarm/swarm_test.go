@@ -95,3 +95,41 @@ func TestSwarmStore(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, u.String(), uu.String())
 }
+
+func TestSwarmStepDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	nodeInfo := types.Info{
+		Swarm: swarm.Info{
+			NodeID: "node",
+		},
+	}
+	node := swarm.Node{
+		ID:   "node",
+		Meta: swarm.Meta{Version: swarm.Version{Index: 10}},
+		Spec: swarm.NodeSpec{Role: swarm.NodeRoleManager},
+		ManagerStatus: &swarm.ManagerStatus{
+			Leader: true,
+		},
+	}
+
+	mock := docker_mock.NewMockAPIClientCloser(ctrl)
+	detector := NewDetector(10*time.Millisecond, mock)
+
+	require.Error(t, detector.CanStepDown(""))
+	require.Error(t, detector.StepDown(""))
+
+	detector.Demote = true
+	require.NoError(t, detector.CanStepDown(""))
+	require.Error(t, detector.CanStepDown("node2"))
+	require.Error(t, detector.StepDown("node2"))
+
+	mock.EXPECT().Info(ctx).Return(nodeInfo, nil)
+	mock.EXPECT().NodeInspectWithRaw(ctx, "node").Return(node, nil, nil)
+	mock.EXPECT().NodeUpdate(ctx, "node", swarm.Version{Index: 10},
+		swarm.NodeSpec{Role: swarm.NodeRoleWorker}).Return(nil)
+
+	require.NoError(t, detector.StepDown(""))
+}
@@ -59,6 +59,17 @@ type Options struct {
 	// SpecStore persists user specs
 	SpecStore store.Snapshot `json:"-" yaml:"-"`
 
+	// HistoryStore persists the revisions of the user specs
+	HistoryStore store.Snapshot `json:"-" yaml:"-"`
+
+	// HistoryLimit is the number of revisions to keep.  All revisions are kept if 0.
+	HistoryLimit int
+
+	// HistorySizeLimit is the size in bytes of the encoded history.  The oldest revisions are dropped
+	// to keep the history under the size, e.g. for stores that keep it in a single value of bounded size.
+	// The size is not limited if 0.
+	HistorySizeLimit int
+
 	// MetadataStore persists var information
 	MetadataStore store.Snapshot `json:"-" yaml:"-"`
 
@@ -67,4 +78,8 @@ type Options struct {
 
 	// LeaderCommitSpecsRetryInterval is how long to wait before next retry
 	LeaderCommitSpecsRetryInterval types.Duration
+
+	// UpdatePollInterval is how often the updates of the groups committed are polled, so that the spec a
+	// group is rolled back to is stored if the update fails.  Defaults to 5s if not set.
+	UpdatePollInterval types.Duration
 }
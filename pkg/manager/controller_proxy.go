@@ -1,8 +1,11 @@
 package manager // import "github.com/docker/infrakit/pkg/manager"
 
 import (
+	"context"
+
 	"github.com/docker/infrakit/pkg/controller/group"
 	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/spi/controller"
 	"github.com/docker/infrakit/pkg/types"
 )
@@ -36,14 +39,15 @@ func (m *manager) Controllers() (map[string]controller.Controller, error) {
 			name:    c,
 			manager: m,
 			backend: control,
+			ctx:     context.Background(),
 		}
 	}
 	log.Debug("Controllers", "map", controllers, "V", debugV3)
 	return controllers, nil
 }
 
-func (m *manager) updateSpec(spec types.Spec, handler plugin.Name) error {
-	log.Debug("Updating config", "spec", spec)
+func (m *manager) updateSpec(spec types.Spec, handler plugin.Name, author string) error {
+	log.Debug("Updating config", "spec", spec, "author", author)
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -59,11 +63,15 @@ func (m *manager) updateSpec(spec types.Spec, handler plugin.Name) error {
 	defer log.Debug("Saved snapshot", "global", stored, "spec", spec)
 
 	stored.updateSpec(spec, handler)
-	return stored.store(m.Options.SpecStore)
+	if err := stored.store(m.Options.SpecStore); err != nil {
+		return err
+	}
+	m.recordRevision(stored, author)
+	return nil
 }
 
-func (m *manager) removeSpec(spec types.Spec) error {
-	log.Debug("Removing config", "metadata", spec)
+func (m *manager) removeSpec(spec types.Spec, author string) error {
+	log.Debug("Removing config", "metadata", spec, "author", author)
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -78,13 +86,26 @@ func (m *manager) removeSpec(spec types.Spec) error {
 	defer log.Debug("Saved snapshot", "global", stored, "spec", spec)
 
 	stored.removeSpec(spec.Kind, spec.Metadata)
-	return stored.store(m.Options.SpecStore)
+	if err := stored.store(m.Options.SpecStore); err != nil {
+		return err
+	}
+	m.recordRevision(stored, author)
+	return nil
 }
 
 type controllerAdapter struct {
 	name    plugin.Name
 	manager *manager
 	backend controller.Controller
+	ctx     context.Context
+}
+
+// WithContext implements spi.Contextual.  The caller of the context is recorded as the author of the
+// specs committed and the backend is called in the context.
+func (m controllerAdapter) WithContext(ctx context.Context) interface{} {
+	m.ctx = ctx
+	m.backend = spi.WithContext(m.backend, ctx).(controller.Controller)
+	return m
 }
 
 // Plan implements Controller.Plan
@@ -116,9 +137,9 @@ func (m controllerAdapter) Commit(op controller.Operation, spec types.Spec) (obj
 
 	switch op {
 	case controller.Enforce:
-		err = m.manager.updateSpec(spec, m.name)
+		err = m.manager.updateSpec(spec, m.name, caller(m.ctx))
 	case controller.Destroy:
-		err = m.manager.removeSpec(spec)
+		err = m.manager.removeSpec(spec, caller(m.ctx))
 	}
 	if err != nil {
 		return
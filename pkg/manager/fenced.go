@@ -0,0 +1,39 @@
+package manager // import "github.com/docker/infrakit/pkg/manager"
+
+import (
+	"github.com/docker/infrakit/pkg/leader"
+	"github.com/docker/infrakit/pkg/store"
+)
+
+// fencedSnapshot attaches the fencing token of the manager's term of leadership to the writes, so that
+// a deposed leader cannot overwrite what the new leader has written.
+type fencedSnapshot struct {
+	store.Snapshot
+
+	fencing leader.Fencing
+	token   func() uint64
+}
+
+// Save saves the object only if the token is still the token of the current term
+func (s fencedSnapshot) Save(obj interface{}) error {
+	return s.fencing.Fence(s.token(), func() error {
+		return s.Snapshot.Save(obj)
+	})
+}
+
+// fenceStores wraps the stores if the leader detector issues fencing tokens
+func fenceStores(options *Options, token func() uint64) {
+	fencing, is := options.Leader.(leader.Fencing)
+	if !is {
+		return
+	}
+	fence := func(s store.Snapshot) store.Snapshot {
+		if s == nil {
+			return nil
+		}
+		return fencedSnapshot{Snapshot: s, fencing: fencing, token: token}
+	}
+	options.SpecStore = fence(options.SpecStore)
+	options.HistoryStore = fence(options.HistoryStore)
+	options.MetadataStore = fence(options.MetadataStore)
+}
@@ -0,0 +1,62 @@
+package manager // import "github.com/docker/infrakit/pkg/manager"
+
+import (
+	"testing"
+
+	"github.com/docker/infrakit/pkg/leader"
+	"github.com/stretchr/testify/require"
+)
+
+type testFencingDetector struct {
+	testLeaderDetector
+	token uint64
+}
+
+func (l *testFencingDetector) Token() uint64 {
+	return l.token
+}
+
+func (l *testFencingDetector) Fence(token uint64, write func() error) error {
+	if token != l.token {
+		return leader.ErrFenced
+	}
+	return write()
+}
+
+func TestFenceStores(t *testing.T) {
+	saved := []interface{}{}
+	snapshot := fakeSnapshot{
+		SaveFunc: func(obj interface{}) error {
+			saved = append(saved, obj)
+			return nil
+		},
+	}
+
+	options := Options{Leader: &testLeaderDetector{}, SpecStore: snapshot}
+	fenceStores(&options, func() uint64 { return 1 })
+	_, is := options.SpecStore.(fencedSnapshot)
+	require.False(t, is)
+	require.Nil(t, options.HistoryStore)
+
+	detector := &testFencingDetector{token: 1}
+	options = Options{Leader: detector, SpecStore: snapshot}
+
+	token := uint64(1)
+	fenceStores(&options, func() uint64 { return token })
+	_, is = options.SpecStore.(fencedSnapshot)
+	require.True(t, is)
+	require.Nil(t, options.HistoryStore)
+
+	require.NoError(t, options.SpecStore.Save("first"))
+	require.Equal(t, []interface{}{"first"}, saved)
+
+	// another node took over the lease
+	detector.token = 2
+	require.Equal(t, leader.ErrFenced, options.SpecStore.Save("second"))
+	require.Equal(t, []interface{}{"first"}, saved)
+
+	// leadership is assumed again with the new token
+	token = 2
+	require.NoError(t, options.SpecStore.Save("third"))
+	require.Equal(t, []interface{}{"first", "third"}, saved)
+}
@@ -1,14 +1,25 @@
 package manager // import "github.com/docker/infrakit/pkg/manager"
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	group_types "github.com/docker/infrakit/pkg/controller/group/types"
+	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/spi/group"
 	"github.com/docker/infrakit/pkg/spi/instance"
 	"github.com/docker/infrakit/pkg/types"
 )
 
+const (
+	// defaultUpdatePollInterval is how often the updates of the groups are polled if not set in the options
+	defaultUpdatePollInterval = 5 * time.Second
+
+	// rollbackAuthor is the author of the revisions stored when the group plugin rolls back an update
+	rollbackAuthor = "rollback"
+)
+
 // Groups returns a map of *scoped* group controllers by ID of the group.
 func (m *manager) Groups() (map[group.ID]group.Plugin, error) {
 	groups := map[group.ID]group.Plugin{
@@ -27,6 +38,11 @@ func (m *manager) Groups() (map[group.ID]group.Plugin, error) {
 	return groups, nil
 }
 
+// backend returns the group plugin backend used in the context
+func (m *manager) backend(ctx context.Context) group.Plugin {
+	return spi.WithContext(m.Plugin, ctx).(group.Plugin)
+}
+
 func (m *manager) loadGroupSpec(id group.ID) (group.Spec, error) {
 	// load the config
 	config := globalSpec{}
@@ -40,8 +56,8 @@ func (m *manager) loadGroupSpec(id group.ID) (group.Spec, error) {
 	return config.getGroupSpec(id)
 }
 
-func (m *manager) updateConfig(spec group.Spec) error {
-	log.Debug("Updating config", "spec", spec)
+func (m *manager) updateConfig(spec group.Spec, author string) error {
+	log.Debug("Updating config", "spec", spec, "author", author)
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -57,11 +73,15 @@ func (m *manager) updateConfig(spec group.Spec) error {
 	defer log.Debug("Saved snapshot", "global", stored, "spec", spec)
 
 	stored.updateGroupSpec(spec, m.Options.Group)
-	return stored.store(m.Options.SpecStore)
+	if err := stored.store(m.Options.SpecStore); err != nil {
+		return err
+	}
+	m.recordRevision(stored, author)
+	return nil
 }
 
-func (m *manager) removeConfig(id group.ID) error {
-	log.Debug("Removing config", "groupID", id)
+func (m *manager) removeConfig(id group.ID, author string) error {
+	log.Debug("Removing config", "groupID", id, "author", author)
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -76,17 +96,30 @@ func (m *manager) removeConfig(id group.ID) error {
 	defer log.Debug("Saved snapshot", "global", stored, "id", id)
 
 	stored.removeGroup(id)
-	return stored.store(m.Options.SpecStore)
+	if err := stored.store(m.Options.SpecStore); err != nil {
+		return err
+	}
+	m.recordRevision(stored, author)
+	return nil
 }
 
 // This implements/ overrides the Group Plugin interface to support single group-only operations
-func (m *manager) CommitGroup(grp group.Spec, pretend bool) (resp string, err error) {
+func (m *manager) CommitGroup(grp group.Spec, pretend bool) (string, error) {
+	return m.commitGroup(context.Background(), grp, pretend)
+}
+
+func (m *managerInContext) CommitGroup(grp group.Spec, pretend bool) (string, error) {
+	return m.commitGroup(m.ctx, grp, pretend)
+}
+
+func (m *manager) commitGroup(ctx context.Context, grp group.Spec, pretend bool) (resp string, err error) {
 
 	if is, errLeader := m.IsLeader(); errLeader != nil || !is {
 		err = errNotLeader
 		return
 	}
 
+	author := caller(ctx)
 	retry := false
 	<-m.queue("commit",
 		func() (bool, error) {
@@ -94,7 +127,7 @@ func (m *manager) CommitGroup(grp group.Spec, pretend bool) (resp string, err er
 
 			// We first update the user's desired state first
 			if !pretend {
-				if updateErr := m.updateConfig(grp); updateErr != nil {
+				if updateErr := m.updateConfig(grp, author); updateErr != nil {
 					log.Error("Error updating", "err", updateErr)
 					resp = "Cannot update spec. Abort"
 					err = updateErr
@@ -102,7 +135,10 @@ func (m *manager) CommitGroup(grp group.Spec, pretend bool) (resp string, err er
 				}
 			}
 
-			resp, err = m.Plugin.CommitGroup(grp, pretend)
+			resp, err = m.backend(ctx).CommitGroup(grp, pretend)
+			if err == nil && !pretend {
+				m.watchUpdate(grp)
+			}
 			return retry, err
 		})
 
@@ -110,7 +146,15 @@ func (m *manager) CommitGroup(grp group.Spec, pretend bool) (resp string, err er
 }
 
 // Serialized describe group
-func (m *manager) DescribeGroup(id group.ID) (desc group.Description, err error) {
+func (m *manager) DescribeGroup(id group.ID) (group.Description, error) {
+	return m.describeGroup(context.Background(), id)
+}
+
+func (m *managerInContext) DescribeGroup(id group.ID) (group.Description, error) {
+	return m.describeGroup(m.ctx, id)
+}
+
+func (m *manager) describeGroup(ctx context.Context, id group.ID) (desc group.Description, err error) {
 
 	if is, errLeader := m.IsLeader(); errLeader != nil || !is {
 		err = errNotLeader
@@ -121,59 +165,77 @@ func (m *manager) DescribeGroup(id group.ID) (desc group.Description, err error)
 	<-m.queue("describe",
 		func() (bool, error) {
 			log.Debug("Manager DescribeGroup", "id", id, "V", debugV)
-			desc, err = m.Plugin.DescribeGroup(id)
+			desc, err = m.backend(ctx).DescribeGroup(id)
 			return retry, err
 		})
 	return
 }
 
 // This implements/ overrides the Group Plugin interface to support single group-only operations
-func (m *manager) DestroyGroup(id group.ID) (err error) {
+func (m *manager) DestroyGroup(id group.ID) error {
+	return m.destroyGroup(context.Background(), id)
+}
+
+func (m *managerInContext) DestroyGroup(id group.ID) error {
+	return m.destroyGroup(m.ctx, id)
+}
+
+func (m *manager) destroyGroup(ctx context.Context, id group.ID) (err error) {
 
 	if is, errLeader := m.IsLeader(); errLeader != nil || !is {
 		err = errNotLeader
 		return
 	}
 
+	author := caller(ctx)
 	retry := false
 	<-m.queue("destroy",
 		func() (bool, error) {
 			log.Debug("Manager DestroyGroup", "groupID", id, "V", debugV)
 
 			// We first update the user's desired state first
-			if removeErr := m.removeConfig(id); removeErr != nil {
+			if removeErr := m.removeConfig(id, author); removeErr != nil {
 				log.Warn("Error updating/ remove", "err", removeErr)
 				err = removeErr
 				return retry, err
 			}
 
-			err = m.Plugin.DestroyGroup(id)
+			err = m.backend(ctx).DestroyGroup(id)
 			return retry, err
 		})
 	return
 }
 
 // This implements/ overrides the Group Plugin interface to support single group-only operations
-func (m *manager) FreeGroup(id group.ID) (err error) {
+func (m *manager) FreeGroup(id group.ID) error {
+	return m.freeGroup(context.Background(), id)
+}
+
+func (m *managerInContext) FreeGroup(id group.ID) error {
+	return m.freeGroup(m.ctx, id)
+}
+
+func (m *manager) freeGroup(ctx context.Context, id group.ID) (err error) {
 
 	if is, errLeader := m.IsLeader(); errLeader != nil || !is {
 		err = errNotLeader
 		return
 	}
 
+	author := caller(ctx)
 	retry := false
 	<-m.queue("free",
 		func() (bool, error) {
 			log.Debug("Manager FreeGroup", "groupID", id, "V", debugV)
 
 			// We first update the user's desired state first
-			if removeErr := m.removeConfig(id); removeErr != nil {
+			if removeErr := m.removeConfig(id, author); removeErr != nil {
 				log.Warn("Error updating / remove", "err", removeErr)
 				err = removeErr
 				return retry, err
 			}
 
-			err = m.Plugin.FreeGroup(id)
+			err = m.backend(ctx).FreeGroup(id)
 			return retry, err
 		})
 
@@ -181,7 +243,15 @@ func (m *manager) FreeGroup(id group.ID) (err error) {
 }
 
 // This implements/ overrides the Group Plugin interface to support single group-only operations
-func (m *manager) DestroyInstances(id group.ID, instances []instance.ID) (err error) {
+func (m *manager) DestroyInstances(id group.ID, instances []instance.ID) error {
+	return m.destroyInstances(context.Background(), id, instances)
+}
+
+func (m *managerInContext) DestroyInstances(id group.ID, instances []instance.ID) error {
+	return m.destroyInstances(m.ctx, id, instances)
+}
+
+func (m *manager) destroyInstances(ctx context.Context, id group.ID, instances []instance.ID) (err error) {
 
 	if is, errLeader := m.IsLeader(); errLeader != nil || !is {
 		err = errNotLeader
@@ -193,7 +263,7 @@ func (m *manager) DestroyInstances(id group.ID, instances []instance.ID) (err er
 		func() (bool, error) {
 			log.Debug("Manager DestroyInstances", "groupID", id, "instances", instances, "V", debugV)
 
-			err = m.Plugin.DestroyInstances(id, instances)
+			err = m.backend(ctx).DestroyInstances(id, instances)
 			return retry, err
 		})
 
@@ -202,6 +272,14 @@ func (m *manager) DestroyInstances(id group.ID, instances []instance.ID) (err er
 
 // This implements/ overrides the Group Plugin interface to support single group-only operations
 func (m *manager) SetSize(id group.ID, size int) error {
+	return m.setSize(context.Background(), id, size)
+}
+
+func (m *managerInContext) SetSize(id group.ID, size int) error {
+	return m.setSize(m.ctx, id, size)
+}
+
+func (m *manager) setSize(ctx context.Context, id group.ID, size int) error {
 
 	if is, errLeader := m.IsLeader(); errLeader != nil || !is {
 		return errNotLeader
@@ -220,7 +298,7 @@ func (m *manager) SetSize(id group.ID, size int) error {
 	}
 	parsed.Allocation.Size = uint(size)
 	spec.Properties = types.AnyValueMust(parsed)
-	_, err = m.CommitGroup(spec, false)
+	_, err = m.commitGroup(ctx, spec, false)
 	return err
 }
 
@@ -246,3 +324,168 @@ func (m *manager) Size(id group.ID) (size int, err error) {
 	}
 	return int(parsed.Allocation.Size), nil
 }
+
+// This implements/ overrides the Group Plugin interface to support single group-only operations
+func (m *manager) PromoteUpdate(id group.ID) error {
+	return m.promoteUpdate(context.Background(), id)
+}
+
+func (m *managerInContext) PromoteUpdate(id group.ID) error {
+	return m.promoteUpdate(m.ctx, id)
+}
+
+func (m *manager) promoteUpdate(ctx context.Context, id group.ID) (err error) {
+
+	if is, errLeader := m.IsLeader(); errLeader != nil || !is {
+		err = errNotLeader
+		return
+	}
+
+	author := caller(ctx)
+	retry := false
+	<-m.queue("promoteUpdate",
+		func() (bool, error) {
+			log.Debug("Manager PromoteUpdate", "groupID", id, "V", debugV)
+
+			err = m.backend(ctx).PromoteUpdate(id)
+			if err != nil {
+				return retry, err
+			}
+			err = m.recordPromotion(id, author)
+			return retry, err
+		})
+
+	return
+}
+
+// recordPromotion stores the promotion of the canary phase with the spec of the group so that the
+// update is not paused again when the spec is committed again.
+func (m *manager) recordPromotion(id group.ID, author string) error {
+	spec, err := m.loadGroupSpec(id)
+	if err != nil {
+		return err
+	}
+	parsed, err := group_types.ParseProperties(spec)
+	if err != nil {
+		return err
+	}
+	if parsed.Updating.Canary == nil {
+		return nil
+	}
+	parsed.Updating.Canary.Promoted = parsed.InstanceHash()
+	spec.Properties = types.AnyValueMust(parsed)
+	return m.updateConfig(spec, author)
+}
+
+// watchUpdate polls the group committed until its update completes.  If the group plugin rolls the update
+// back, the spec the group is rolled back to is stored so that the failed spec is not committed again, e.g.
+// when the leadership changes.
+func (m *manager) watchUpdate(spec group.Spec) {
+	parsed, err := group_types.ParseProperties(spec)
+	if err != nil {
+		return
+	}
+	hash := parsed.InstanceHash()
+
+	interval := defaultUpdatePollInterval
+	if m.Options.UpdatePollInterval > 0 {
+		interval = m.Options.UpdatePollInterval.Duration()
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if is, err := m.IsLeader(); err != nil || !is {
+				return
+			}
+			done := true
+			<-m.queue("watchUpdate",
+				func() (bool, error) {
+					var err error
+					done, err = m.recordRollback(spec.ID, hash)
+					return false, err
+				})
+			if done {
+				return
+			}
+		}
+	}()
+}
+
+// recordRollback stores the spec the group is rolled back to if the update to the config of the hash was
+// rolled back.  It returns true once there is nothing more to watch: the update is completed or rolled
+// back, or the group was changed since.
+func (m *manager) recordRollback(id group.ID, hash string) (bool, error) {
+	spec, err := m.loadGroupSpec(id)
+	if err != nil {
+		return true, err
+	}
+	parsed, err := group_types.ParseProperties(spec)
+	if err != nil {
+		return true, err
+	}
+	if parsed.InstanceHash() != hash {
+		return true, nil
+	}
+
+	backend := m.backend(context.Background())
+	desc, err := backend.DescribeGroup(id)
+	if err != nil {
+		return true, err
+	}
+	if desc.Rollback == nil || desc.Rollback.From != hash {
+		return desc.Converged, nil
+	}
+
+	specs, err := backend.InspectGroups()
+	if err != nil {
+		return true, err
+	}
+	for _, previous := range specs {
+		if previous.ID == id {
+			log.Warn("Storing the spec the group is rolled back to", "groupID", id,
+				"from", desc.Rollback.From, "to", desc.Rollback.To, "reason", desc.Rollback.Reason)
+			return true, m.updateConfig(previous, rollbackAuthor)
+		}
+	}
+	return true, fmt.Errorf("group %v not found", id)
+}
+
+// This implements/ overrides the Group Plugin interface to support single group-only operations
+func (m *manager) AbortUpdate(id group.ID) error {
+	return m.abortUpdate(context.Background(), id)
+}
+
+func (m *managerInContext) AbortUpdate(id group.ID) error {
+	return m.abortUpdate(m.ctx, id)
+}
+
+func (m *manager) abortUpdate(ctx context.Context, id group.ID) (err error) {
+
+	if is, errLeader := m.IsLeader(); errLeader != nil || !is {
+		err = errNotLeader
+		return
+	}
+
+	retry := false
+	<-m.queue("abortUpdate",
+		func() (bool, error) {
+			log.Debug("Manager AbortUpdate", "groupID", id, "V", debugV)
+
+			err = m.backend(ctx).AbortUpdate(id)
+			if err != nil {
+				return retry, err
+			}
+			// the group plugin rolls the canary back, and the spec it is rolled back to is stored.  The
+			// update may not have been committed by this manager, e.g. if the specs were rolled back.
+			spec, err := m.loadGroupSpec(id)
+			if err == nil {
+				m.watchUpdate(spec)
+			}
+			return retry, nil
+		})
+
+	return
+}
@@ -0,0 +1,210 @@
+package manager // import "github.com/docker/infrakit/pkg/manager"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/infrakit/pkg/rpc/auth"
+	"github.com/docker/infrakit/pkg/spi/stack"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+// revisionRecord is a revision as persisted in the history store.  The entries of the global spec
+// are kept instead of the specs so that the handlers are restored on rollback.
+type revisionRecord struct {
+	stack.Revision
+	Entries []entry
+}
+
+func (r revisionRecord) config() globalSpec {
+	config := globalSpec{}
+	config.init(r.Entries)
+	return config
+}
+
+// caller returns the name of the caller of the rpc served in the context, or local if the call
+// did not come through rpc.
+func caller(ctx context.Context) string {
+	if id, has := auth.FromContext(ctx); has {
+		return id.String()
+	}
+	return "local"
+}
+
+func (m *manager) loadHistory() ([]revisionRecord, error) {
+	records := []revisionRecord{}
+	if m.Options.HistoryStore == nil {
+		return records, nil
+	}
+	if err := m.Options.HistoryStore.Load(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// recordRevision appends the stored config to the history if it's different from the latest revision.
+// The caller must hold the lock so that revisions are recorded in the order the config is stored.  The
+// history is best effort: the config is already stored and is committed even if it cannot be recorded.
+func (m *manager) recordRevision(config globalSpec, author string) {
+	if err := m.doRecordRevision(config, author); err != nil {
+		log.Warn("Cannot record revision", "author", author, "err", err)
+	}
+}
+
+func (m *manager) doRecordRevision(config globalSpec, author string) error {
+	if m.Options.HistoryStore == nil {
+		return nil
+	}
+
+	records, err := m.loadHistory()
+	if err != nil {
+		return err
+	}
+
+	fingerprint := types.Fingerprint(types.AnyValueMust(config.toSpecs()))
+	next := 1
+	if n := len(records); n > 0 {
+		if records[n-1].Fingerprint == fingerprint {
+			return nil
+		}
+		next = records[n-1].Revision.Revision + 1
+	}
+
+	records = append(records, revisionRecord{
+		Revision: stack.Revision{
+			Revision:    next,
+			Author:      author,
+			Timestamp:   time.Now(),
+			Fingerprint: fingerprint,
+		},
+		Entries: config.data,
+	})
+	if limit := m.Options.HistoryLimit; limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	if limit := m.Options.HistorySizeLimit; limit > 0 {
+		// the oldest revisions are dropped until the history fits, but the latest is always kept
+		for len(records) > 1 {
+			any, err := types.AnyValue(records)
+			if err != nil {
+				return err
+			}
+			if len(any.Bytes()) <= limit {
+				break
+			}
+			records = records[1:]
+		}
+	}
+
+	log.Debug("Recording revision", "revision", next, "author", author, "fingerprint", fingerprint, "V", debugV)
+	return m.Options.HistoryStore.Save(records)
+}
+
+func (m *manager) findRevision(rev int) (revisionRecord, error) {
+	records, err := m.loadHistory()
+	if err != nil {
+		return revisionRecord{}, err
+	}
+	for _, r := range records {
+		if r.Revision.Revision == rev {
+			return r, nil
+		}
+	}
+	return revisionRecord{}, fmt.Errorf("revision not found %v", rev)
+}
+
+// History returns the revisions of the specs, oldest first
+func (m *manager) History() ([]stack.Revision, error) {
+	log.Debug("stack.History", "V", debugV2)
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	records, err := m.loadHistory()
+	if err != nil {
+		return nil, err
+	}
+	revisions := []stack.Revision{}
+	for _, r := range records {
+		revisions = append(revisions, r.Revision)
+	}
+	return revisions, nil
+}
+
+// Revision returns the revision with its specs
+func (m *manager) Revision(rev int) (stack.Revision, error) {
+	log.Debug("stack.Revision", "revision", rev, "V", debugV2)
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	record, err := m.findRevision(rev)
+	if err != nil {
+		return stack.Revision{}, err
+	}
+	revision := record.Revision
+	config := record.config()
+	revision.Specs = config.toSpecs()
+	return revision, nil
+}
+
+// Rollback stores the specs of an earlier revision as the specs to enforce and commits them.  Specs added
+// after the revision are freed (no longer enforced) but the resources they created are not destroyed.
+func (m *manager) Rollback(rev int) error {
+	return m.rollback(context.Background(), rev)
+}
+
+// Rollback implements stack.Interface.Rollback with the caller of the context as the author of the revision.
+func (m *managerInContext) Rollback(rev int) error {
+	return m.rollback(m.ctx, rev)
+}
+
+func (m *manager) rollback(ctx context.Context, rev int) error {
+	log.Info("stack.Rollback", "revision", rev)
+
+	if is, err := m.IsLeader(); err != nil || !is {
+		return errNotLeader
+	}
+
+	author := caller(ctx)
+
+	m.lock.Lock()
+
+	target, err := m.findRevision(rev)
+	if err != nil {
+		m.lock.Unlock()
+		return err
+	}
+
+	current := globalSpec{}
+	if err := current.load(m.Options.SpecStore); err != nil {
+		m.lock.Unlock()
+		return err
+	}
+
+	config := target.config()
+	removed := globalSpec{}
+	current.visit(func(k key, r record) error {
+		if _, has := config.index[k]; !has {
+			removed.updateSpec(r.Spec, r.Handler)
+		}
+		return nil
+	})
+
+	err = config.store(m.Options.SpecStore)
+	if err == nil {
+		m.recordRevision(config, author)
+	}
+
+	m.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if err := m.doFree(removed); err != nil {
+		return err
+	}
+	return m.doCommitAll(config)
+}
@@ -0,0 +1,211 @@
+package manager // import "github.com/docker/infrakit/pkg/manager"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	group_mock "github.com/docker/infrakit/pkg/mock/spi/group"
+	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/rpc/auth"
+	"github.com/docker/infrakit/pkg/spi/group"
+	"github.com/docker/infrakit/pkg/spi/stack"
+	testing_scope "github.com/docker/infrakit/pkg/testing/scope"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func testMemSnapshot() fakeSnapshot {
+	var buff []byte
+	return fakeSnapshot{
+		SaveFunc: func(obj interface{}) error {
+			v, err := json.Marshal(obj)
+			buff = v
+			return err
+		},
+		LoadFunc: func(obj interface{}) error {
+			if buff == nil {
+				return nil
+			}
+			return json.Unmarshal(buff, obj)
+		},
+	}
+}
+
+func testHistoryManager(limit int) *manager {
+	return &manager{
+		scope: testing_scope.DefaultScope(),
+		Options: Options{
+			Group:        plugin.Name("group-stateless"),
+			SpecStore:    testMemSnapshot(),
+			HistoryStore: testMemSnapshot(),
+			HistoryLimit: limit,
+		},
+		isLeader: true,
+	}
+}
+
+func TestHistory(t *testing.T) {
+
+	m := testHistoryManager(0)
+
+	revisions, err := m.History()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(revisions))
+
+	require.NoError(t, m.updateConfig(testBuildGroupSpec("workers", `{"size":1}`), "cn=alice"))
+	require.NoError(t, m.updateConfig(testBuildGroupSpec("workers", `{"size":1}`), "cn=bob")) // no change
+	require.NoError(t, m.updateSpec(types.Spec{
+		Kind:     "ingress",
+		Metadata: types.Metadata{Name: "workers/ingress"},
+	}, plugin.Name("ingress"), "local"))
+	require.NoError(t, m.removeConfig(group.ID("workers"), "cn=bob"))
+
+	revisions, err = m.History()
+	require.NoError(t, err)
+	require.Equal(t, 3, len(revisions))
+	require.Equal(t, []string{"cn=alice", "local", "cn=bob"},
+		[]string{revisions[0].Author, revisions[1].Author, revisions[2].Author})
+	for i, r := range revisions {
+		require.Equal(t, i+1, r.Revision)
+		require.Nil(t, r.Specs)
+	}
+	require.NotEqual(t, revisions[0].Fingerprint, revisions[1].Fingerprint)
+
+	revision, err := m.Revision(2)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(revision.Specs))
+	require.Equal(t, "group", revision.Specs[0].Kind)
+	require.Equal(t, "ingress", revision.Specs[1].Kind)
+
+	_, err = m.Revision(4)
+	require.Error(t, err)
+}
+
+func TestHistoryLimit(t *testing.T) {
+
+	m := testHistoryManager(2)
+
+	for _, size := range []string{"1", "2", "3"} {
+		require.NoError(t, m.updateConfig(testBuildGroupSpec("workers", `{"size":`+size+`}`), "local"))
+	}
+
+	revisions, err := m.History()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(revisions))
+	require.Equal(t, 2, revisions[0].Revision)
+	require.Equal(t, 3, revisions[1].Revision)
+
+	_, err = m.Revision(1)
+	require.Error(t, err)
+}
+
+func TestHistorySizeLimit(t *testing.T) {
+
+	m := testHistoryManager(0)
+	require.NoError(t, m.updateConfig(testBuildGroupSpec("workers", `{"size":1}`), "local"))
+	records, err := m.loadHistory()
+	require.NoError(t, err)
+	size := len(types.AnyValueMust(records).Bytes())
+
+	// room for about two revisions
+	m.Options.HistorySizeLimit = size * 5 / 2
+	for _, size := range []string{"2", "3", "4"} {
+		require.NoError(t, m.updateConfig(testBuildGroupSpec("workers", `{"size":`+size+`}`), "local"))
+	}
+
+	revisions, err := m.History()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(revisions))
+	require.Equal(t, 3, revisions[0].Revision)
+	require.Equal(t, 4, revisions[1].Revision)
+
+	// the latest revision is kept even if it doesn't fit
+	m.Options.HistorySizeLimit = 1
+	require.NoError(t, m.updateConfig(testBuildGroupSpec("workers", `{"size":5}`), "local"))
+	revisions, err = m.History()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(revisions))
+	require.Equal(t, 5, revisions[0].Revision)
+}
+
+func TestHistoryBestEffort(t *testing.T) {
+
+	m := testHistoryManager(0)
+	m.Options.HistoryStore = fakeSnapshot{
+		SaveFunc: func(obj interface{}) error {
+			return fmt.Errorf("too large")
+		},
+		LoadFunc: func(obj interface{}) error {
+			return nil
+		},
+	}
+
+	workers := testBuildGroupSpec("workers", `{"size":1}`)
+	require.NoError(t, m.updateConfig(workers, "cn=alice"))
+
+	spec, err := m.loadGroupSpec(group.ID("workers"))
+	require.NoError(t, err)
+	require.Equal(t, workers, spec)
+}
+
+func TestRollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := testHistoryManager(0)
+	ops := make(chan backendOp, 10)
+	m.backendOps = ops
+
+	gp := group_mock.NewMockPlugin(ctrl)
+	m.scope.(*testing_scope.Scope).ResolveGroup = func(n string) (group.Plugin, error) {
+		require.Equal(t, "group-stateless", n)
+		return gp, nil
+	}
+
+	workers := testBuildGroupSpec("workers", `{"size":1}`)
+	require.NoError(t, m.updateConfig(workers, "cn=alice"))
+	require.NoError(t, m.updateConfig(testBuildGroupSpec("workers", `{"size":2}`), "cn=bob"))
+	require.NoError(t, m.updateConfig(testBuildGroupSpec("managers", `{"size":3}`), "cn=bob"))
+
+	gp.EXPECT().FreeGroup(group.ID("managers")).Return(nil)
+	gp.EXPECT().CommitGroup(workers, false).Return("ok", nil).Times(2) // committed again after all groups are defined
+
+	require.NoError(t, m.Rollback(1))
+	require.Equal(t, 3, len(ops))
+	for len(ops) > 0 {
+		_, err := (<-ops).operation()
+		require.NoError(t, err)
+	}
+
+	specs, err := m.Specs()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(specs))
+	require.Equal(t, "workers", specs[0].Metadata.Name)
+
+	revisions, err := m.History()
+	require.NoError(t, err)
+	require.Equal(t, 4, len(revisions))
+	require.Equal(t, revisions[0].Fingerprint, revisions[3].Fingerprint)
+	require.Equal(t, "local", revisions[3].Author)
+
+	// the caller of the context is the author of the rollback
+	caller := auth.Identity{CommonName: "carol"}
+	gp.EXPECT().CommitGroup(testBuildGroupSpec("workers", `{"size":2}`), false).Return("ok", nil).Times(2)
+	require.NoError(t, m.WithContext(auth.NewContext(context.Background(), caller)).(stack.Interface).Rollback(2))
+	for len(ops) > 0 {
+		_, err := (<-ops).operation()
+		require.NoError(t, err)
+	}
+	revisions, err = m.History()
+	require.NoError(t, err)
+	require.Equal(t, 5, len(revisions))
+	require.Equal(t, caller.String(), revisions[4].Author)
+
+	require.Error(t, m.Rollback(6))
+
+	m.isLeader = false
+	require.Equal(t, errNotLeader, m.Rollback(1))
+}
@@ -1,9 +1,11 @@
 package manager // import "github.com/docker/infrakit/pkg/manager"
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/docker/infrakit/pkg/leader"
@@ -19,6 +21,10 @@ import (
 // such as leadership changes and configuration changes and perform the necessary actions
 // to activate / deactivate plugins
 type manager struct {
+	// fencingToken is the fencing token of the current term of leadership, if the leader detector issues them.
+	// It's accessed atomically so it's first for alignment.
+	fencingToken uint64
+
 	scope scope.Scope
 
 	// Options include configurations
@@ -31,9 +37,12 @@ type manager struct {
 	metadata.Updatable
 
 	isLeader bool
-	lock     sync.RWMutex
-	stop     chan struct{}
-	running  chan struct{}
+	// steppingDown is true from the call of StepDown until the detector has released the leadership, so that
+	// the leadership the detector still reports in the meantime is not assumed again.
+	steppingDown bool
+	lock         sync.RWMutex
+	stop         chan struct{}
+	running      chan struct{}
 
 	// Status is the status metadata (readonly)
 	Status            metadata.Plugin
@@ -44,6 +53,18 @@ type manager struct {
 	backendOps chan<- backendOp
 }
 
+// managerInContext is the manager used in the context of a call, e.g. the rpc served.  The caller of the
+// context is recorded as the author of the changes and the backends are called in the context.
+type managerInContext struct {
+	*manager
+	ctx context.Context
+}
+
+// WithContext implements spi.Contextual
+func (m *manager) WithContext(ctx context.Context) interface{} {
+	return &managerInContext{manager: m, ctx: ctx}
+}
+
 const (
 	// defaultPluginPollInterval is the interval to retry connection
 	defaultPluginPollInterval = 2 * time.Second
@@ -67,12 +88,14 @@ func NewManager(scope scope.Scope, options Options) Backend {
 
 	impl := &manager{
 		scope:         scope,
-		Options:       options,
 		Plugin:        gp, // the stateless backend group plugin
-		Updatable:     initUpdatable(scope, options),
 		refreshStatus: refreshStatus,
 	}
 
+	fenceStores(&options, impl.currentFencingToken)
+	impl.Options = options
+	impl.Updatable = initUpdatable(scope, options)
+
 	impl.Status = initStatusMetadata(impl)
 	return impl
 }
@@ -156,6 +179,87 @@ func (m *manager) LeaderLocation() (*url.URL, error) {
 	return m.Options.LeaderStore.GetLocation()
 }
 
+// StepDown stops the controllers and releases the leadership in the leader detector.  The step down is
+// checked with the detector first so that nothing is stopped if the detector would reject it.  If the
+// detector fails to release the leadership, the leadership is assumed again right away.
+func (m *manager) StepDown(successor string) error {
+	handoff, is := m.Options.Leader.(leader.Handoff)
+	if !is {
+		return fmt.Errorf("leader detector does not support stepping down")
+	}
+
+	m.lock.Lock()
+	if !m.isLeader || m.steppingDown {
+		m.lock.Unlock()
+		return errNotLeader
+	}
+	if err := handoff.CanStepDown(successor); err != nil {
+		m.lock.Unlock()
+		return err
+	}
+	// not the leader from now on so that the lost leadership is not signaled again at the next poll, and
+	// the leadership is not assumed again before the detector releases it
+	m.isLeader = false
+	m.steppingDown = true
+	m.lock.Unlock()
+
+	var err error
+	<-m.queue("StepDown", func() (bool, error) {
+		log.Info("Stepping down", "successor", successor)
+		if err = m.onLostLeadership(); err != nil {
+			log.Warn("Error stopping controllers", "err", err)
+		}
+		err = handoff.StepDown(successor)
+
+		m.lock.Lock()
+		m.steppingDown = false
+		if err != nil {
+			// still the leader since the detector did not release it
+			m.isLeader = true
+		}
+		m.lock.Unlock()
+
+		if err != nil {
+			log.Warn("Cannot step down, resuming the leadership", "err", err)
+			if e := m.onAssumeLeadership(); e != nil {
+				log.Error("Error resuming the leadership", "err", e)
+			}
+		}
+		return false, err
+	})
+	return err
+}
+
+// leadershipChanged updates the leadership status with the event of the leader detector.  It returns true
+// with the new status if the status changed.
+func (m *manager) leadershipChanged(evt leader.Leadership) (changed, isLeader bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	current := m.isLeader
+
+	switch {
+	case evt.Status == leader.Unknown:
+		log.Warn("Leadership status is uncertain", "err", evt.Error)
+
+		// if we are currently the leader then there's a possibility of split brain depending on
+		// the robustness of the leader election process.
+		// It's better to be conservative and not assume we can still be a leader...  just downgrade
+		// because the worst case is we stopped watching (and not have two masters running wild).
+
+		if m.isLeader {
+			m.isLeader = false
+		}
+
+	case m.steppingDown:
+		log.Debug("Stepping down, ignoring leadership event", "status", evt.Status, "V", debugV)
+
+	default:
+		m.isLeader = evt.Status == leader.Leader
+	}
+	return current != m.isLeader, m.isLeader
+}
+
 func (m *manager) queue(name string, work func() (retry bool, err error)) <-chan struct{} {
 	wait := make(chan struct{})
 	m.backendOps <- backendOp{
@@ -266,30 +370,7 @@ func (m *manager) Start() (<-chan struct{}, error) {
 				// This here handles possible duplicated events about leadership and fires only when there
 				// is a change.
 
-				m.lock.Lock()
-
-				current := m.isLeader
-
-				if evt.Status == leader.Unknown {
-					log.Warn("Leadership status is uncertain", "err", evt.Error)
-
-					// if we are currently the leader then there's a possibility of split brain depending on
-					// the robustness of the leader election process.
-					// It's better to be conservative and not assume we can still be a leader...  just downgrade
-					// because the worst case is we stopped watching (and not have two masters running wild).
-
-					if m.isLeader {
-						m.isLeader = false
-					}
-
-				} else {
-					m.isLeader = evt.Status == leader.Leader
-				}
-				next := m.isLeader
-
-				m.lock.Unlock()
-
-				if current != next {
+				if changed, next := m.leadershipChanged(evt); changed {
 					notify <- next
 				}
 
@@ -390,9 +471,19 @@ func (m *manager) loadMetadata() (err error) {
 	return m.Updatable.Commit(proposed, cas)
 }
 
+func (m *manager) currentFencingToken() uint64 {
+	return atomic.LoadUint64(&m.fencingToken)
+}
+
 func (m *manager) onAssumeLeadership() (err error) {
 	log.Info("Assuming leadership")
 
+	if fencing, is := m.Options.Leader.(leader.Fencing); is {
+		token := fencing.Token()
+		atomic.StoreUint64(&m.fencingToken, token)
+		log.Info("Fencing writes", "token", token)
+	}
+
 	defer func() {
 		log.Info("Running as leader")
 		m.metadataChanged()
@@ -485,6 +576,10 @@ func (m *manager) doFreeAll(config globalSpec) error {
 	defer m.metadataChanged()
 
 	log.Info("Freeing groups")
+	return m.doFree(config)
+}
+
+func (m *manager) doFree(config globalSpec) error {
 	return m.execPlugins(config,
 		func(controller controller.Controller, spec types.Spec) (bool, error) {
 
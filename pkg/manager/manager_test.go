@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	group_types "github.com/docker/infrakit/pkg/controller/group/types"
 	"github.com/docker/infrakit/pkg/discovery"
 	"github.com/docker/infrakit/pkg/discovery/local"
 	"github.com/docker/infrakit/pkg/leader"
@@ -18,6 +19,7 @@ import (
 	"github.com/docker/infrakit/pkg/rpc/server"
 	"github.com/docker/infrakit/pkg/run/scope"
 	"github.com/docker/infrakit/pkg/spi/group"
+	testing_scope "github.com/docker/infrakit/pkg/testing/scope"
 	"github.com/docker/infrakit/pkg/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -371,3 +373,386 @@ func TestChangeLeadership(t *testing.T) {
 
 	testCloseAll(leaderChans)
 }
+
+type testHandoffDetector struct {
+	testLeaderDetector
+	successor chan string
+	released  chan struct{}
+	reject    error
+	fail      error
+}
+
+func (l *testHandoffDetector) CanStepDown(successor string) error {
+	return l.reject
+}
+
+func (l *testHandoffDetector) StepDown(successor string) error {
+	l.successor <- successor
+	if l.released != nil {
+		<-l.released
+	}
+	return l.fail
+}
+
+func TestStepDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gp := group_mock.NewMockPlugin(ctrl)
+	detector := &testHandoffDetector{successor: make(chan string, 1)}
+	ops := make(chan backendOp, 10)
+	refresh := make(chan struct{}, 10)
+
+	m := &manager{
+		scope: testing_scope.DefaultScope(),
+		Options: Options{
+			Group:  plugin.Name("group-stateless"),
+			Leader: &testLeaderDetector{},
+		},
+		Plugin:        gp,
+		backendOps:    ops,
+		refreshStatus: refresh,
+	}
+	m.scope.(*testing_scope.Scope).ResolveGroup = func(n string) (group.Plugin, error) {
+		return gp, nil
+	}
+	go func() {
+		for op := range ops {
+			op.operation()
+		}
+	}()
+	defer close(ops)
+
+	require.Error(t, m.StepDown(""))
+
+	m.Options.Leader = detector
+	require.Equal(t, errNotLeader, m.StepDown(""))
+
+	m.isLeader = true
+	gp.EXPECT().InspectGroups().Return([]group.Spec{testBuildGroupSpec("managers", `{}`)}, nil)
+	gp.EXPECT().FreeGroup(group.ID("managers")).Return(nil)
+
+	require.NoError(t, m.StepDown("m2"))
+	require.Equal(t, "m2", <-detector.successor)
+
+	isLeader, err := m.IsLeader()
+	require.NoError(t, err)
+	require.False(t, isLeader)
+}
+
+func TestStepDownRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no calls expected: nothing is stopped if the detector rejects the step down
+	gp := group_mock.NewMockPlugin(ctrl)
+	detector := &testHandoffDetector{successor: make(chan string, 1), reject: fmt.Errorf("no successor")}
+	ops := make(chan backendOp, 10)
+
+	m := &manager{
+		scope: testing_scope.DefaultScope(),
+		Options: Options{
+			Group:  plugin.Name("group-stateless"),
+			Leader: detector,
+		},
+		Plugin:        gp,
+		backendOps:    ops,
+		refreshStatus: make(chan struct{}, 10),
+		isLeader:      true,
+	}
+	m.scope.(*testing_scope.Scope).ResolveGroup = func(n string) (group.Plugin, error) {
+		return gp, nil
+	}
+	go func() {
+		for op := range ops {
+			op.operation()
+		}
+	}()
+	defer close(ops)
+
+	require.Equal(t, detector.reject, m.StepDown(""))
+	require.Equal(t, 0, len(detector.successor))
+
+	isLeader, err := m.IsLeader()
+	require.NoError(t, err)
+	require.True(t, isLeader)
+}
+
+func TestStepDownResumesLeadership(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := testHistoryManager(0)
+	ops := make(chan backendOp, 10)
+	m.backendOps = ops
+	m.refreshStatus = make(chan struct{}, 10)
+
+	gp := group_mock.NewMockPlugin(ctrl)
+	m.Plugin = gp
+	m.scope.(*testing_scope.Scope).ResolveGroup = func(n string) (group.Plugin, error) {
+		return gp, nil
+	}
+	detector := &testHandoffDetector{successor: make(chan string, 1), fail: fmt.Errorf("not released")}
+	m.Options.Leader = detector
+
+	managers := testBuildGroupSpec("managers", `{}`)
+	require.NoError(t, m.updateConfig(managers, "cn=alice"))
+
+	go func() {
+		for op := range ops {
+			op.operation()
+		}
+	}()
+	defer close(ops)
+
+	gp.EXPECT().InspectGroups().Return([]group.Spec{managers}, nil)
+	gp.EXPECT().FreeGroup(group.ID("managers")).Return(nil)
+	// the specs are committed again right away since the detector did not release the leadership
+	gp.EXPECT().CommitGroup(gomock.Any(), false).Return("", nil).MinTimes(1)
+
+	require.Equal(t, detector.fail, m.StepDown("m2"))
+	require.Equal(t, "m2", <-detector.successor)
+
+	isLeader, err := m.IsLeader()
+	require.NoError(t, err)
+	require.True(t, isLeader)
+}
+
+func TestPromoteUpdateRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := testHistoryManager(0)
+	ops := make(chan backendOp, 10)
+	m.backendOps = ops
+
+	gp := group_mock.NewMockPlugin(ctrl)
+	m.Plugin = gp
+
+	workers := testBuildGroupSpec("workers", `{
+  "Allocation" : { "Size" : 3 },
+  "Updating" : { "Canary" : { "Count" : 1 } },
+  "Instance" : { "Plugin" : "simulator/compute", "Properties" : { "image" : "v2" } },
+  "Flavor" : { "Plugin" : "vanilla", "Properties" : {} }
+}`)
+	require.NoError(t, m.updateConfig(workers, "cn=alice"))
+
+	gp.EXPECT().PromoteUpdate(group.ID("workers")).Return(nil)
+
+	go func() {
+		op := <-ops
+		op.operation()
+	}()
+	require.NoError(t, m.PromoteUpdate(group.ID("workers")))
+
+	spec, err := m.loadGroupSpec(group.ID("workers"))
+	require.NoError(t, err)
+	parsed := group_types.MustParse(group_types.ParseProperties(spec))
+	require.Equal(t, parsed.InstanceHash(), parsed.Updating.Canary.Promoted)
+	require.Equal(t, 1, parsed.Updating.Canary.Count)
+}
+
+func TestRollbackRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := testHistoryManager(0)
+	gp := group_mock.NewMockPlugin(ctrl)
+	m.Plugin = gp
+
+	v1 := testBuildGroupSpec("workers", `{
+  "Allocation" : { "Size" : 3 },
+  "Instance" : { "Plugin" : "simulator/compute", "Properties" : { "image" : "v1" } },
+  "Flavor" : { "Plugin" : "vanilla", "Properties" : {} }
+}`)
+	v2 := testBuildGroupSpec("workers", `{
+  "Allocation" : { "Size" : 3 },
+  "Updating" : { "Canary" : { "Count" : 1 } },
+  "Instance" : { "Plugin" : "simulator/compute", "Properties" : { "image" : "v2" } },
+  "Flavor" : { "Plugin" : "vanilla", "Properties" : {} }
+}`)
+	hash := func(spec group.Spec) string {
+		return group_types.MustParse(group_types.ParseProperties(spec)).InstanceHash()
+	}
+	require.NoError(t, m.updateConfig(v2, "cn=alice"))
+
+	// still updating
+	gp.EXPECT().DescribeGroup(group.ID("workers")).Return(group.Description{}, nil)
+	done, err := m.recordRollback(group.ID("workers"), hash(v2))
+	require.NoError(t, err)
+	require.False(t, done)
+
+	// rolled back to v1, e.g. after the canary is aborted
+	gp.EXPECT().DescribeGroup(group.ID("workers")).Return(group.Description{
+		Rollback: &group.Rollback{From: hash(v2), To: hash(v1), Reason: "aborted"},
+	}, nil)
+	gp.EXPECT().InspectGroups().Return([]group.Spec{v1}, nil)
+	done, err = m.recordRollback(group.ID("workers"), hash(v2))
+	require.NoError(t, err)
+	require.True(t, done)
+
+	spec, err := m.loadGroupSpec(group.ID("workers"))
+	require.NoError(t, err)
+	require.Equal(t, hash(v1), hash(spec))
+
+	revisions, err := m.History()
+	require.NoError(t, err)
+	require.Equal(t, rollbackAuthor, revisions[len(revisions)-1].Author)
+
+	// nothing to watch once the group is changed
+	done, err = m.recordRollback(group.ID("workers"), hash(v2))
+	require.NoError(t, err)
+	require.True(t, done)
+}
+
+func TestCommitGroupWatchesUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := testHistoryManager(0)
+	m.Options.UpdatePollInterval = types.FromDuration(10 * time.Millisecond)
+	ops := make(chan backendOp, 10)
+	m.backendOps = ops
+
+	gp := group_mock.NewMockPlugin(ctrl)
+	m.Plugin = gp
+	go func() {
+		for op := range ops {
+			op.operation()
+		}
+	}()
+	defer close(ops)
+
+	workers := testBuildGroupSpec("workers", `{
+  "Allocation" : { "Size" : 3 },
+  "Instance" : { "Plugin" : "simulator/compute", "Properties" : { "image" : "v2" } },
+  "Flavor" : { "Plugin" : "vanilla", "Properties" : {} }
+}`)
+
+	polled := make(chan struct{})
+	gp.EXPECT().CommitGroup(workers, false).Return("ok", nil)
+	gp.EXPECT().DescribeGroup(group.ID("workers")).Return(group.Description{}, nil)
+	gp.EXPECT().DescribeGroup(group.ID("workers")).Do(func(group.ID) { close(polled) }).Return(
+		group.Description{Converged: true}, nil)
+
+	_, err := m.CommitGroup(workers, false)
+	require.NoError(t, err)
+
+	select {
+	case <-polled:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "update not polled")
+	}
+}
+
+func TestAbortUpdateRecordsRollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := testHistoryManager(0)
+	m.Options.UpdatePollInterval = types.FromDuration(10 * time.Millisecond)
+	ops := make(chan backendOp, 10)
+	m.backendOps = ops
+
+	gp := group_mock.NewMockPlugin(ctrl)
+	m.Plugin = gp
+	go func() {
+		for op := range ops {
+			op.operation()
+		}
+	}()
+	defer close(ops)
+
+	v1 := testBuildGroupSpec("workers", `{
+  "Allocation" : { "Size" : 3 },
+  "Instance" : { "Plugin" : "simulator/compute", "Properties" : { "image" : "v1" } },
+  "Flavor" : { "Plugin" : "vanilla", "Properties" : {} }
+}`)
+	v2 := testBuildGroupSpec("workers", `{
+  "Allocation" : { "Size" : 3 },
+  "Updating" : { "Canary" : { "Count" : 1 } },
+  "Instance" : { "Plugin" : "simulator/compute", "Properties" : { "image" : "v2" } },
+  "Flavor" : { "Plugin" : "vanilla", "Properties" : {} }
+}`)
+	hash := func(spec group.Spec) string {
+		return group_types.MustParse(group_types.ParseProperties(spec)).InstanceHash()
+	}
+	require.NoError(t, m.updateConfig(v2, "cn=alice"))
+
+	rolledBack := make(chan struct{})
+	gp.EXPECT().AbortUpdate(group.ID("workers")).Return(nil)
+	gp.EXPECT().DescribeGroup(group.ID("workers")).Return(group.Description{
+		Rollback: &group.Rollback{From: hash(v2), To: hash(v1), Reason: "aborted"},
+	}, nil)
+	gp.EXPECT().InspectGroups().Do(func() { close(rolledBack) }).Return([]group.Spec{v1}, nil)
+
+	require.NoError(t, m.AbortUpdate(group.ID("workers")))
+
+	select {
+	case <-rolledBack:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "rollback not recorded")
+	}
+	// wait for the rollback to be stored in the queue
+	<-m.queue("test", func() (bool, error) { return false, nil })
+
+	spec, err := m.loadGroupSpec(group.ID("workers"))
+	require.NoError(t, err)
+	require.Equal(t, hash(v1), hash(spec))
+}
+
+func TestStepDownIgnoresLeadershipUntilReleased(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gp := group_mock.NewMockPlugin(ctrl)
+	detector := &testHandoffDetector{successor: make(chan string, 1), released: make(chan struct{})}
+	ops := make(chan backendOp, 10)
+
+	m := &manager{
+		scope: testing_scope.DefaultScope(),
+		Options: Options{
+			Group:  plugin.Name("group-stateless"),
+			Leader: detector,
+		},
+		Plugin:        gp,
+		backendOps:    ops,
+		refreshStatus: make(chan struct{}, 10),
+		isLeader:      true,
+	}
+	m.scope.(*testing_scope.Scope).ResolveGroup = func(n string) (group.Plugin, error) {
+		return gp, nil
+	}
+	go func() {
+		for op := range ops {
+			op.operation()
+		}
+	}()
+	defer close(ops)
+
+	gp.EXPECT().InspectGroups().Return([]group.Spec{}, nil)
+
+	done := make(chan error)
+	go func() {
+		done <- m.StepDown("m2")
+	}()
+	require.Equal(t, "m2", <-detector.successor)
+
+	// the detector still reports the leadership while releasing it
+	changed, isLeader := m.leadershipChanged(leader.Leadership{Status: leader.Leader})
+	require.False(t, changed)
+	require.False(t, isLeader)
+	require.Equal(t, errNotLeader, m.StepDown("m3"))
+
+	close(detector.released)
+	require.NoError(t, <-done)
+
+	changed, isLeader = m.leadershipChanged(leader.Leadership{Status: leader.NotLeader})
+	require.False(t, changed)
+	require.False(t, isLeader)
+
+	// the leadership is assumed again if the detector reports it after the release
+	changed, isLeader = m.leadershipChanged(leader.Leadership{Status: leader.Leader})
+	require.True(t, changed)
+	require.True(t, isLeader)
+}
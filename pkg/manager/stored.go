@@ -96,16 +96,21 @@ func (g *globalSpec) store(store store.Snapshot) error {
 }
 
 func (g *globalSpec) load(store store.Snapshot) error {
-	g.data = []entry{}
-	err := store.Load(&g.data)
+	data := []entry{}
+	err := store.Load(&data)
 	if err != nil {
 		return err
 	}
+	g.init(data)
+	return nil
+}
+
+func (g *globalSpec) init(data []entry) {
+	g.data = data
 	g.index = map[key]record{}
 	for _, p := range g.data {
 		g.index[p.Key] = p.Record
 	}
-	return nil
 }
 
 func (g *globalSpec) updateSpec(spec types.Spec, handler plugin.Name) {
@@ -0,0 +1,68 @@
+package metrics // import "github.com/docker/infrakit/pkg/metrics"
+
+import (
+	"sync"
+
+	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	fsmInstancesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "fsm", "instances"),
+		"Number of fsm instances in each state, by controller and set.",
+		[]string{"controller", "set", "state"}, nil,
+	)
+
+	sets = &setCollector{sets: map[*fsm.Set]string{}}
+)
+
+// setCollector collects the counts by state of the registered fsm sets at scrape time
+type setCollector struct {
+	sets map[*fsm.Set]string
+	lock sync.RWMutex
+}
+
+// Describe implements prometheus.Collector
+func (c *setCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- fsmInstancesDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *setCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	// Sets of different controllers may share the same name, so the counts are summed up
+	// to avoid reporting the same series twice.
+	type key struct {
+		controller, set, state string
+	}
+	totals := map[key]int{}
+	for set, controller := range c.sets {
+		for state, count := range set.StateCounts() {
+			totals[key{controller: controller, set: set.Name(), state: state}] += count
+		}
+	}
+	for k, total := range totals {
+		ch <- prometheus.MustNewConstMetric(fsmInstancesDesc, prometheus.GaugeValue, float64(total),
+			k.controller, k.set, k.state)
+	}
+}
+
+// RegisterSet exposes the count of instances by state of the set under the controller's name.
+// The set must be unregistered before it is stopped.
+func RegisterSet(controller string, set *fsm.Set) {
+	sets.lock.Lock()
+	defer sets.lock.Unlock()
+
+	sets.sets[set] = controller
+}
+
+// UnregisterSet stops exposing the counts of the set
+func UnregisterSet(set *fsm.Set) {
+	sets.lock.Lock()
+	defer sets.lock.Unlock()
+
+	delete(sets.sets, set)
+}
@@ -0,0 +1,63 @@
+package metrics // import "github.com/docker/infrakit/pkg/metrics"
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/infrakit/pkg/fsm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCollector(t *testing.T) {
+
+	const (
+		pending fsm.Index = iota
+		ready
+	)
+	const (
+		found fsm.Signal = iota
+	)
+
+	spec, err := fsm.Define(
+		fsm.State{
+			Index:       pending,
+			Transitions: map[fsm.Signal]fsm.Index{found: ready},
+		},
+		fsm.State{
+			Index: ready,
+		},
+	)
+	require.NoError(t, err)
+	spec.SetStateNames(map[fsm.Index]string{
+		pending: "pending",
+		ready:   "ready",
+	})
+
+	clock := fsm.NewClock()
+	defer clock.Stop()
+
+	set := fsm.NewSet(spec, clock, fsm.DefaultOptions("test"))
+	defer set.Stop()
+
+	set.Add(pending)
+	set.Add(pending)
+	set.Add(ready)
+
+	RegisterSet("test-controller", set)
+
+	scrape := func() string {
+		resp := httptest.NewRecorder()
+		Handler().ServeHTTP(resp, httptest.NewRequest("GET", URL, nil))
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	body := scrape()
+	require.Contains(t, body, `infrakit_fsm_instances{controller="test-controller",set="test",state="pending"} 2`)
+	require.Contains(t, body, `infrakit_fsm_instances{controller="test-controller",set="test",state="ready"} 1`)
+
+	UnregisterSet(set)
+	require.NotContains(t, scrape(), `controller="test-controller"`)
+}
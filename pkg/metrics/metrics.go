@@ -0,0 +1,180 @@
+package metrics // import "github.com/docker/infrakit/pkg/metrics"
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// URL is the well-known HTTP GET endpoint that exposes the metrics in the Prometheus text format.
+	URL = "/metrics"
+
+	namespace = "infrakit"
+)
+
+var (
+	// RPCCalls counts the rpc calls served by a plugin
+	RPCCalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "calls_total",
+			Help:      "Number of rpc calls served, by plugin, interface and method.",
+		},
+		[]string{"plugin", "interface", "method"},
+	)
+
+	// RPCErrors counts the rpc calls that returned an error
+	RPCErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "errors_total",
+			Help:      "Number of rpc calls that returned an error, by plugin, interface and method.",
+		},
+		[]string{"plugin", "interface", "method"},
+	)
+
+	// RPCDuration observes the latencies of the rpc calls
+	RPCDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "duration_seconds",
+			Help:      "Latency of the rpc calls in seconds, by plugin, interface and method.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"plugin", "interface", "method"},
+	)
+
+	// GroupTargetSize is the desired size of a group
+	GroupTargetSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "group",
+			Name:      "target_size",
+			Help:      "Desired number of instances in the group.",
+		},
+		[]string{"group"},
+	)
+
+	// GroupSize is the actual size of a group as last observed
+	GroupSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "group",
+			Name:      "size",
+			Help:      "Number of instances found in the group.",
+		},
+		[]string{"group"},
+	)
+
+	// GroupConverged is 1 if the group has no update in progress, 0 otherwise
+	GroupConverged = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "group",
+			Name:      "converged",
+			Help:      "1 if the group has no update in progress, 0 otherwise.",
+		},
+		[]string{"group"},
+	)
+
+	// GroupUpdateRemaining is the number of instances still to be updated by a rolling update
+	GroupUpdateRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "group",
+			Name:      "update_remaining",
+			Help:      "Number of instances not yet updated by the rolling update in progress.",
+		},
+		[]string{"group"},
+	)
+
+	// GroupUpdateBatches counts the batches of instances replaced by rolling updates
+	GroupUpdateBatches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "group",
+			Name:      "update_batches_total",
+			Help:      "Number of batches of instances replaced by rolling updates.",
+		},
+		[]string{"group"},
+	)
+
+	// GroupInstancesReplaced counts the unhealthy instances destroyed to be replaced
+	GroupInstancesReplaced = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "group",
+			Name:      "instances_replaced_total",
+			Help:      "Number of instances destroyed for being unhealthy so they are replaced.",
+		},
+		[]string{"group"},
+	)
+
+	// EnrollmentDelta is the number of enrollments to add or remove found by the last sync
+	EnrollmentDelta = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "enrollment",
+			Name:      "delta",
+			Help:      "Number of enrollments to add or remove found by the last sync, by operation.",
+		},
+		[]string{"enrollment", "op"},
+	)
+
+	// EnrollmentErrors counts the enrollments that could not be added or removed
+	EnrollmentErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "enrollment",
+			Name:      "errors_total",
+			Help:      "Number of enrollments that could not be added or removed, by operation.",
+		},
+		[]string{"enrollment", "op"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		RPCCalls,
+		RPCErrors,
+		RPCDuration,
+		GroupTargetSize,
+		GroupSize,
+		GroupConverged,
+		GroupUpdateRemaining,
+		GroupUpdateBatches,
+		GroupInstancesReplaced,
+		EnrollmentDelta,
+		EnrollmentErrors,
+		sets,
+	)
+}
+
+// Handler returns the http handler that serves the metrics
+func Handler() http.Handler {
+	return prometheus.Handler()
+}
+
+// Bool returns the gauge value for a flag
+func Bool(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// DeleteGroup removes the gauges of a group that is no longer watched
+func DeleteGroup(id string) {
+	for _, gauge := range []*prometheus.GaugeVec{
+		GroupTargetSize,
+		GroupSize,
+		GroupConverged,
+		GroupUpdateRemaining,
+	} {
+		gauge.DeleteLabelValues(id)
+	}
+}
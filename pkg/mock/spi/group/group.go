@@ -30,6 +30,16 @@ func (_m *MockPlugin) EXPECT() *_MockPluginRecorder {
 	return _m.recorder
 }
 
+func (_m *MockPlugin) AbortUpdate(_param0 group.ID) error {
+	ret := _m.ctrl.Call(_m, "AbortUpdate", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockPluginRecorder) AbortUpdate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "AbortUpdate", arg0)
+}
+
 func (_m *MockPlugin) CommitGroup(_param0 group.Spec, _param1 bool) (string, error) {
 	ret := _m.ctrl.Call(_m, "CommitGroup", _param0, _param1)
 	ret0, _ := ret[0].(string)
@@ -93,6 +103,16 @@ func (_mr *_MockPluginRecorder) InspectGroups() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "InspectGroups")
 }
 
+func (_m *MockPlugin) PromoteUpdate(_param0 group.ID) error {
+	ret := _m.ctrl.Call(_m, "PromoteUpdate", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockPluginRecorder) PromoteUpdate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PromoteUpdate", arg0)
+}
+
 func (_m *MockPlugin) SetSize(_param0 group.ID, _param1 int) error {
 	ret := _m.ctrl.Call(_m, "SetSize", _param0, _param1)
 	ret0, _ := ret[0].(error)
@@ -0,0 +1,312 @@
+package forward // import "github.com/docker/infrakit/pkg/plugin/event/forward"
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/plugin"
+	event_rpc "github.com/docker/infrakit/pkg/rpc/event"
+	"github.com/docker/infrakit/pkg/run/scope"
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/imdario/mergo"
+)
+
+var (
+	log    = logutil.New("module", "plugin/event/forward")
+	debugV = logutil.V(500)
+)
+
+// Stats are the counts of the events of a route
+type Stats struct {
+	Received     int
+	Delivered    int
+	Dropped      int
+	Batches      int
+	Retries      int
+	LastError    string    `json:",omitempty"`
+	LastDelivery time.Time `json:",omitempty"`
+}
+
+type route struct {
+	Route
+	sink  Sink
+	queue chan *event.Event
+
+	stats Stats
+	lock  sync.Mutex
+}
+
+func (r *route) update(f func(*Stats)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	f(&r.stats)
+}
+
+func (r *route) snapshot() Stats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.stats
+}
+
+// Forwarder subscribes to the topics of the routes and forwards the events to their sinks
+type Forwarder struct {
+	options Options
+	routes  []*route
+	data    map[string]interface{}
+
+	// subscriber returns the subscriber of the events of the plugin
+	subscriber func(plugin.Name) (event.Subscriber, error)
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewForwarder returns a forwarder of the events of the plugins in the scope
+func NewForwarder(scope scope.Scope, options Options) (*Forwarder, error) {
+	if err := mergo.Merge(&options, DefaultOptions); err != nil {
+		return nil, err
+	}
+
+	f := &Forwarder{
+		options: options,
+		data:    map[string]interface{}{},
+		stop:    make(chan struct{}),
+		subscriber: func(name plugin.Name) (event.Subscriber, error) {
+			endpoint, err := scope.Plugins().Find(name)
+			if err != nil {
+				return nil, err
+			}
+			p, err := event_rpc.NewClient(endpoint.Address)
+			if err != nil {
+				return nil, err
+			}
+			subscriber, is := p.(event.Subscriber)
+			if !is {
+				return nil, fmt.Errorf("not a subscriber: %v", name)
+			}
+			return subscriber, nil
+		},
+	}
+
+	names := map[string]bool{}
+	for _, r := range options.Routes {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("duplicate route: %s", r.Name)
+		}
+		names[r.Name] = true
+
+		if err := mergo.Merge(&r, DefaultRoute); err != nil {
+			return nil, err
+		}
+		if len(r.Topics) == 0 {
+			r.Topics = []types.Path{types.PathFromString(".")}
+		}
+
+		sink, err := NewSink(r.Sink)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %v", r.Name, err)
+		}
+
+		rt := &route{
+			Route: r,
+			sink:  sink,
+			queue: make(chan *event.Event, 4*r.BatchSize),
+		}
+		f.routes = append(f.routes, rt)
+		types.Put(types.PathFromString("routes/"+r.Name),
+			func() interface{} {
+				return rt.snapshot()
+			},
+			f.data)
+	}
+	return f, nil
+}
+
+// Data returns the metadata of the forwarder, the stats of the routes
+func (f *Forwarder) Data() map[string]interface{} {
+	return f.data
+}
+
+// Start starts forwarding the events
+func (f *Forwarder) Start() {
+	for _, r := range f.routes {
+		for _, topic := range r.Topics {
+			f.wg.Add(1)
+			go f.subscribe(r, topic)
+		}
+		f.wg.Add(1)
+		go f.forward(r)
+	}
+}
+
+// Stop stops the subscriptions and delivers the events pending
+func (f *Forwarder) Stop() {
+	close(f.stop)
+	f.wg.Wait()
+
+	for _, r := range f.routes {
+		if err := r.sink.Close(); err != nil {
+			log.Warn("Cannot close sink", "route", r.Name, "err", err)
+		}
+	}
+}
+
+// subscribe subscribes to the topic of the route until stopped.  The subscription is retried
+// while the plugin is not available.
+func (f *Forwarder) subscribe(r *route, topic types.Path) {
+	defer f.wg.Done()
+
+	// topics of the plugin are scoped by the type, e.g. group/workers
+	lookup, typeName := r.Plugin.GetLookupAndType()
+	if typeName != "" {
+		topic = types.PathFromString(typeName).Join(topic)
+	}
+
+	for {
+		err := f.receive(r, plugin.Name(lookup), topic)
+		if err != nil {
+			log.Warn("Cannot subscribe", "route", r.Name, "plugin", lookup, "topic", topic, "err", err)
+		}
+
+		select {
+		case <-f.stop:
+			return
+		case <-time.After(f.options.SubscribeRetry.Duration()):
+		}
+	}
+}
+
+// receive queues the events of the topic until stopped or the stream ends
+func (f *Forwarder) receive(r *route, name plugin.Name, topic types.Path) error {
+	subscriber, err := f.subscriber(name)
+	if err != nil {
+		return err
+	}
+	var stream <-chan *event.Event
+	var done chan<- struct{}
+	if r.Filter.Empty() {
+		stream, done, err = subscriber.SubscribeOn(topic)
+	} else if filtered, is := subscriber.(event.FilteredSubscriber); is {
+		stream, done, err = filtered.SubscribeWithFilter(topic, r.Filter)
+	} else {
+		err = fmt.Errorf("filters not supported by %v", name)
+	}
+	if err != nil {
+		return err
+	}
+	defer close(done)
+
+	log.Info("Subscribed", "route", r.Name, "plugin", name, "topic", topic)
+	for {
+		select {
+		case <-f.stop:
+			return nil
+
+		case evt, ok := <-stream:
+			if !ok {
+				return fmt.Errorf("stream ended")
+			}
+			if evt.Type == event.TypeError {
+				// errors of the transport
+				log.Warn("Error receiving events", "route", r.Name, "topic", topic, "err", evt.Error)
+				continue
+			}
+			r.update(func(s *Stats) { s.Received++ })
+
+			select {
+			case r.queue <- evt:
+			case <-f.stop:
+				return nil
+			}
+		}
+	}
+}
+
+// forward delivers the events queued in batches until stopped
+func (f *Forwarder) forward(r *route) {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(r.BatchInterval.Duration())
+	defer ticker.Stop()
+
+	batch := []*event.Event{}
+	for {
+		select {
+		case <-f.stop:
+			// last chance for the events received
+		drain:
+			for {
+				select {
+				case evt := <-r.queue:
+					batch = append(batch, evt)
+				default:
+					break drain
+				}
+			}
+			for len(batch) > 0 {
+				n := len(batch)
+				if n > r.BatchSize {
+					n = r.BatchSize
+				}
+				f.deliver(r, batch[:n])
+				batch = batch[n:]
+			}
+			return
+
+		case evt := <-r.queue:
+			batch = append(batch, evt)
+			if len(batch) < r.BatchSize {
+				continue
+			}
+
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		f.deliver(r, batch)
+		batch = []*event.Event{}
+	}
+}
+
+// deliver sends the batch to the sink, with retries.  The retries are abandoned when stopped.
+func (f *Forwarder) deliver(r *route, batch []*event.Event) {
+	wait := r.RetryInterval.Duration()
+	for attempt := 0; ; attempt++ {
+		err := r.sink.Send(batch)
+		if err == nil {
+			log.Debug("Delivered", "route", r.Name, "count", len(batch), "V", debugV)
+			r.update(func(s *Stats) {
+				s.Delivered += len(batch)
+				s.Batches++
+				s.LastDelivery = time.Now()
+			})
+			return
+		}
+
+		log.Warn("Cannot deliver", "route", r.Name, "count", len(batch), "attempt", attempt, "err", err)
+		r.update(func(s *Stats) { s.LastError = err.Error() })
+
+		if attempt >= r.Retries {
+			break
+		}
+
+		select {
+		case <-f.stop:
+			attempt = r.Retries // one last try
+		case <-time.After(wait):
+		}
+		wait = 2 * wait
+		r.update(func(s *Stats) { s.Retries++ })
+	}
+
+	log.Error("Dropped events", "route", r.Name, "count", len(batch))
+	r.update(func(s *Stats) { s.Dropped += len(batch) })
+}
@@ -0,0 +1,134 @@
+package forward // import "github.com/docker/infrakit/pkg/plugin/event/forward"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/spi/event"
+	testing_scope "github.com/docker/infrakit/pkg/testing/scope"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+type subscriber func(topic types.Path) (<-chan *event.Event, chan<- struct{}, error)
+
+func (s subscriber) SubscribeOn(topic types.Path) (<-chan *event.Event, chan<- struct{}, error) {
+	return s(topic)
+}
+
+func TestForwardWebhook(t *testing.T) {
+
+	batches := make(chan []event.Event, 10)
+	failures := 1
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Equal(t, Sign("s3cr3t", body), req.Header.Get(SignatureHeader))
+		require.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+
+		if failures > 0 {
+			failures--
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		events := []event.Event{}
+		require.NoError(t, json.Unmarshal(body, &events))
+		require.Equal(t, fmt.Sprintf("%d", len(events)), req.Header.Get(CountHeader))
+		batches <- events
+	}))
+	defer server.Close()
+
+	options := Options{
+		SubscribeRetry: types.Duration(10 * time.Millisecond),
+	}
+	require.NoError(t, types.AnyYAMLMust([]byte(fmt.Sprintf(`
+Routes:
+  - Name: oncall
+    Plugin: group/workers
+    Topics:
+      - scale
+    BatchSize: 2
+    BatchInterval: 50ms
+    RetryInterval: 10ms
+    Sink:
+      Kind: webhook
+      Properties:
+        URL: %s
+        Secret: s3cr3t
+        Headers:
+          Authorization: Bearer token
+`, server.URL))).Decode(&options))
+
+	f, err := NewForwarder(testing_scope.DefaultScope(), options)
+	require.NoError(t, err)
+
+	stream := make(chan *event.Event)
+	attempts := 0
+	f.subscriber = func(name plugin.Name) (event.Subscriber, error) {
+		require.Equal(t, plugin.Name("group"), name)
+		attempts++
+		if attempts == 1 {
+			return nil, fmt.Errorf("not running yet")
+		}
+		return subscriber(func(topic types.Path) (<-chan *event.Event, chan<- struct{}, error) {
+			require.Equal(t, types.PathFromString("workers/scale"), topic)
+			return stream, make(chan struct{}), nil
+		}), nil
+	}
+
+	f.Start()
+
+	// transport errors are not forwarded
+	stream <- event.Event{Type: event.TypeError}.Init().WithError(fmt.Errorf("lost"))
+	for i := 0; i < 3; i++ {
+		stream <- event.Event{Type: "Scale", ID: fmt.Sprintf("e%d", i)}.Init().WithTopic("workers/scale")
+	}
+
+	// a full batch, after a retry
+	batch := <-batches
+	require.Equal(t, 2, len(batch))
+	require.Equal(t, "e0", batch[0].ID)
+	require.Equal(t, "e1", batch[1].ID)
+
+	// the rest after the interval
+	batch = <-batches
+	require.Equal(t, 1, len(batch))
+	require.Equal(t, "e2", batch[0].ID)
+
+	f.Stop()
+
+	stats, err := types.AnyValue(types.Get(types.PathFromString("routes/oncall"), f.Data()))
+	require.NoError(t, err)
+	s := Stats{}
+	require.NoError(t, stats.Decode(&s))
+	require.Equal(t, 3, s.Received)
+	require.Equal(t, 3, s.Delivered)
+	require.Equal(t, 2, s.Batches)
+	require.Equal(t, 1, s.Retries)
+	require.Equal(t, 0, s.Dropped)
+}
+
+func TestRoutes(t *testing.T) {
+	_, err := NewForwarder(testing_scope.DefaultScope(), Options{
+		Routes: []Route{{Name: "a", Plugin: "group", Sink: SinkSpec{Kind: "kafka"}}},
+	})
+	require.Error(t, err)
+
+	_, err = NewForwarder(testing_scope.DefaultScope(), Options{
+		Routes: []Route{{Name: "a", Plugin: "group", Sink: SinkSpec{Kind: WebhookKind}}},
+	})
+	require.Error(t, err) // no url
+
+	_, err = NewForwarder(testing_scope.DefaultScope(), Options{
+		Routes: []Route{{Name: "a", Sink: SinkSpec{Kind: WebhookKind}}},
+	})
+	require.Error(t, err) // no plugin
+}
@@ -0,0 +1,129 @@
+package forward // import "github.com/docker/infrakit/pkg/plugin/event/forward"
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+// Options are the options of the forwarder
+type Options struct {
+	// Routes are the routes of the events to the sinks
+	Routes []Route
+
+	// SubscribeRetry is the time to wait before subscribing again when the event plugin
+	// is not available or the stream of events ended.
+	SubscribeRetry types.Duration
+}
+
+// Route forwards the events of the topics of an event plugin to a sink
+type Route struct {
+	// Name is the name of the route
+	Name string
+
+	// Plugin is the name of the event plugin, e.g. group or instance-tracker
+	Plugin plugin.Name
+
+	// Topics are the topics to subscribe to.  All the topics of the plugin if empty.
+	Topics []types.Path
+
+	// Filter selects the events of the topics.  The events are filtered by the event plugin.
+	Filter event.Filter
+
+	// Sink is where the events are delivered
+	Sink SinkSpec
+
+	// BatchSize is the maximum number of events delivered at once
+	BatchSize int
+
+	// BatchInterval is the longest time events are held before delivery
+	BatchInterval types.Duration
+
+	// Retries is the number of times the delivery of a batch is retried before the batch is dropped
+	Retries int
+
+	// RetryInterval is the time to wait before the first retry.  It doubles on each retry.
+	RetryInterval types.Duration
+}
+
+// SinkSpec is the kind and properties of a sink
+type SinkSpec struct {
+	// Kind is the kind of the sink, e.g. webhook
+	Kind string
+
+	// Properties are the properties of the sink, specific to the kind
+	Properties *types.Any
+}
+
+// DefaultOptions are the default options of the forwarder
+var DefaultOptions = Options{
+	SubscribeRetry: types.Duration(5 * time.Second),
+}
+
+// DefaultRoute has the default values of a route
+var DefaultRoute = Route{
+	BatchSize:     10,
+	BatchInterval: types.Duration(1 * time.Second),
+	Retries:       3,
+	RetryInterval: types.Duration(1 * time.Second),
+}
+
+// Validate validates the route
+func (r Route) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("no name")
+	}
+	if r.Plugin == "" {
+		return fmt.Errorf("no plugin for route %s", r.Name)
+	}
+	if r.Sink.Kind == "" {
+		return fmt.Errorf("no sink for route %s", r.Name)
+	}
+	if r.BatchSize < 0 || r.Retries < 0 {
+		return fmt.Errorf("negative batch size or retries for route %s", r.Name)
+	}
+	return nil
+}
+
+// Sink delivers batches of events to a system outside of infrakit
+type Sink interface {
+
+	// Send delivers the events.  The batch is retried when an error is returned.
+	Send(events []*event.Event) error
+
+	// Close releases the resources of the sink
+	Close() error
+}
+
+// SinkBuilder builds a sink from the properties in its spec
+type SinkBuilder func(properties *types.Any) (Sink, error)
+
+var (
+	sinks     = map[string]SinkBuilder{}
+	sinksLock sync.RWMutex
+)
+
+// RegisterSink registers the builder of a kind of sink.  Sinks for message queues are
+// added by registering their builders, typically in the init of their packages.
+func RegisterSink(kind string, builder SinkBuilder) {
+	sinksLock.Lock()
+	defer sinksLock.Unlock()
+
+	sinks[kind] = builder
+}
+
+// NewSink returns the sink of the spec
+func NewSink(spec SinkSpec) (Sink, error) {
+	sinksLock.RLock()
+	defer sinksLock.RUnlock()
+
+	builder, has := sinks[spec.Kind]
+	if !has {
+		return nil, fmt.Errorf("unknown kind of sink: %s", spec.Kind)
+	}
+	return builder(spec.Properties)
+}
@@ -0,0 +1,115 @@
+package forward // import "github.com/docker/infrakit/pkg/plugin/event/forward"
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+const (
+	// WebhookKind is the kind of the sink that posts the events to a url
+	WebhookKind = "webhook"
+
+	// SignatureHeader is the header with the HMAC-SHA256 signature of the body, as sha256=<hex>
+	SignatureHeader = "X-Infrakit-Signature"
+
+	// CountHeader is the header with the number of events in the body
+	CountHeader = "X-Infrakit-Event-Count"
+)
+
+func init() {
+	RegisterSink(WebhookKind, NewWebhook)
+}
+
+// WebhookProperties are the properties of a webhook sink
+type WebhookProperties struct {
+	// URL is the url the events are posted to, as a json array
+	URL string
+
+	// Headers are additional headers of the requests, e.g. for authentication
+	Headers map[string]string
+
+	// Secret is the key of the signature of the requests.  Not signed if empty.
+	Secret string
+
+	// Timeout is the timeout of the requests
+	Timeout types.Duration
+}
+
+type webhook struct {
+	WebhookProperties
+	client *http.Client
+}
+
+// NewWebhook returns a sink that posts the events to a webhook
+func NewWebhook(properties *types.Any) (Sink, error) {
+	w := &webhook{
+		WebhookProperties: WebhookProperties{
+			Timeout: types.Duration(10 * time.Second),
+		},
+	}
+	if err := properties.Decode(&w.WebhookProperties); err != nil {
+		return nil, err
+	}
+	if w.URL == "" {
+		return nil, fmt.Errorf("no url for webhook")
+	}
+	w.client = &http.Client{Timeout: w.Timeout.Duration()}
+	return w, nil
+}
+
+// Sign returns the signature of the body with the secret, as the value of the signature header
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send implements Sink.Send
+func (w *webhook) Send(events []*event.Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CountHeader, strconv.Itoa(len(events)))
+	if w.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s: http-status:%v", w.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// Close implements Sink.Close
+func (w *webhook) Close() error {
+	return nil
+}
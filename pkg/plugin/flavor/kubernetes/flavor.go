@@ -52,6 +52,10 @@ type Options struct {
 
 	// MultiMaster specifies if the control plane supports multi master
 	MultiMaster bool
+
+	// Kubeconfig is the path of the kubeconfig file used by the instance plugin observing the Nodes.
+	// The in-cluster configuration is used if it is empty.
+	Kubeconfig string
 }
 
 // Spec is the value passed in the `Properties` field of configs
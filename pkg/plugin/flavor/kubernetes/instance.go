@@ -0,0 +1,174 @@
+package kubernetes // import "github.com/docker/infrakit/pkg/plugin/flavor/kubernetes"
+
+import (
+	"fmt"
+
+	"github.com/docker/infrakit/pkg/spi/instance"
+	"github.com/docker/infrakit/pkg/types"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	policy "k8s.io/client-go/pkg/apis/policy/v1beta1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// mirrorPodAnnotation marks the static pods that are managed by the kubelet and cannot be evicted
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+)
+
+// KubeClient returns a client of the API server given the path to a kubeconfig file.  The in-cluster
+// configuration is used if the path is empty.
+func KubeClient(kubeconfig string) (kubernetes.Interface, error) {
+	var config *rest.Config
+	var err error
+	if kubeconfig == "" {
+		config, err = rest.InClusterConfig()
+	} else {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// NewInstancePlugin creates an instance plugin that observes the Nodes of a Kubernetes cluster
+func NewInstancePlugin(connect func() (kubernetes.Interface, error)) instance.Plugin {
+	return &InstancePlugin{connect: connect}
+}
+
+// InstancePlugin is the instance plugin whose instances are the Nodes known to the API server
+type InstancePlugin struct {
+	connect func() (kubernetes.Interface, error)
+}
+
+// DescribeInstances returns a slice of instance.Description objects, each having:
+// - Node name as ID
+// - Node labels as Tags, plus the name
+// - Node "infrakit-link" label as the LogicalID (if set)
+// - Node object as Properties
+func (p *InstancePlugin) DescribeInstances(labels map[string]string, properties bool) ([]instance.Description, error) {
+	client, err := p.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []instance.Description{}
+	for _, n := range nodes.Items {
+		var propsAny *types.Any
+		if properties {
+			propsAny, err = types.AnyValue(n)
+			if err != nil {
+				log.Error("Failed to encode node properties", "node", n.Name, "err", err)
+				return nil, err
+			}
+		}
+		tags := map[string]string{}
+		for k, v := range n.Labels {
+			tags[k] = v
+		}
+		tags["name"] = n.Name
+
+		var logicalID *instance.LogicalID
+		if link, has := tags[types.LinkLabel]; has {
+			v := instance.LogicalID(link)
+			logicalID = &v
+		}
+		result = append(result, instance.Description{
+			ID:         instance.ID(n.Name),
+			LogicalID:  logicalID,
+			Properties: propsAny,
+			Tags:       tags,
+		})
+	}
+	return result, nil
+}
+
+// Destroy cordons the node, evicts its pods and deletes the Node object.  Pods managed by
+// DaemonSets and mirror pods are left to be removed with the node.
+func (p *InstancePlugin) Destroy(id instance.ID, instContext instance.Context) error {
+	client, err := p.connect()
+	if err != nil {
+		return err
+	}
+
+	name := string(id)
+	node, err := client.CoreV1().Nodes().Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if !node.Spec.Unschedulable {
+		node.Spec.Unschedulable = true
+		if _, err := client.CoreV1().Nodes().Update(node); err != nil {
+			log.Warn("Failed to cordon node", "node", name, "err", err)
+			return err
+		}
+		log.Info("Cordoned node", "node", name)
+	}
+
+	pods, err := client.CoreV1().Pods(meta_v1.NamespaceAll).List(meta_v1.ListOptions{
+		FieldSelector: "spec.nodeName=" + name,
+	})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		if !evictable(pod) {
+			continue
+		}
+		err := client.CoreV1().Pods(pod.Namespace).Evict(&policy.Eviction{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+		})
+		if err != nil {
+			log.Warn("Failed to evict pod", "node", name, "namespace", pod.Namespace, "pod", pod.Name, "err", err)
+			return err
+		}
+		log.Info("Evicted pod", "node", name, "namespace", pod.Namespace, "pod", pod.Name)
+	}
+
+	if err := client.CoreV1().Nodes().Delete(name, &meta_v1.DeleteOptions{}); err != nil {
+		log.Warn("Failed to delete node", "node", name, "err", err)
+		return err
+	}
+	log.Info("Successfully deleted node", "node", name)
+	return nil
+}
+
+func evictable(pod v1.Pod) bool {
+	if _, has := pod.Annotations[mirrorPodAnnotation]; has {
+		return false
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate is not suported
+func (p *InstancePlugin) Validate(req *types.Any) error {
+	return fmt.Errorf("Validate not supported for kubernetes nodes")
+}
+
+// Provision is not suported
+func (p *InstancePlugin) Provision(spec instance.Spec) (*instance.ID, error) {
+	return nil, fmt.Errorf("Provision not supported for kubernetes nodes")
+}
+
+// Label is not suported
+func (p *InstancePlugin) Label(instance instance.ID, labels map[string]string) error {
+	return fmt.Errorf("Label not supported for kubernetes nodes")
+}
@@ -0,0 +1,131 @@
+package kubernetes // import "github.com/docker/infrakit/pkg/plugin/flavor/kubernetes"
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/docker/infrakit/pkg/spi/instance"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/rest"
+)
+
+// fakeAPIServer serves the node and pod resources used by the instance plugin and records the requests
+type fakeAPIServer struct {
+	*httptest.Server
+
+	nodes    []v1.Node
+	pods     []v1.Pod
+	requests []string
+	lock     sync.Mutex
+}
+
+func newFakeAPIServer(t *testing.T, nodes []v1.Node, pods []v1.Pod) *fakeAPIServer {
+	s := &fakeAPIServer{nodes: nodes, pods: pods}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		s.requests = append(s.requests, req.Method+" "+req.URL.Path)
+
+		var body interface{}
+		switch {
+		case req.Method == "GET" && req.URL.Path == "/api/v1/nodes":
+			body = v1.NodeList{Items: s.nodes}
+		case req.Method == "GET" && req.URL.Path == "/api/v1/nodes/node1":
+			body = s.nodes[0]
+		case req.Method == "PUT" && req.URL.Path == "/api/v1/nodes/node1":
+			node := v1.Node{}
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&node))
+			s.nodes[0] = node
+			body = node
+		case req.Method == "GET" && req.URL.Path == "/api/v1/pods":
+			require.Equal(t, "spec.nodeName=node1", req.URL.Query().Get("fieldSelector"))
+			body = v1.PodList{Items: s.pods}
+		case req.Method == "POST" || req.Method == "DELETE":
+			body = meta_v1.Status{Status: meta_v1.StatusSuccess}
+		default:
+			resp.WriteHeader(http.StatusNotFound)
+			return
+		}
+		resp.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(resp).Encode(body))
+	}))
+	return s
+}
+
+func (s *fakeAPIServer) connect() (kubernetes.Interface, error) {
+	return kubernetes.NewForConfig(&rest.Config{Host: s.URL})
+}
+
+func testNode(name string, labels map[string]string) v1.Node {
+	node := v1.Node{}
+	node.Name = name
+	node.Labels = labels
+	node.Status.Conditions = []v1.NodeCondition{
+		{Type: v1.NodeReady, Status: v1.ConditionTrue},
+	}
+	return node
+}
+
+func testPod(namespace, name string, annotations map[string]string, owner string) v1.Pod {
+	pod := v1.Pod{}
+	pod.Namespace = namespace
+	pod.Name = name
+	pod.Annotations = annotations
+	if owner != "" {
+		pod.OwnerReferences = []meta_v1.OwnerReference{{Kind: owner, Name: "owner"}}
+	}
+	return pod
+}
+
+func TestInstancePluginDescribe(t *testing.T) {
+	server := newFakeAPIServer(t, []v1.Node{
+		testNode("node1", map[string]string{types.LinkLabel: "link1"}),
+		testNode("node2", nil),
+	}, nil)
+	defer server.Close()
+
+	plugin := NewInstancePlugin(server.connect)
+
+	described, err := plugin.DescribeInstances(nil, true)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(described))
+
+	require.Equal(t, instance.ID("node1"), described[0].ID)
+	require.Equal(t, instance.LogicalID("link1"), *described[0].LogicalID)
+	require.Equal(t, "node1", described[0].Tags["name"])
+	require.Nil(t, described[1].LogicalID)
+
+	node := v1.Node{}
+	require.NoError(t, described[0].Properties.Decode(&node))
+	require.Equal(t, v1.ConditionTrue, node.Status.Conditions[0].Status)
+}
+
+func TestInstancePluginDestroy(t *testing.T) {
+	server := newFakeAPIServer(t, []v1.Node{testNode("node1", nil)}, []v1.Pod{
+		testPod("default", "web", nil, "ReplicaSet"),
+		testPod("kube-system", "kube-proxy", nil, "DaemonSet"),
+		testPod("kube-system", "etcd", map[string]string{mirrorPodAnnotation: "hash"}, ""),
+	})
+	defer server.Close()
+
+	plugin := NewInstancePlugin(server.connect)
+	require.NoError(t, plugin.Destroy(instance.ID("node1"), instance.Termination))
+
+	require.True(t, server.nodes[0].Spec.Unschedulable)
+	require.Equal(t, []string{
+		"GET /api/v1/nodes/node1",
+		"PUT /api/v1/nodes/node1",
+		"GET /api/v1/pods",
+		"POST /api/v1/namespaces/default/pods/web/eviction",
+		"DELETE /api/v1/nodes/node1",
+	}, server.requests)
+}
@@ -87,3 +87,13 @@ func (s *GroupPlugin) DestroyInstances(group.ID, []instance.ID) error {
 func (s *GroupPlugin) SetSize(group.ID, int) error {
 	return fmt.Errorf("SetSize not supported for swarm group")
 }
+
+// PromoteUpdate is not supported
+func (s *GroupPlugin) PromoteUpdate(group.ID) error {
+	return fmt.Errorf("PromoteUpdate not supported for swarm group")
+}
+
+// AbortUpdate is not supported
+func (s *GroupPlugin) AbortUpdate(group.ID) error {
+	return fmt.Errorf("AbortUpdate not supported for swarm group")
+}
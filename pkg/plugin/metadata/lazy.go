@@ -127,3 +127,11 @@ func (c *lazyConnect) Commit(proposed *types.Any, cas string) (err error) {
 	})
 	return
 }
+
+func (c *lazyConnect) Watch(path types.Path) (updates <-chan metadata.Update, done chan<- struct{}, err error) {
+	err = c.do(func(p metadata.Plugin) error {
+		updates, done, err = Watch(p, path, DefaultPollInterval)
+		return err
+	})
+	return
+}
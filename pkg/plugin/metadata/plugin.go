@@ -1,19 +1,22 @@
 package metadata // import "github.com/docker/infrakit/pkg/plugin/metadata"
 
 import (
+	"time"
+
 	"github.com/docker/infrakit/pkg/spi/metadata"
 	"github.com/docker/infrakit/pkg/types"
 )
 
 // NewPluginFromData creates a plugin out of a simple data map.  Note the updates to the map
-// is not guarded and synchronized with the reads.
+// is not guarded and synchronized with the reads.  The values watched are polled for changes.
 func NewPluginFromData(data map[string]interface{}) metadata.Plugin {
-	return &plugin{data: data}
+	return &plugin{data: data, poll: DefaultPollInterval}
 }
 
 // NewPluginFromChannel returns a plugin implementation where reads and writes are serialized
 // via channel of functions that have a view to the metadata.  Closing the write channel stops
-// the serialized read/writes and falls back to unserialized reads.
+// the serialized read/writes and falls back to unserialized reads.  The watchers of the plugin
+// are notified after each write.
 func NewPluginFromChannel(writes <-chan func(map[string]interface{})) metadata.Plugin {
 
 	readChan := make(chan func(map[string]interface{}))
@@ -37,6 +40,7 @@ func NewPluginFromChannel(writes <-chan func(map[string]interface{})) metadata.P
 					return
 				}
 				writer(data)
+				p.watchers.changed()
 
 			case reader := <-p.reads:
 				copy := data
@@ -48,8 +52,10 @@ func NewPluginFromChannel(writes <-chan func(map[string]interface{})) metadata.P
 }
 
 type plugin struct {
-	data  map[string]interface{}
-	reads chan func(data map[string]interface{})
+	data     map[string]interface{}
+	reads    chan func(data map[string]interface{})
+	poll     time.Duration
+	watchers watchers
 }
 
 // Keys returns a list of *child nodes* given a path, which is specified as a slice
@@ -87,3 +93,8 @@ func (p *plugin) Get(path types.Path) (*types.Any, error) {
 
 	return <-value, <-err
 }
+
+// Watch returns the updates of the value at the path given.
+func (p *plugin) Watch(path types.Path) (<-chan metadata.Update, chan<- struct{}, error) {
+	return p.watchers.watch(p.Get, path, p.poll)
+}
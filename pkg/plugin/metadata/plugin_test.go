@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/infrakit/pkg/spi/metadata"
+	testing_metadata "github.com/docker/infrakit/pkg/testing/metadata"
 	"github.com/docker/infrakit/pkg/types"
 	"github.com/stretchr/testify/require"
 )
@@ -73,3 +75,54 @@ func TestPluginSerializedReadWrites(t *testing.T) {
 
 	require.Equal(t, []string{"0", "100", "200", "300"}, results)
 }
+
+func TestPluginWatch(t *testing.T) {
+
+	c := make(chan func(map[string]interface{}))
+	defer close(c)
+
+	p := NewPluginFromChannel(c)
+
+	c <- func(m map[string]interface{}) {
+		types.Put(types.PathFromString("metrics/instances/count"), 100, m)
+	}
+
+	updates, done, err := p.(metadata.Watcher).Watch(types.PathFromString("metrics/instances/count"))
+	require.NoError(t, err)
+
+	c <- func(m map[string]interface{}) {
+		types.Put(types.PathFromString("metrics/networks/count"), 10, m)
+	}
+	c <- func(m map[string]interface{}) {
+		types.Put(types.PathFromString("metrics/instances/count"), 200, m)
+	}
+
+	update := <-updates
+	require.Equal(t, "100", update.Old.String())
+	require.Equal(t, "200", update.New.String())
+
+	close(done)
+	_, open := <-updates
+	require.False(t, open)
+
+	// plugins that don't notify are polled
+	var lock sync.Mutex
+	count := 1
+	updates, done, err = Watch(&testing_metadata.Plugin{
+		DoGet: func(path types.Path) (*types.Any, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return types.AnyValue(count)
+		},
+	}, types.PathFromString("count"), 10*time.Millisecond)
+	require.NoError(t, err)
+	defer close(done)
+
+	lock.Lock()
+	count = 2
+	lock.Unlock()
+
+	update = <-updates
+	require.Equal(t, "1", update.Old.String())
+	require.Equal(t, "2", update.New.String())
+}
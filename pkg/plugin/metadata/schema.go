@@ -0,0 +1,102 @@
+package metadata // import "github.com/docker/infrakit/pkg/plugin/metadata"
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+)
+
+// InvalidChange is a value at a path that doesn't conform to the schema
+type InvalidChange struct {
+	Path   types.Path
+	Reason string
+}
+
+// ValidationError is the error of proposed metadata rejected by the schema
+type ValidationError []InvalidChange
+
+// Error implements the error interface
+func (e ValidationError) Error() string {
+	lines := []string{}
+	for _, c := range e {
+		lines = append(lines, fmt.Sprintf("%v: %s", c.Path, c.Reason))
+	}
+	return fmt.Sprintf("invalid changes: %s", strings.Join(lines, "; "))
+}
+
+// Validate checks the proposed metadata against the JSON schema.  The schema supports the types,
+// ranges, enums, etc. of JSON schema; the paths of properties marked readOnly must have the
+// same values in the original and the proposed.  A nil schema accepts everything.
+func Validate(schema, original, proposed *types.Any) error {
+	if schema == nil {
+		return nil
+	}
+
+	s := &spec.Schema{}
+	if err := schema.Decode(s); err != nil {
+		return fmt.Errorf("bad schema: %v", err)
+	}
+
+	var before, after interface{}
+	if err := original.Decode(&before); err != nil {
+		return err
+	}
+	if err := proposed.Decode(&after); err != nil {
+		return err
+	}
+
+	invalid := ValidationError{}
+
+	result := validate.NewSchemaValidator(s, nil, "", strfmt.Default).Validate(after)
+	for _, err := range result.Errors {
+		invalid = append(invalid, invalidChange(err))
+	}
+
+	for _, p := range readOnly(s, types.Dot) {
+		if !reflect.DeepEqual(types.Get(p, before), types.Get(p, after)) {
+			invalid = append(invalid, InvalidChange{Path: p, Reason: "is read-only"})
+		}
+	}
+
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Slice(invalid, func(i, j int) bool { return invalid[i].Path.Less(invalid[j].Path) })
+	return invalid
+}
+
+// invalidChange converts the errors of the validator, where the fields are named by their
+// dotted paths, e.g. metrics.count in body should be less than or equal to 10
+func invalidChange(err error) InvalidChange {
+	v, is := err.(*errors.Validation)
+	if !is {
+		return InvalidChange{Path: types.Dot, Reason: err.Error()}
+	}
+	path := types.Dot
+	if v.Name != "" {
+		path = types.PathFromString(strings.Replace(v.Name, ".", "/", -1))
+	}
+	reason := strings.TrimPrefix(v.Error(), v.Name)
+	reason = strings.TrimPrefix(strings.TrimSpace(reason), "in body ")
+	return InvalidChange{Path: path, Reason: reason}
+}
+
+// readOnly returns the paths of the properties marked read-only
+func readOnly(s *spec.Schema, path types.Path) []types.Path {
+	paths := []types.Path{}
+	if s.ReadOnly {
+		return append(paths, path)
+	}
+	for k, p := range s.Properties {
+		property := p
+		paths = append(paths, readOnly(&property, path.JoinString(k))...)
+	}
+	return paths
+}
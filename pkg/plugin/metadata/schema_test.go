@@ -0,0 +1,124 @@
+package metadata // import "github.com/docker/infrakit/pkg/plugin/metadata"
+
+import (
+	"testing"
+
+	"github.com/docker/infrakit/pkg/spi/metadata"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `
+{
+  "type" : "object",
+  "properties" : {
+    "cluster" : {
+      "type" : "object",
+      "properties" : {
+        "id" : { "type" : "string", "readOnly" : true },
+        "size" : { "type" : "integer", "minimum" : 1, "maximum" : 10 },
+        "mode" : { "enum" : [ "swarm", "kubernetes" ] }
+      }
+    }
+  }
+}
+`
+
+func TestValidate(t *testing.T) {
+
+	schema := types.AnyString(testSchema)
+	original := types.AnyValueMust(map[string]interface{}{
+		"cluster": map[string]interface{}{
+			"id":   "c1",
+			"size": 3,
+			"mode": "swarm",
+		},
+	})
+
+	require.NoError(t, Validate(nil, original, types.AnyString(`{"cluster":{"size":"big"}}`)))
+	require.NoError(t, Validate(schema, original, original))
+	require.NoError(t, Validate(schema, original, types.AnyString(`{"cluster":{"id":"c1","size":5,"mode":"kubernetes"}}`)))
+
+	err := Validate(schema, original, types.AnyString(`{"cluster":{"id":"c2","size":20,"mode":"mesos"}}`))
+	require.Error(t, err)
+
+	invalid, is := err.(ValidationError)
+	require.True(t, is)
+	require.Equal(t, 3, len(invalid))
+	require.Equal(t, types.PathFromString("cluster/id"), invalid[0].Path)
+	require.Equal(t, "is read-only", invalid[0].Reason)
+	require.Equal(t, types.PathFromString("cluster/mode"), invalid[1].Path)
+	require.Equal(t, types.PathFromString("cluster/size"), invalid[2].Path)
+	require.Contains(t, invalid[2].Reason, "less than or equal to 10")
+
+	err = Validate(schema, original, types.AnyString(`{"cluster":{"id":"c1","size":"big"}}`))
+	require.Error(t, err)
+	require.Equal(t, types.PathFromString("cluster/size"), err.(ValidationError)[0].Path)
+}
+
+func TestUpdatableWithSchema(t *testing.T) {
+
+	data := map[string]interface{}{}
+	require.NoError(t, types.AnyString(`{"cluster":{"id":"c1","size":3}}`).Decode(&data))
+
+	var store *types.Any
+	u := NewUpdatablePluginWithSchema(NewPluginFromData(data),
+		func(proposed *types.Any) error {
+			store = proposed
+			return nil
+		},
+		types.AnyString(testSchema))
+
+	require.Equal(t, types.AnyString(testSchema), u.(metadata.Schema).Schema())
+
+	_, _, _, err := u.Changes([]metadata.Change{
+		{Path: types.PathFromString("cluster/size"), Value: types.AnyValueMust(11)},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cluster/size")
+
+	_, _, _, err = u.Changes([]metadata.Change{
+		{Path: types.PathFromString("cluster/id"), Value: types.AnyValueMust("c2")},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cluster/id: is read-only")
+
+	_, proposed, cas, err := u.Changes([]metadata.Change{
+		{Path: types.PathFromString("cluster/size"), Value: types.AnyValueMust(5)},
+	})
+	require.NoError(t, err)
+	require.NoError(t, u.Commit(proposed, cas))
+	require.Equal(t, proposed, store)
+
+	// proposals that didn't go through Changes are validated too
+	bad := types.AnyString(`{"cluster":{"id":"c1","size":0}}`)
+	original, err := u.Get(types.Dot)
+	require.NoError(t, err)
+	require.Error(t, u.Commit(bad, types.Fingerprint(original, bad)))
+}
+
+func TestUpdatableWithSchemaForwarded(t *testing.T) {
+
+	data := map[string]interface{}{}
+	require.NoError(t, types.AnyString(`{"cluster":{"id":"c1","size":3}}`).Decode(&data))
+
+	committed := 0
+	backend := NewUpdatablePlugin(NewPluginFromData(data),
+		func(proposed *types.Any) error {
+			committed++
+			return proposed.Decode(&data)
+		})
+
+	u := NewUpdatablePluginWithSchema(backend,
+		func(proposed *types.Any) error { return nil },
+		types.AnyString(testSchema))
+
+	// the cas is computed by the client and the commit is forwarded to the backend
+	bad := types.AnyString(`{"cluster":{"id":"c1","size":0}}`)
+	original, err := u.Get(types.Dot)
+	require.NoError(t, err)
+	err = u.Commit(bad, types.Fingerprint(original, bad))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cluster/size")
+	require.Equal(t, 0, committed)
+}
@@ -22,15 +22,37 @@ type CommitFunc func(proposed *types.Any) error
 
 // NewUpdatablePlugin assembles the implementations into a Updatable implementation
 func NewUpdatablePlugin(reader metadata.Plugin, commit CommitFunc) metadata.Updatable {
+	return NewUpdatablePluginWithSchema(reader, commit, nil)
+}
+
+// NewUpdatablePluginWithSchema assembles the implementations into a Updatable implementation
+// whose changes are validated against the JSON schema.  The schema of the reader is used if
+// the schema given is nil and the reader implements metadata.Schema.
+func NewUpdatablePluginWithSchema(reader metadata.Plugin, commit CommitFunc, schema *types.Any) metadata.Updatable {
 	return &updatable{
-		Plugin: reader,
-		commit: commit,
+		Plugin:   reader,
+		commit:   commit,
+		schema:   schema,
+		watchers: &watchers{},
 	}
 }
 
 type updatable struct {
 	metadata.Plugin
-	commit CommitFunc
+	commit   CommitFunc
+	schema   *types.Any
+	watchers *watchers
+}
+
+// Schema returns the JSON schema of the metadata
+func (p updatable) Schema() *types.Any {
+	if p.schema != nil {
+		return p.schema
+	}
+	if s, is := p.Plugin.(metadata.Schema); is {
+		return s.Schema()
+	}
+	return nil
 }
 
 // changeSet returns a sparse map where the kv pairs of path / value have been
@@ -53,7 +75,12 @@ func (p updatable) load() (original *types.Any, err error) {
 func (p updatable) Changes(changes []metadata.Change) (original, proposed *types.Any, cas string, err error) {
 
 	if u, is := p.Plugin.(metadata.Updatable); is {
-		return u.Changes(changes)
+		// the backend validates with its own schema; only the schema of this layer is checked here
+		original, proposed, cas, err = u.Changes(changes)
+		if err == nil {
+			err = Validate(p.schema, original, proposed)
+		}
+		return
 	}
 
 	// first read the data to be modified
@@ -95,6 +122,10 @@ func (p updatable) Changes(changes []metadata.Change) (original, proposed *types
 
 	log.Debug("proposed", "proposed", proposed.String(), "V", debugV)
 
+	if err = Validate(p.Schema(), original, proposed); err != nil {
+		return
+	}
+
 	cas = types.Fingerprint(original, proposed)
 	return
 }
@@ -111,6 +142,16 @@ func (p updatable) Commit(proposed *types.Any, cas string) error {
 
 		log.Debug("forward commit to backend", "plugin", u, "V", debugV)
 
+		if p.schema != nil {
+			original, err := p.load()
+			if err != nil {
+				return err
+			}
+			if err := Validate(p.schema, original, proposed); err != nil {
+				return err
+			}
+		}
+
 		if err := u.Commit(proposed, cas); err != nil {
 			return err
 		}
@@ -129,7 +170,26 @@ func (p updatable) Commit(proposed *types.Any, cas string) error {
 		if hash != cas {
 			return fmt.Errorf("cas mismatch")
 		}
+
+		if err := Validate(p.Schema(), buff, proposed); err != nil {
+			return err
+		}
 	}
 
-	return p.commit(proposed)
+	if err := p.commit(proposed); err != nil {
+		return err
+	}
+	if p.watchers != nil {
+		p.watchers.changed()
+	}
+	return nil
+}
+
+// Watch returns the updates of the value at the path given.  The watch is delegated to the reader
+// if it notifies of changes; otherwise the value is checked after each commit and polled.
+func (p updatable) Watch(path types.Path) (<-chan metadata.Update, chan<- struct{}, error) {
+	if w, is := p.Plugin.(metadata.Watcher); is {
+		return w.Watch(path)
+	}
+	return p.watchers.watch(p.Plugin.Get, path, DefaultPollInterval)
 }
@@ -0,0 +1,125 @@
+package metadata // import "github.com/docker/infrakit/pkg/plugin/metadata"
+
+import (
+	"sync"
+	"time"
+
+	"github.com/docker/infrakit/pkg/spi/metadata"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+// DefaultPollInterval is the interval of checking the values watched in plugins that
+// don't notify of their changes.
+var DefaultPollInterval = 1 * time.Second
+
+// Watch returns the updates of the value at the path.  Plugins that implement metadata.Watcher
+// are watched directly; the others are polled at the interval given.
+func Watch(p metadata.Plugin, path types.Path, interval time.Duration) (<-chan metadata.Update, chan<- struct{}, error) {
+	if w, is := p.(metadata.Watcher); is {
+		return w.Watch(path)
+	}
+	return (&watchers{}).watch(p.Get, path, interval)
+}
+
+// watchers are the watches of the paths of a plugin
+type watchers struct {
+	set  map[*watch]struct{}
+	lock sync.Mutex
+}
+
+// watch starts a watch of the path.  The value is read by the get function each time the
+// watchers are notified and at the interval, if it's not 0.
+func (ws *watchers) watch(get func(types.Path) (*types.Any, error),
+	path types.Path, interval time.Duration) (<-chan metadata.Update, chan<- struct{}, error) {
+
+	w := &watch{
+		path:    path,
+		get:     get,
+		notify:  make(chan struct{}, 1),
+		updates: make(chan metadata.Update),
+		done:    make(chan struct{}),
+	}
+
+	// added before the first read so the changes after the read are not missed
+	ws.lock.Lock()
+	if ws.set == nil {
+		ws.set = map[*watch]struct{}{}
+	}
+	ws.set[w] = struct{}{}
+	ws.lock.Unlock()
+
+	last, err := get(path)
+	if err != nil {
+		ws.remove(w)
+		return nil, nil, err
+	}
+
+	go func() {
+		defer ws.remove(w)
+		w.run(last, interval)
+	}()
+	return w.updates, w.done, nil
+}
+
+func (ws *watchers) remove(w *watch) {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	delete(ws.set, w)
+}
+
+// changed notifies the watches that the metadata may have changed
+func (ws *watchers) changed() {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	for w := range ws.set {
+		select {
+		case w.notify <- struct{}{}:
+		default: // a check is already pending
+		}
+	}
+}
+
+type watch struct {
+	path    types.Path
+	get     func(types.Path) (*types.Any, error)
+	notify  chan struct{}
+	updates chan metadata.Update
+	done    chan struct{}
+}
+
+func (w *watch) run(last *types.Any, interval time.Duration) {
+	defer close(w.updates)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.notify:
+		case <-tick:
+		}
+
+		current, err := w.get(w.path)
+		if err != nil {
+			log.Warn("Cannot get watched value", "path", w.path, "err", err)
+			continue
+		}
+		if types.Fingerprint(current) == types.Fingerprint(last) {
+			continue
+		}
+
+		log.Debug("Value changed", "path", w.path, "V", debugV)
+		select {
+		case w.updates <- metadata.Update{Path: w.path, Old: last, New: current}:
+			last = current
+		case <-w.done:
+			return
+		}
+	}
+}
@@ -55,6 +55,10 @@ type Info struct {
 type InterfaceDescription struct {
 	spi.InterfaceSpec
 	Methods []MethodDescription
+
+	// Schema (optional) is the JSON schema of the data of the plugin, e.g. the metadata of
+	// an updatable metadata plugin
+	Schema *types.Any `json:",omitempty"`
 }
 
 // MethodDescription contains information about the RPC method such as the request and response
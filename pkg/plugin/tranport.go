@@ -1,5 +1,9 @@
 package plugin // import "github.com/docker/infrakit/pkg/plugin"
 
+import (
+	"github.com/docker/go-connections/tlsconfig"
+)
+
 // Transport describes how the plugin communicates
 type Transport struct {
 	// Name is the name of the plugin
@@ -14,6 +18,15 @@ type Transport struct {
 	// Dir is the directory for discovery (ie location of the socket files, etc.)
 	// If not specified, it will default to system settings (via environment variable -- see pkg/discovery/local
 	Dir string
+
+	// TLS is the optional TLS configuration when listening on tcp.  If the CAFile is set,
+	// clients must present a certificate signed by the CA.  If not set, the server certificate
+	// configured in the environment, if any, is used.  See pkg/run/local
+	TLS *tlsconfig.Options
+
+	// AuthPolicy is the path of the optional authorization policy file enforced on rpc calls
+	// when listening on tcp.  See pkg/rpc/auth
+	AuthPolicy string
 }
 
 // DefaultTransport returns the default transport based on a simple name.  The default is to
@@ -324,6 +324,14 @@ func (p *plugin) SetSize(id group.ID, size int) error {
 	return p.API.ResizeInstanceGroupManager(string(id), int64(size))
 }
 
+func (p *plugin) PromoteUpdate(id group.ID) error {
+	return fmt.Errorf("PromoteUpdate not supported for google group")
+}
+
+func (p *plugin) AbortUpdate(id group.ID) error {
+	return fmt.Errorf("AbortUpdate not supported for google group")
+}
+
 func last(url string) string {
 	parts := strings.Split(url, "/")
 	return parts[len(parts)-1]
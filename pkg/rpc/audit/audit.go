@@ -0,0 +1,136 @@
+package audit // import "github.com/docker/infrakit/pkg/rpc/audit"
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/rpc/auth"
+	"github.com/docker/infrakit/pkg/spi"
+	"github.com/docker/infrakit/pkg/store"
+	"github.com/docker/infrakit/pkg/store/file"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+var log = logutil.New("module", "rpc/audit")
+
+// TypeName is the type name used for the audit records in a store
+const TypeName = "audit"
+
+// mutating lists the mutating methods by interface name.  Note that the manager is exported
+// as the Stack interface.
+var mutating = map[string]map[string]bool{
+	"Instance": {"Provision": true, "Destroy": true, "Label": true},
+	"Group": {"CommitGroup": true, "SetSize": true, "DestroyInstances": true, "FreeGroup": true,
+		"DestroyGroup": true, "PromoteUpdate": true, "AbortUpdate": true},
+	"Controller": {"Commit": true, "Free": true, "Claim": true, "Release": true},
+	"Updatable":  {"Commit": true},
+	"Stack":      {"Enforce": true, "Terminate": true, "Rollback": true, "StepDown": true},
+}
+
+// IsMutating returns true if the method of the interface changes the state of the system
+func IsMutating(spec spi.InterfaceSpec, method string) bool {
+	return mutating[spec.Name][method]
+}
+
+// Record is the audit record of a call
+type Record struct {
+
+	// ID is the id of the record.  IDs sort in the order of the timestamps.
+	ID string
+
+	// Timestamp is when the call was made
+	Timestamp time.Time
+
+	// Plugin is the name of the plugin called
+	Plugin string
+
+	// Caller is the identity of the caller
+	Caller auth.Identity
+
+	// Remote is the remote address of the caller
+	Remote string `json:",omitempty"`
+
+	// Interface is the interface of the method
+	Interface spi.InterfaceSpec
+
+	// Method is the method called, e.g. Destroy
+	Method string
+
+	// Digest is the sha256 digest of the arguments
+	Digest string
+
+	// Error is the error returned by the call, empty if the call succeeded
+	Error string `json:",omitempty"`
+}
+
+// Succeeded returns true if the call completed without error
+func (r Record) Succeeded() bool {
+	return r.Error == ""
+}
+
+// Sink receives audit records
+type Sink interface {
+
+	// Record records the audit record
+	Record(Record) error
+}
+
+// SinkFunc adapts a function to a Sink
+type SinkFunc func(Record) error
+
+// Record implements Sink
+func (f SinkFunc) Record(r Record) error {
+	return f(r)
+}
+
+// NewID returns a new record id for the timestamp
+func NewID(t time.Time) string {
+	return fmt.Sprintf("%020d-%06d", t.UnixNano(), rand.Intn(1000000))
+}
+
+type kvSink struct {
+	kv store.KV
+}
+
+// KVSink returns a sink that writes the records to the store
+func KVSink(kv store.KV) Sink {
+	return &kvSink{kv: kv}
+}
+
+// FileSink returns a sink that writes the records as files in the directory
+func FileSink(dir string) Sink {
+	return KVSink(file.NewStore(TypeName, dir))
+}
+
+// Record implements Sink
+func (s *kvSink) Record(r Record) error {
+	any, err := types.AnyValue(r)
+	if err != nil {
+		return err
+	}
+	return s.kv.Write(r.ID, any.Bytes())
+}
+
+// List returns the records in the store, sorted by time
+func List(kv store.KV) ([]Record, error) {
+	entries, err := kv.Entries()
+	if err != nil {
+		return nil, err
+	}
+	records := []Record{}
+	for entry := range entries {
+		r := Record{}
+		if err := types.AnyBytes(entry.Value).Decode(&r); err != nil {
+			log.Warn("skipping bad record", "key", entry.Key, "err", err)
+			continue
+		}
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ID < records[j].ID
+	})
+	return records, nil
+}
@@ -0,0 +1,79 @@
+package audit // import "github.com/docker/infrakit/pkg/rpc/audit"
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/infrakit/pkg/spi"
+	"github.com/docker/infrakit/pkg/store/mem"
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerRecordsMutatingCalls(t *testing.T) {
+	kv := mem.NewStore("audit")
+
+	next := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		body := bytes.Buffer{}
+		body.ReadFrom(req.Body)
+		if bytes.Contains(body.Bytes(), []byte("bad")) {
+			resp.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32000,"message":"boom"},"id":1}`))
+			return
+		}
+		resp.Write([]byte(`{"jsonrpc":"2.0","result":{},"id":1}`))
+	})
+
+	spec := spi.InterfaceSpec{Name: "Instance", Version: "0.6.0"}
+	handler := Handler(KVSink(kv), "simulator",
+		func(service string) (spi.InterfaceSpec, bool) {
+			return spec, service == "Instance"
+		}, next)
+
+	call := func(method string, params interface{}, token string) error {
+		body, err := json2.EncodeClientRequest(method, params)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		result := map[string]interface{}{}
+		return json2.DecodeClientResponse(resp.Body, &result)
+	}
+
+	require.NoError(t, call("Instance.DescribeInstances", map[string]string{"a": "b"}, ""))
+	require.NoError(t, call("Instance.Destroy", map[string]string{"ID": "i-1"}, "s3cr3t"))
+	require.Error(t, call("Instance.Provision", map[string]string{"ID": "bad"}, ""))
+
+	records, err := List(kv)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(records))
+
+	require.Equal(t, "Destroy", records[0].Method)
+	require.Equal(t, spec, records[0].Interface)
+	require.Equal(t, "simulator", records[0].Plugin)
+	require.Contains(t, records[0].Caller.String(), "token=")
+	require.True(t, records[0].Succeeded())
+	require.Equal(t, 64, len(records[0].Digest))
+
+	require.Equal(t, "Provision", records[1].Method)
+	require.False(t, records[1].Succeeded())
+	require.Equal(t, "boom", records[1].Error)
+	require.Equal(t, "anonymous", records[1].Caller.String())
+	require.NotEqual(t, records[0].Digest, records[1].Digest)
+}
+
+func TestIsMutating(t *testing.T) {
+	require.True(t, IsMutating(spi.InterfaceSpec{Name: "Group"}, "CommitGroup"))
+	require.True(t, IsMutating(spi.InterfaceSpec{Name: "Stack"}, "Enforce"))
+	require.True(t, IsMutating(spi.InterfaceSpec{Name: "Stack"}, "Rollback"))
+	require.True(t, IsMutating(spi.InterfaceSpec{Name: "Stack"}, "StepDown"))
+	require.True(t, IsMutating(spi.InterfaceSpec{Name: "Updatable"}, "Commit"))
+	require.True(t, IsMutating(spi.InterfaceSpec{Name: "Controller"}, "Claim"))
+	require.True(t, IsMutating(spi.InterfaceSpec{Name: "Controller"}, "Release"))
+	require.False(t, IsMutating(spi.InterfaceSpec{Name: "Metadata"}, "Get"))
+	require.False(t, IsMutating(spi.InterfaceSpec{Name: "Group"}, "DescribeGroup"))
+}
@@ -0,0 +1,110 @@
+package audit // import "github.com/docker/infrakit/pkg/rpc/audit"
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docker/infrakit/pkg/rpc"
+	"github.com/docker/infrakit/pkg/rpc/auth"
+	"github.com/docker/infrakit/pkg/spi"
+)
+
+// Handler returns a http handler that records the mutating json-rpc calls to the sink after the
+// next handler has served them.  The plugin is the name of the plugin served.  If resolve is nil or cannot
+// resolve the service, the service name is used as the interface name.
+func Handler(sink Sink, plugin string, resolve auth.Resolver, next http.Handler) http.Handler {
+	if sink == nil {
+		return next
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(resp, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		call := struct {
+			Method string           `json:"method"`
+			Params *json.RawMessage `json:"params"`
+		}{}
+		if err := json.Unmarshal(body, &call); err != nil || call.Method == "" {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		service, method := call.Method, ""
+		if i := strings.LastIndex(call.Method, "."); i > 0 {
+			service, method = call.Method[:i], call.Method[i+1:]
+		}
+		spec := spi.InterfaceSpec{Name: service}
+		if resolve != nil {
+			if s, has := resolve(service); has {
+				spec = s
+			}
+		}
+
+		if !IsMutating(spec, method) {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		now := time.Now()
+		record := Record{
+			ID:        NewID(now),
+			Timestamp: now,
+			Plugin:    plugin,
+			Caller:    auth.IdentityFromRequest(req),
+			Remote:    req.RemoteAddr,
+			Interface: spec,
+			Method:    method,
+		}
+		if call.Params != nil {
+			record.Digest = fmt.Sprintf("%x", sha256.Sum256(*call.Params))
+		}
+
+		recorder := rpc.NewRecorder()
+		next.ServeHTTP(recorder, req)
+
+		record.Error = CallError(recorder)
+		if err := sink.Record(record); err != nil {
+			log.Error("cannot record", "record", record, "err", err)
+		}
+
+		for k, v := range recorder.HeaderMap {
+			resp.Header()[k] = v
+		}
+		resp.WriteHeader(recorder.Code)
+		recorder.Body.WriteTo(resp)
+	})
+}
+
+// CallError returns the error message of the json-rpc response, if any
+func CallError(recorder *rpc.ResponseRecorder) string {
+	if recorder.Code != http.StatusOK {
+		return http.StatusText(recorder.Code)
+	}
+	response := struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		return err.Error()
+	}
+	if response.Error != nil {
+		return response.Error.Message
+	}
+	return ""
+}
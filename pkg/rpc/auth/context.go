@@ -0,0 +1,36 @@
+package auth // import "github.com/docker/infrakit/pkg/rpc/auth"
+
+import (
+	"context"
+	"net/http"
+)
+
+// Like the spans of pkg/rpc/trace, the identity of the caller is carried in the context of the request
+// so that the plugins that want it (see spi.Contextual) can tell who made a change.
+
+type contextKey int
+
+const callerKey contextKey = 0
+
+// NewContext returns a copy of the context that carries the identity of the caller
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, callerKey, id)
+}
+
+// FromContext returns the identity of the caller carried by the context.  Returns false if the
+// context is not of a call being served.
+func FromContext(ctx context.Context) (Identity, bool) {
+	if ctx == nil {
+		return Identity{}, false
+	}
+	id, has := ctx.Value(callerKey).(Identity)
+	return id, has
+}
+
+// CallerHandler returns a http handler that adds the identity of the caller to the context of
+// the request.
+func CallerHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(resp, req.WithContext(NewContext(req.Context(), IdentityFromRequest(req))))
+	})
+}
@@ -0,0 +1,30 @@
+package auth // import "github.com/docker/infrakit/pkg/rpc/auth"
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallerHandler(t *testing.T) {
+	_, has := FromContext(context.Background())
+	require.False(t, has)
+
+	var caller Identity
+	handler := CallerHandler(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		caller, has = FromContext(req.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.True(t, has)
+	require.Equal(t, "s3cr3t", caller.Token)
+
+	_, has = FromContext(req.Context())
+	require.False(t, has)
+}
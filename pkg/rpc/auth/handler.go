@@ -0,0 +1,172 @@
+package auth // import "github.com/docker/infrakit/pkg/rpc/auth"
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/spi"
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+var log = logutil.New("module", "rpc/auth")
+
+// handshakeService is the name of the rpc service for exchanging the interfaces -- see pkg/rpc/handshake.go
+const handshakeService = "Handshake"
+
+// Identity is the identity of the caller
+type Identity struct {
+
+	// CommonName is the common name of the verified client certificate
+	CommonName string `json:",omitempty"`
+
+	// Token is the bearer token.  This is never logged or published.
+	Token string `json:"-"`
+
+	// TokenID identifies the token without revealing it.  It is a prefix of the token's sha256 digest.
+	TokenID string `json:",omitempty"`
+}
+
+// String returns a printable form of the identity
+func (id Identity) String() string {
+	switch {
+	case id.CommonName != "":
+		return "cn=" + id.CommonName
+	case id.TokenID != "":
+		return "token=" + id.TokenID
+	}
+	return "anonymous"
+}
+
+// IdentityFromRequest returns the identity of the caller from the verified client certificate
+// and the bearer token in the Authorization header.
+func IdentityFromRequest(req *http.Request) Identity {
+	id := Identity{}
+	if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 && len(req.TLS.VerifiedChains[0]) > 0 {
+		id.CommonName = req.TLS.VerifiedChains[0][0].Subject.CommonName
+	}
+	if h := req.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		id.Token = strings.TrimSpace(strings.TrimPrefix(h, "Bearer "))
+		id.TokenID = fmt.Sprintf("%x", sha256.Sum256([]byte(id.Token)))[:12]
+	}
+	return id
+}
+
+// Denial describes a call that was denied by the policy
+type Denial struct {
+
+	// Identity is the caller
+	Identity Identity
+
+	// Interface is the interface of the method
+	Interface spi.InterfaceSpec
+
+	// Method is the method called, e.g. Destroy
+	Method string
+
+	// Remote is the remote address of the caller
+	Remote string
+}
+
+// Resolver returns the interface implemented by the rpc service of the given name (e.g. Instance for
+// the method Instance.Destroy).
+type Resolver func(service string) (spi.InterfaceSpec, bool)
+
+// Handler returns a http handler that enforces the policy on json-rpc calls before passing
+// the request to the next handler.  If resolve is nil or cannot resolve the service, the service
+// name is used as the interface name.  The onDeny callback, if not nil, is called for each denied call.
+func Handler(policy *Policy, resolve Resolver, onDeny func(Denial), next http.Handler) http.Handler {
+	if policy == nil {
+		return next
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(resp, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		call := struct {
+			Method string           `json:"method"`
+			ID     *json.RawMessage `json:"id"`
+		}{}
+		if err := json.Unmarshal(body, &call); err != nil {
+			// not a json-rpc call.  It cannot be authorized.
+			http.Error(resp, fmt.Sprintf("bad json-rpc request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if call.Method == "" {
+			http.Error(resp, "bad json-rpc request: no method", http.StatusBadRequest)
+			return
+		}
+
+		service, method := call.Method, ""
+		if i := strings.LastIndex(call.Method, "."); i > 0 {
+			service, method = call.Method[:i], call.Method[i+1:]
+		}
+		if service == handshakeService {
+			// always allowed so clients can discover the interfaces
+			next.ServeHTTP(resp, req)
+			return
+		}
+		spec := spi.InterfaceSpec{Name: service}
+		if resolve != nil {
+			if s, has := resolve(service); has {
+				spec = s
+			}
+		}
+
+		id := IdentityFromRequest(req)
+		if policy.Allow(id, spec, method) {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		denial := Denial{
+			Identity:  id,
+			Interface: spec,
+			Method:    method,
+			Remote:    req.RemoteAddr,
+		}
+		log.Warn("Denied", "identity", id, "interface", spec, "method", method, "remote", req.RemoteAddr)
+		if onDeny != nil {
+			onDeny(denial)
+		}
+		writeDenied(resp, call.ID, denial)
+	})
+}
+
+// writeDenied writes a json-rpc error response so that the client returns an error
+func writeDenied(resp http.ResponseWriter, id *json.RawMessage, denial Denial) {
+	buff, err := json.Marshal(struct {
+		Version string           `json:"jsonrpc"`
+		Error   *json2.Error     `json:"error"`
+		ID      *json.RawMessage `json:"id"`
+	}{
+		Version: "2.0",
+		Error: &json2.Error{
+			Code: json2.E_SERVER,
+			Message: fmt.Sprintf("permission denied: %v is not allowed to call %s.%s",
+				denial.Identity, denial.Interface.Name, denial.Method),
+		},
+		ID: id,
+	})
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusForbidden)
+	resp.Write(buff)
+}
@@ -0,0 +1,84 @@
+package auth // import "github.com/docker/infrakit/pkg/rpc/auth"
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/infrakit/pkg/spi"
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	policy := &Policy{
+		Roles: map[string][]Rule{
+			"readonly": {
+				{Interface: "Instance", Methods: []string{"DescribeInstances"}},
+			},
+		},
+		Bindings: []Binding{
+			{Token: "s3cr3t", Roles: []string{"readonly"}},
+		},
+	}
+
+	called := 0
+	next := http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		called++
+		resp.Write([]byte(`{"jsonrpc":"2.0","result":{},"id":1}`))
+	})
+
+	denials := []Denial{}
+	handler := Handler(policy,
+		func(service string) (spi.InterfaceSpec, bool) {
+			return instanceSpec, service == "Instance"
+		},
+		func(d Denial) {
+			denials = append(denials, d)
+		}, next)
+
+	call := func(method, token string) error {
+		body, err := json2.EncodeClientRequest(method, struct{}{})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		result := map[string]interface{}{}
+		return json2.DecodeClientResponse(resp.Body, &result)
+	}
+
+	require.NoError(t, call("Instance.DescribeInstances", "s3cr3t"))
+	require.Equal(t, 1, called)
+
+	err := call("Instance.Destroy", "s3cr3t")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "permission denied")
+	require.Equal(t, 1, called)
+
+	require.Error(t, call("Instance.DescribeInstances", ""))
+	require.Equal(t, 1, called)
+
+	require.Equal(t, 2, len(denials))
+	require.Equal(t, "Destroy", denials[0].Method)
+	require.Equal(t, instanceSpec, denials[0].Interface)
+	require.Equal(t, "anonymous", denials[1].Identity.String())
+
+	// Non-rpc requests pass through
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/info/api.json", nil))
+	require.Equal(t, 2, called)
+
+	// Posts that are not json-rpc calls cannot be authorized
+	for _, body := range []string{`not json`, `{"id":1}`, `{"method":"","id":1}`} {
+		resp = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body)))
+		req.Header.Set("Authorization", "Bearer s3cr3t")
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+	}
+	require.Equal(t, 2, called)
+}
@@ -0,0 +1,120 @@
+package auth // import "github.com/docker/infrakit/pkg/rpc/auth"
+
+import (
+	"crypto/subtle"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/infrakit/pkg/spi"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+const (
+	// Any matches any interface or method in a rule
+	Any = "*"
+)
+
+// Rule allows calls of the given methods on an interface
+type Rule struct {
+
+	// Interface is the name of the interface, e.g. Instance, or Instance/0.6.0 to restrict to
+	// a specific version.  Use * to match any interface.
+	Interface string
+
+	// Methods are the method names, e.g. DescribeInstances.  Use * to match any method.
+	Methods []string
+}
+
+// Binding binds an identity to a list of roles
+type Binding struct {
+
+	// CommonName is the common name (CN) of the client certificate
+	CommonName string `json:",omitempty" yaml:",omitempty"`
+
+	// Token is the bearer token presented in the Authorization header
+	Token string `json:",omitempty" yaml:",omitempty"`
+
+	// Roles are the names of the roles granted to the identity
+	Roles []string
+}
+
+// Policy maps identities to the methods they are allowed to call
+type Policy struct {
+
+	// Roles are named lists of rules
+	Roles map[string][]Rule
+
+	// Bindings bind identities to roles
+	Bindings []Binding
+
+	// Default is the role granted to identities without bindings, including anonymous callers.
+	// If not set, such calls are denied.
+	Default string `json:",omitempty" yaml:",omitempty"`
+}
+
+// LoadPolicy loads the policy from a YAML or JSON file
+func LoadPolicy(path string) (*Policy, error) {
+	buff, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	any, err := types.AnyYAML(buff)
+	if err != nil {
+		any = types.AnyBytes(buff)
+	}
+	policy := Policy{}
+	if err := any.Decode(&policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// roles returns the roles granted to the identity
+func (p Policy) roles(id Identity) []string {
+	roles := []string{}
+	for _, b := range p.Bindings {
+		if b.CommonName != "" && b.CommonName == id.CommonName {
+			roles = append(roles, b.Roles...)
+		} else if b.Token != "" && subtle.ConstantTimeCompare([]byte(b.Token), []byte(id.Token)) == 1 {
+			roles = append(roles, b.Roles...)
+		}
+	}
+	if len(roles) == 0 && p.Default != "" {
+		roles = append(roles, p.Default)
+	}
+	return roles
+}
+
+// Allow returns true if the identity is allowed to call the method of the given interface
+func (p Policy) Allow(id Identity, spec spi.InterfaceSpec, method string) bool {
+	for _, role := range p.roles(id) {
+		for _, rule := range p.Roles[role] {
+			if rule.matches(spec, method) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r Rule) matches(spec spi.InterfaceSpec, method string) bool {
+	if r.Interface != Any {
+		name := r.Interface
+		version := ""
+		if i := strings.Index(r.Interface, "/"); i > 0 {
+			name, version = r.Interface[:i], r.Interface[i+1:]
+		}
+		if name != spec.Name {
+			return false
+		}
+		if version != "" && version != spec.Version {
+			return false
+		}
+	}
+	for _, m := range r.Methods {
+		if m == Any || m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package auth // import "github.com/docker/infrakit/pkg/rpc/auth"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/infrakit/pkg/spi"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	instanceSpec = spi.InterfaceSpec{Name: "Instance", Version: "0.6.0"}
+	groupSpec    = spi.InterfaceSpec{Name: "Group", Version: "0.1.0"}
+)
+
+func TestLoadPolicyAndAllow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "infrakit-auth")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "policy.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+Roles:
+  readonly:
+    - Interface: Instance
+      Methods: [ DescribeInstances ]
+    - Interface: Group/0.1.0
+      Methods: [ DescribeGroup, InspectGroups ]
+    - Interface: Metadata
+      Methods: [ Keys, Get ]
+  admin:
+    - Interface: "*"
+      Methods: [ "*" ]
+Bindings:
+  - CommonName: ops
+    Roles: [ admin ]
+  - Token: s3cr3t
+    Roles: [ readonly ]
+`), 0600))
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+
+	readonly := Identity{Token: "s3cr3t"}
+	require.True(t, policy.Allow(readonly, instanceSpec, "DescribeInstances"))
+	require.False(t, policy.Allow(readonly, instanceSpec, "Destroy"))
+	require.True(t, policy.Allow(readonly, groupSpec, "DescribeGroup"))
+	require.False(t, policy.Allow(readonly, groupSpec, "CommitGroup"))
+	require.False(t, policy.Allow(readonly, spi.InterfaceSpec{Name: "Group", Version: "0.2.0"}, "DescribeGroup"))
+
+	admin := Identity{CommonName: "ops"}
+	require.True(t, policy.Allow(admin, instanceSpec, "Destroy"))
+	require.True(t, policy.Allow(admin, groupSpec, "CommitGroup"))
+
+	require.False(t, policy.Allow(Identity{}, instanceSpec, "DescribeInstances"))
+	require.False(t, policy.Allow(Identity{Token: "wrong"}, instanceSpec, "DescribeInstances"))
+
+	policy.Default = "readonly"
+	require.True(t, policy.Allow(Identity{}, instanceSpec, "DescribeInstances"))
+	require.False(t, policy.Allow(Identity{}, instanceSpec, "Destroy"))
+}
@@ -0,0 +1,31 @@
+package client // import "github.com/docker/infrakit/pkg/rpc/client"
+
+import (
+	"os"
+	"sync"
+
+	"github.com/docker/infrakit/pkg/run/local"
+)
+
+var (
+	tokens     = map[string]string{}
+	tokensLock sync.RWMutex
+)
+
+// RegisterToken registers the bearer token to present when connecting to the given host (host:port).
+// Hosts without registered tokens use the token from the environment, if any.
+func RegisterToken(host string, token string) {
+	tokensLock.Lock()
+	defer tokensLock.Unlock()
+	tokens[host] = token
+}
+
+// token returns the bearer token for the host, or empty string if none.
+func token(host string) string {
+	tokensLock.RLock()
+	defer tokensLock.RUnlock()
+	if t, has := tokens[host]; has {
+		return t
+	}
+	return os.Getenv(local.EnvAuthToken)
+}
@@ -2,6 +2,7 @@ package client // import "github.com/docker/infrakit/pkg/rpc/client"
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -14,6 +15,7 @@ import (
 
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/rpc"
+	"github.com/docker/infrakit/pkg/rpc/trace"
 	"github.com/docker/infrakit/pkg/run/local"
 	"github.com/docker/infrakit/pkg/spi"
 	"github.com/gorilla/rpc/v2/json2"
@@ -28,6 +30,7 @@ type client struct {
 	http *http.Client
 	addr string
 	url  *url.URL
+	ctx  context.Context // optional context of the calls, e.g. the trace continued
 }
 
 // NewHandshaker returns a handshaker object, or a generic, untyped rpc object
@@ -147,6 +150,12 @@ func parseAddress(address string) (connectURL *url.URL, httpClient *http.Client,
 				}).Dial,
 				TLSHandshakeTimeout: local.ClientTimeout(),
 			}
+			if connectURL.Scheme == "https" {
+				transport.TLSClientConfig, err = TLSConfig(connectURL.Host)
+				if err != nil {
+					return
+				}
+			}
 			httpClient = &http.Client{Transport: transport}
 			cacheClient(address, httpClient)
 		}
@@ -177,7 +186,29 @@ func (c client) Addr() string {
 	return c.addr
 }
 
-func (c client) Call(method string, arg interface{}, result interface{}) error {
+// WithContext returns a client whose calls continue the trace of the context, if any.  Clients
+// other than the rpc clients of this package are returned as is.
+func WithContext(c Client, ctx context.Context) Client {
+	switch c := c.(type) {
+	case *client:
+		copy := *c
+		copy.ctx = ctx
+		return &copy
+	case *handshakingClient:
+		copy := *c
+		copy.client = WithContext(c.client, ctx).(*client)
+		return &copy
+	}
+	return c
+}
+
+func (c client) Call(method string, arg interface{}, result interface{}) (err error) {
+	// continue the trace of the call in the context, if any
+	current := trace.FromContext(c.ctx)
+	span := trace.Start(current.Service(), method, trace.KindClient, current.Context())
+	span.SetTag("peer.address", c.addr)
+	defer func() { span.Finish(err) }()
+
 	message, err := json2.EncodeClientRequest(method, arg)
 	if err != nil {
 		return err
@@ -188,6 +219,10 @@ func (c client) Call(method string, arg interface{}, result interface{}) error {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if t := token(c.url.Host); t != "" {
+		req.Header.Set("Authorization", "Bearer "+t)
+	}
+	trace.Inject(span, req.Header)
 
 	requestData, err := httputil.DumpRequest(req, true)
 	if err == nil {
@@ -0,0 +1,40 @@
+package client // import "github.com/docker/infrakit/pkg/rpc/client"
+
+import (
+	"crypto/tls"
+	"sync"
+
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/docker/infrakit/pkg/rpc"
+	"github.com/docker/infrakit/pkg/run/local"
+)
+
+var (
+	tlsOptions     = map[string]tlsconfig.Options{}
+	tlsOptionsLock sync.RWMutex
+)
+
+// RegisterTLS registers the TLS options to use when connecting to the given host (host:port).
+// Hosts without registered options use the options from the environment, if any.
+func RegisterTLS(host string, options tlsconfig.Options) {
+	tlsOptionsLock.Lock()
+	defer tlsOptionsLock.Unlock()
+	tlsOptions[host] = options
+}
+
+// TLSConfig returns the tls config for connecting to the given host (host:port).  It returns
+// nil if no TLS options are registered for the host or set in the environment.
+func TLSConfig(host string) (*tls.Config, error) {
+	tlsOptionsLock.RLock()
+	options, has := tlsOptions[host]
+	tlsOptionsLock.RUnlock()
+
+	if !has {
+		env := local.TLSOptions()
+		if env == nil {
+			return nil, nil
+		}
+		options = *env
+	}
+	return rpc.ClientTLSConfig(options)
+}
@@ -1,6 +1,9 @@
 package controller // import "github.com/docker/infrakit/pkg/rpc/controller"
 
 import (
+	"context"
+	"time"
+
 	"github.com/docker/infrakit/pkg/plugin"
 	rpc_client "github.com/docker/infrakit/pkg/rpc/client"
 	"github.com/docker/infrakit/pkg/spi/controller"
@@ -26,6 +29,11 @@ type client struct {
 	client rpc_client.Client
 }
 
+// WithContext implements spi.Contextual.  The calls continue the trace of the context, if any.
+func (c client) WithContext(ctx context.Context) interface{} {
+	return &client{name: c.name, client: rpc_client.WithContext(c.client, ctx)}
+}
+
 // Plan is a commit without actually making the changes.  The controller returns a proposed object state
 // after commit, with a Plan, or error.
 func (c client) Plan(operation controller.Operation, spec types.Spec) (types.Object, controller.Plan, error) {
@@ -78,3 +86,38 @@ func (c client) Free(metadata *types.Metadata) ([]types.Object, error) {
 	err := c.client.Call("Controller.Free", req, &resp)
 	return resp.Objects, err
 }
+
+// Claim leases an idle resource of the object matching the metadata to the consumer, for the given duration.
+func (c client) Claim(metadata types.Metadata, consumer string, ttl time.Duration) (controller.Lease, error) {
+	req := ClaimRequest{
+		Name:     c.name,
+		Metadata: metadata,
+		Consumer: consumer,
+		TTL:      types.FromDuration(ttl),
+	}
+	resp := ClaimResponse{}
+	err := c.client.Call("Controller.Claim", req, &resp)
+	return resp.Lease, err
+}
+
+// Release ends the lease of the given id before it expires
+func (c client) Release(metadata types.Metadata, id string) error {
+	req := ReleaseRequest{
+		Name:     c.name,
+		Metadata: metadata,
+		ID:       id,
+	}
+	resp := ReleaseResponse{}
+	return c.client.Call("Controller.Release", req, &resp)
+}
+
+// Leases returns the current leases of the object matching the metadata
+func (c client) Leases(metadata types.Metadata) ([]controller.Lease, error) {
+	req := LeasesRequest{
+		Name:     c.name,
+		Metadata: metadata,
+	}
+	resp := LeasesResponse{}
+	err := c.client.Call("Controller.Leases", req, &resp)
+	return resp.Leases, err
+}
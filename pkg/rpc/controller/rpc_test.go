@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/docker/infrakit/pkg/plugin"
 	rpc_server "github.com/docker/infrakit/pkg/rpc/server"
@@ -188,3 +189,59 @@ func TestControllerFree(t *testing.T) {
 	server.Stop()
 
 }
+
+func TestControllerLeasing(t *testing.T) {
+	socketPath := tempSocket()
+	name := filepath.Base(socketPath)
+
+	search := types.Metadata{Name: "workers"}
+	lease := controller.Lease{
+		ID:       "workers_0001",
+		Consumer: "ci",
+		Expires:  time.Now().Add(time.Minute).UTC().Truncate(time.Second),
+	}
+	lease.Instance.ID = "i-1"
+
+	actual := make(chan []interface{}, 3)
+
+	small := &testing_controller.Controller{
+		DoClaim: func(metadata types.Metadata, consumer string, ttl time.Duration) (controller.Lease, error) {
+			actual <- []interface{}{metadata, consumer, ttl}
+			if consumer == "" {
+				return controller.Lease{}, fmt.Errorf("no consumer")
+			}
+			return lease, nil
+		},
+		DoRelease: func(metadata types.Metadata, id string) error {
+			actual <- []interface{}{metadata, id}
+			return nil
+		},
+		DoLeases: func(metadata types.Metadata) ([]controller.Lease, error) {
+			actual <- []interface{}{metadata}
+			return []controller.Lease{lease}, nil
+		},
+	}
+	server, err := rpc_server.StartPluginAtPath(socketPath, Server(small))
+	require.NoError(t, err)
+	defer server.Stop()
+
+	leasing, is := must(NewClient(plugin.Name(name), socketPath)).(controller.Leasing)
+	require.True(t, is)
+
+	claimed, err := leasing.Claim(search, "ci", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, lease, claimed)
+	require.Equal(t, []interface{}{search, "ci", time.Minute}, <-actual)
+
+	_, err = leasing.Claim(search, "", time.Minute)
+	require.Error(t, err)
+	<-actual
+
+	leases, err := leasing.Leases(search)
+	require.NoError(t, err)
+	require.Equal(t, []controller.Lease{lease}, leases)
+	require.Equal(t, []interface{}{search}, <-actual)
+
+	require.NoError(t, leasing.Release(search, lease.ID))
+	require.Equal(t, []interface{}{search, lease.ID}, <-actual)
+}
@@ -75,9 +75,9 @@ func (c *Controller) Objects() []rpc.Object {
 }
 
 // Plan is the rpc method for Plan
-func (c *Controller) Plan(_ *http.Request, req *ChangeRequest, resp *ChangeResponse) error {
+func (c *Controller) Plan(r *http.Request, req *ChangeRequest, resp *ChangeResponse) error {
 
-	return c.keyed.Do(req, func(v interface{}) error {
+	return c.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		object, plan, err := v.(controller.Controller).Plan(req.Operation, req.Spec)
 		if err == nil {
@@ -90,9 +90,9 @@ func (c *Controller) Plan(_ *http.Request, req *ChangeRequest, resp *ChangeRespo
 }
 
 // Commit is the rpc method for Commit
-func (c *Controller) Commit(_ *http.Request, req *ChangeRequest, resp *ChangeResponse) error {
+func (c *Controller) Commit(r *http.Request, req *ChangeRequest, resp *ChangeResponse) error {
 
-	return c.keyed.Do(req, func(v interface{}) error {
+	return c.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		object, err := v.(controller.Controller).Commit(req.Operation, req.Spec)
 		if err == nil {
@@ -103,9 +103,9 @@ func (c *Controller) Commit(_ *http.Request, req *ChangeRequest, resp *ChangeRes
 }
 
 // Describe is the rpc method for Describe
-func (c *Controller) Describe(_ *http.Request, req *FindRequest, resp *FindResponse) error {
+func (c *Controller) Describe(r *http.Request, req *FindRequest, resp *FindResponse) error {
 	pn, _ := req.Plugin()
-	return c.keyed.Do(req, func(v interface{}) error {
+	return c.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		log.Debug("Describe", "req", req, "p", pn, "v", v, "meta", req.Metadata)
 
 		resp.Name = req.Name
@@ -118,9 +118,9 @@ func (c *Controller) Describe(_ *http.Request, req *FindRequest, resp *FindRespo
 }
 
 // Free is the rpc method for Free
-func (c *Controller) Free(_ *http.Request, req *FindRequest, resp *FindResponse) error {
+func (c *Controller) Free(r *http.Request, req *FindRequest, resp *FindResponse) error {
 
-	return c.keyed.Do(req, func(v interface{}) error {
+	return c.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		objects, err := v.(controller.Controller).Free(req.Metadata)
 		if err == nil {
@@ -129,3 +129,50 @@ func (c *Controller) Free(_ *http.Request, req *FindRequest, resp *FindResponse)
 		return err
 	})
 }
+
+// Claim is the rpc method for Claim
+func (c *Controller) Claim(r *http.Request, req *ClaimRequest, resp *ClaimResponse) error {
+
+	return c.keyed.DoContext(r.Context(), req, func(v interface{}) error {
+		resp.Name = req.Name
+		leasing, is := v.(controller.Leasing)
+		if !is {
+			return controller.ErrLeasingNotSupported
+		}
+		lease, err := leasing.Claim(req.Metadata, req.Consumer, req.TTL.Duration())
+		if err == nil {
+			resp.Lease = lease
+		}
+		return err
+	})
+}
+
+// Release is the rpc method for Release
+func (c *Controller) Release(r *http.Request, req *ReleaseRequest, resp *ReleaseResponse) error {
+
+	return c.keyed.DoContext(r.Context(), req, func(v interface{}) error {
+		resp.Name = req.Name
+		leasing, is := v.(controller.Leasing)
+		if !is {
+			return controller.ErrLeasingNotSupported
+		}
+		return leasing.Release(req.Metadata, req.ID)
+	})
+}
+
+// Leases is the rpc method for Leases
+func (c *Controller) Leases(r *http.Request, req *LeasesRequest, resp *LeasesResponse) error {
+
+	return c.keyed.DoContext(r.Context(), req, func(v interface{}) error {
+		resp.Name = req.Name
+		leasing, is := v.(controller.Leasing)
+		if !is {
+			return controller.ErrLeasingNotSupported
+		}
+		leases, err := leasing.Leases(req.Metadata)
+		if err == nil {
+			resp.Leases = leases
+		}
+		return err
+	})
+}
@@ -41,3 +41,56 @@ type FindResponse struct {
 	Name    plugin.Name
 	Objects []types.Object
 }
+
+// ClaimRequest is the request message for Claim
+type ClaimRequest struct {
+	Name     plugin.Name
+	Metadata types.Metadata
+	Consumer string
+	TTL      types.Duration
+}
+
+// Plugin implements pkg/rpc/internal/Addressable
+func (r ClaimRequest) Plugin() (plugin.Name, error) {
+	return r.Name, nil
+}
+
+// ClaimResponse is the response message for Claim
+type ClaimResponse struct {
+	Name  plugin.Name
+	Lease controller.Lease
+}
+
+// ReleaseRequest is the request message for Release
+type ReleaseRequest struct {
+	Name     plugin.Name
+	Metadata types.Metadata
+	ID       string
+}
+
+// Plugin implements pkg/rpc/internal/Addressable
+func (r ReleaseRequest) Plugin() (plugin.Name, error) {
+	return r.Name, nil
+}
+
+// ReleaseResponse is the response message for Release
+type ReleaseResponse struct {
+	Name plugin.Name
+}
+
+// LeasesRequest is the request message for Leases
+type LeasesRequest struct {
+	Name     plugin.Name
+	Metadata types.Metadata
+}
+
+// Plugin implements pkg/rpc/internal/Addressable
+func (r LeasesRequest) Plugin() (plugin.Name, error) {
+	return r.Name, nil
+}
+
+// LeasesResponse is the response message for Leases
+type LeasesResponse struct {
+	Name   plugin.Name
+	Leases []controller.Lease
+}
@@ -16,6 +16,9 @@ import (
 
 var (
 	log = logutil.New("module", "rpc/event")
+
+	// ReconnectInterval is the time to wait before resuming a lost stream of events
+	ReconnectInterval = 1 * time.Second
 )
 
 // NewClient returns a plugin interface implementation connected to a remote plugin
@@ -47,8 +50,25 @@ func (c client) List(topic types.Path) ([]string, error) {
 
 // SubscribeOn returns the subscriber channel for the topic
 func (c *client) SubscribeOn(topic types.Path) (<-chan *event.Event, chan<- struct{}, error) {
+	return c.SubscribeWithFilter(topic, event.Filter{})
+}
 
-	opts := broker.Options{SocketDir: path.Dir(c.address), Path: rpc.URLEventsPrefix}
+// SubscribeWithFilter returns the subscriber channel for the events of the topic selected by the filter
+func (c *client) SubscribeWithFilter(topic types.Path, filter event.Filter) (<-chan *event.Event, chan<- struct{}, error) {
+
+	opts := broker.Options{
+		SocketDir: path.Dir(c.address),
+		Path:      rpc.URLEventsPrefix,
+		Reconnect: ReconnectInterval,
+	}
+
+	if !filter.Empty() {
+		query, err := FilterQuery(filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts.Query = query
+	}
 
 	url := fmt.Sprintf("unix://%s", path.Base(c.address))
 
@@ -0,0 +1,45 @@
+package event // import "github.com/docker/infrakit/pkg/rpc/event"
+
+import (
+	"encoding/json"
+	"net/url"
+
+	broker "github.com/docker/infrakit/pkg/broker/server"
+	"github.com/docker/infrakit/pkg/spi/event"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+// FilterParam is the query parameter of the filter of a subscription, as json
+const FilterParam = "filter"
+
+// FilterQuery returns the query parameters of the filter
+func FilterQuery(filter event.Filter) (url.Values, error) {
+	buff, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	return url.Values{FilterParam: []string{string(buff)}}, nil
+}
+
+// FilterFromQuery returns the filter in the query parameters.  The filter is empty if not in the query.
+func FilterFromQuery(query url.Values) (filter event.Filter, err error) {
+	if v := query.Get(FilterParam); v != "" {
+		err = json.Unmarshal([]byte(v), &filter)
+	}
+	return
+}
+
+// Filters returns the filter of the events of a subscription for the broker, from the query of the request
+func Filters(query url.Values) (broker.Filter, error) {
+	filter, err := FilterFromQuery(query)
+	if err != nil || filter.Empty() {
+		return nil, err
+	}
+	selector, err := filter.Selector()
+	if err != nil {
+		return nil, err
+	}
+	return func(data []byte) bool {
+		return selector(new(event.Event).FromAny(types.AnyBytes(data)))
+	}, nil
+}
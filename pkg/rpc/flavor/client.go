@@ -1,6 +1,8 @@
 package flavor // import "github.com/docker/infrakit/pkg/rpc/flavor"
 
 import (
+	"context"
+
 	"github.com/docker/infrakit/pkg/plugin"
 	rpc_client "github.com/docker/infrakit/pkg/rpc/client"
 	"github.com/docker/infrakit/pkg/spi/flavor"
@@ -28,6 +30,11 @@ type client struct {
 	client rpc_client.Client
 }
 
+// WithContext implements spi.Contextual.  The calls continue the trace of the context, if any.
+func (c client) WithContext(ctx context.Context) interface{} {
+	return &client{name: c.name, client: rpc_client.WithContext(c.client, ctx)}
+}
+
 // Validate checks whether the helper can support a configuration.
 func (c client) Validate(flavorProperties *types.Any, allocation group.AllocationMethod) error {
 	_, flavorType := c.name.GetLookupAndType()
@@ -1,6 +1,8 @@
 package group // import "github.com/docker/infrakit/pkg/rpc/group"
 
 import (
+	"context"
+
 	"github.com/docker/infrakit/pkg/plugin"
 	rpc_client "github.com/docker/infrakit/pkg/rpc/client"
 	"github.com/docker/infrakit/pkg/spi/group"
@@ -28,6 +30,11 @@ type client struct {
 	client rpc_client.Client
 }
 
+// WithContext implements spi.Contextual.  The calls continue the trace of the context, if any.
+func (c client) WithContext(ctx context.Context) interface{} {
+	return &client{name: c.name, client: rpc_client.WithContext(c.client, ctx)}
+}
+
 func (c client) CommitGroup(grp group.Spec, pretend bool) (string, error) {
 	req := CommitGroupRequest{Name: c.name, Spec: grp, Pretend: pretend}
 	resp := CommitGroupResponse{}
@@ -90,3 +97,15 @@ func (c client) SetSize(id group.ID, size int) error {
 	resp := SetSizeResponse{}
 	return c.client.Call("Group.SetSize", req, &resp)
 }
+
+func (c client) PromoteUpdate(id group.ID) error {
+	req := PromoteUpdateRequest{Name: c.name, ID: id}
+	resp := PromoteUpdateResponse{}
+	return c.client.Call("Group.PromoteUpdate", req, &resp)
+}
+
+func (c client) AbortUpdate(id group.ID) error {
+	req := AbortUpdateRequest{Name: c.name, ID: id}
+	resp := AbortUpdateResponse{}
+	return c.client.Call("Group.AbortUpdate", req, &resp)
+}
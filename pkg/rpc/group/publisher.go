@@ -21,9 +21,59 @@ func (p *Group) List(topic types.Path) (child []string, err error) {
 		types.Put([]string{"describe"}, "", m)
 	}
 
-	return types.List(topic, m), nil
+	child = types.List(topic, m)
+
+	// Include the topics of the group plugins that are event producers themselves
+	err = p.keyed.Each(func(name string, object interface{}) error {
+		ev, is := object.(event.Plugin)
+		if !is {
+			return nil
+		}
+		sub := topic
+		if name != "." {
+			if len(topic) == 0 || topic[0] != name {
+				return nil
+			}
+			sub = topic[1:]
+		}
+		nodes, err := ev.List(sub)
+		if err != nil {
+			return err
+		}
+		child = append(child, nodes...)
+		return nil
+	})
+	return
 }
 
-// PublishOn sets the channel to publish
-func (p *Group) PublishOn(chan<- *event.Event) {
+// PublishOn sets the channel to publish.  Events of the group plugins that are event producers
+// are forwarded, with the topics prefixed by the name of the plugin object.
+func (p *Group) PublishOn(c chan<- *event.Event) {
+	p.keyed.Each(func(name string, object interface{}) error {
+		pub, is := object.(event.Publisher)
+		if !is {
+			return nil
+		}
+
+		if name == "." {
+			pub.PublishOn(c)
+			return nil
+		}
+
+		cc := make(chan *event.Event)
+		pub.PublishOn(cc)
+		namespace := name
+
+		go func() {
+			for {
+				if e, ok := <-cc; ok {
+					e.Topic = types.PathFromString(namespace).Join(e.Topic)
+					c <- e
+				} else {
+					return
+				}
+			}
+		}()
+		return nil
+	})
 }
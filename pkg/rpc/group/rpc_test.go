@@ -377,3 +377,35 @@ func TestGroupNamedPluginSizeSetSize(t *testing.T) {
 	require.Equal(t, 1001, <-sizeActual)
 	require.Equal(t, gid, <-gidActual)
 }
+
+func TestGroupPluginPromoteAbortUpdate(t *testing.T) {
+	socketPath := tempSocket()
+
+	promoted := make(chan group.ID, 1)
+	aborted := make(chan group.ID, 1)
+
+	server, err := rpc_server.StartPluginAtPath(socketPath, PluginServer(&testing_group.Plugin{
+		DoPromoteUpdate: func(gid group.ID) error {
+			promoted <- gid
+			return nil
+		},
+		DoAbortUpdate: func(gid group.ID) error {
+			aborted <- gid
+			return errors.New("not paused")
+		},
+	}))
+	require.NoError(t, err)
+
+	gid := group.ID("group1")
+	client := must(NewClient(nameFromPath(socketPath), socketPath))
+
+	require.NoError(t, client.PromoteUpdate(gid))
+	require.Equal(t, gid, <-promoted)
+
+	err = client.AbortUpdate(gid)
+	require.Error(t, err)
+	require.Equal(t, "not paused", err.Error())
+	require.Equal(t, gid, <-aborted)
+
+	server.Stop()
+}
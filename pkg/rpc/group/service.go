@@ -74,8 +74,8 @@ func (p *Group) Objects() []rpc.Object {
 }
 
 // CommitGroup is the rpc method to commit a group
-func (p *Group) CommitGroup(_ *http.Request, req *CommitGroupRequest, resp *CommitGroupResponse) error {
-	return p.keyed.Do(req, func(v interface{}) error {
+func (p *Group) CommitGroup(r *http.Request, req *CommitGroupRequest, resp *CommitGroupResponse) error {
+	return p.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		details, err := v.(group.Plugin).CommitGroup(req.Spec, req.Pretend)
 		if err != nil {
@@ -88,8 +88,8 @@ func (p *Group) CommitGroup(_ *http.Request, req *CommitGroupRequest, resp *Comm
 }
 
 // FreeGroup is the rpc method to free a group
-func (p *Group) FreeGroup(_ *http.Request, req *FreeGroupRequest, resp *FreeGroupResponse) error {
-	return p.keyed.Do(req, func(v interface{}) error {
+func (p *Group) FreeGroup(r *http.Request, req *FreeGroupRequest, resp *FreeGroupResponse) error {
+	return p.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		err := v.(group.Plugin).FreeGroup(req.ID)
 		if err != nil {
@@ -101,8 +101,8 @@ func (p *Group) FreeGroup(_ *http.Request, req *FreeGroupRequest, resp *FreeGrou
 }
 
 // DescribeGroup is the rpc method to describe a group
-func (p *Group) DescribeGroup(_ *http.Request, req *DescribeGroupRequest, resp *DescribeGroupResponse) error {
-	return p.keyed.Do(req, func(v interface{}) error {
+func (p *Group) DescribeGroup(r *http.Request, req *DescribeGroupRequest, resp *DescribeGroupResponse) error {
+	return p.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		desc, err := v.(group.Plugin).DescribeGroup(req.ID)
 		if err != nil {
@@ -115,8 +115,8 @@ func (p *Group) DescribeGroup(_ *http.Request, req *DescribeGroupRequest, resp *
 }
 
 // DestroyGroup is the rpc method to destroy a group
-func (p *Group) DestroyGroup(_ *http.Request, req *DestroyGroupRequest, resp *DestroyGroupResponse) error {
-	return p.keyed.Do(req, func(v interface{}) error {
+func (p *Group) DestroyGroup(r *http.Request, req *DestroyGroupRequest, resp *DestroyGroupResponse) error {
+	return p.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		err := v.(group.Plugin).DestroyGroup(req.ID)
 		if err != nil {
@@ -128,8 +128,8 @@ func (p *Group) DestroyGroup(_ *http.Request, req *DestroyGroupRequest, resp *De
 }
 
 // InspectGroups is the rpc method to inspect groups
-func (p *Group) InspectGroups(_ *http.Request, req *InspectGroupsRequest, resp *InspectGroupsResponse) error {
-	return p.keyed.Do(req, func(v interface{}) error {
+func (p *Group) InspectGroups(r *http.Request, req *InspectGroupsRequest, resp *InspectGroupsResponse) error {
+	return p.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		groups, err := v.(group.Plugin).InspectGroups()
 		if err != nil {
@@ -141,8 +141,8 @@ func (p *Group) InspectGroups(_ *http.Request, req *InspectGroupsRequest, resp *
 }
 
 // DestroyInstances is the rpc method to destroy specific instances
-func (p *Group) DestroyInstances(_ *http.Request, req *DestroyInstancesRequest, resp *DestroyInstancesResponse) error {
-	return p.keyed.Do(req, func(v interface{}) error {
+func (p *Group) DestroyInstances(r *http.Request, req *DestroyInstancesRequest, resp *DestroyInstancesResponse) error {
+	return p.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		err := v.(group.Plugin).DestroyInstances(req.ID, req.Instances)
 		if err != nil {
@@ -154,8 +154,8 @@ func (p *Group) DestroyInstances(_ *http.Request, req *DestroyInstancesRequest,
 }
 
 // Size is the rpc method to get the group target size
-func (p *Group) Size(_ *http.Request, req *SizeRequest, resp *SizeResponse) error {
-	return p.keyed.Do(req, func(v interface{}) error {
+func (p *Group) Size(r *http.Request, req *SizeRequest, resp *SizeResponse) error {
+	return p.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		size, err := v.(group.Plugin).Size(req.ID)
 		if err != nil {
@@ -168,8 +168,8 @@ func (p *Group) Size(_ *http.Request, req *SizeRequest, resp *SizeResponse) erro
 }
 
 // SetSize is the rpc method to set the group target size
-func (p *Group) SetSize(_ *http.Request, req *SetSizeRequest, resp *SetSizeResponse) error {
-	return p.keyed.Do(req, func(v interface{}) error {
+func (p *Group) SetSize(r *http.Request, req *SetSizeRequest, resp *SetSizeResponse) error {
+	return p.keyed.DoContext(r.Context(), req, func(v interface{}) error {
 		resp.Name = req.Name
 		err := v.(group.Plugin).SetSize(req.ID, req.Size)
 		if err != nil {
@@ -179,3 +179,29 @@ func (p *Group) SetSize(_ *http.Request, req *SetSizeRequest, resp *SetSizeRespo
 		return nil
 	})
 }
+
+// PromoteUpdate is the rpc method to promote an update paused after its canary phase
+func (p *Group) PromoteUpdate(r *http.Request, req *PromoteUpdateRequest, resp *PromoteUpdateResponse) error {
+	return p.keyed.DoContext(r.Context(), req, func(v interface{}) error {
+		resp.Name = req.Name
+		err := v.(group.Plugin).PromoteUpdate(req.ID)
+		if err != nil {
+			return err
+		}
+		resp.ID = req.ID
+		return nil
+	})
+}
+
+// AbortUpdate is the rpc method to abort an update paused after its canary phase
+func (p *Group) AbortUpdate(r *http.Request, req *AbortUpdateRequest, resp *AbortUpdateResponse) error {
+	return p.keyed.DoContext(r.Context(), req, func(v interface{}) error {
+		resp.Name = req.Name
+		err := v.(group.Plugin).AbortUpdate(req.ID)
+		if err != nil {
+			return err
+		}
+		resp.ID = req.ID
+		return nil
+	})
+}
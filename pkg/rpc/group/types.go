@@ -148,3 +148,37 @@ type SetSizeResponse struct {
 	Name plugin.Name
 	ID   group.ID
 }
+
+// PromoteUpdateRequest is the rpc wrapper for the input to promote an update
+type PromoteUpdateRequest struct {
+	Name plugin.Name
+	ID   group.ID
+}
+
+// Plugin implements pkg/rpc/internal/Addressable
+func (r PromoteUpdateRequest) Plugin() (plugin.Name, error) {
+	return r.Name, nil
+}
+
+// PromoteUpdateResponse is the rpc wrapper for the output of promoting an update
+type PromoteUpdateResponse struct {
+	Name plugin.Name
+	ID   group.ID
+}
+
+// AbortUpdateRequest is the rpc wrapper for the input to abort an update
+type AbortUpdateRequest struct {
+	Name plugin.Name
+	ID   group.ID
+}
+
+// Plugin implements pkg/rpc/internal/Addressable
+func (r AbortUpdateRequest) Plugin() (plugin.Name, error) {
+	return r.Name, nil
+}
+
+// AbortUpdateResponse is the rpc wrapper for the output of aborting an update
+type AbortUpdateResponse struct {
+	Name plugin.Name
+	ID   group.ID
+}
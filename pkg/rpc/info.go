@@ -1,5 +1,9 @@
 package rpc // import "github.com/docker/infrakit/pkg/rpc"
 
+import (
+	"github.com/docker/infrakit/pkg/types"
+)
+
 const (
 	// URLAPI is the well-known HTTP GET endpoint that retrieves description of the plugin's interfaces.
 	URLAPI = "/info/api.json"
@@ -19,3 +23,11 @@ type InputExample interface {
 	// The request param must be a pointer
 	SetExampleProperties(request interface{})
 }
+
+// Schema is the interface implemented by the rpc implementations that serve data described
+// by a JSON schema.  The schema is included in the description of the interface.
+type Schema interface {
+
+	// Schema returns the JSON schema or nil if there's none
+	Schema() *types.Any
+}
@@ -1,6 +1,8 @@
 package instance // import "github.com/docker/infrakit/pkg/rpc/instance"
 
 import (
+	"context"
+
 	"github.com/docker/infrakit/pkg/plugin"
 	rpc_client "github.com/docker/infrakit/pkg/rpc/client"
 	"github.com/docker/infrakit/pkg/spi/instance"
@@ -26,6 +28,11 @@ type client struct {
 	client rpc_client.Client
 }
 
+// WithContext implements spi.Contextual.  The calls continue the trace of the context, if any.
+func (c client) WithContext(ctx context.Context) interface{} {
+	return &client{name: c.name, client: rpc_client.WithContext(c.client, ctx)}
+}
+
 // Validate performs local validation on a provision request.
 func (c client) Validate(properties *types.Any) error {
 	_, instanceType := c.name.GetLookupAndType()
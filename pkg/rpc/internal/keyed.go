@@ -1,12 +1,14 @@
 package internal // import "github.com/docker/infrakit/pkg/rpc/internal"
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/plugin"
 	"github.com/docker/infrakit/pkg/rpc"
+	"github.com/docker/infrakit/pkg/spi"
 )
 
 var log = logutil.New("module", "rpc/internal")
@@ -51,6 +53,20 @@ func (k *Keyed) Objects() []rpc.Object {
 	return objs
 }
 
+// Each calls the function for each of the keyed objects
+func (k *Keyed) Each(fn func(key string, object interface{}) error) error {
+	m, err := k.listFunc()
+	if err != nil {
+		return err
+	}
+	for key, object := range m {
+		if err := fn(key, object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Do performs work calling the work function once the request resolves to an object
 func (k *Keyed) Do(request Addressable, work func(resolved interface{}) error) error {
 	resolved, err := k.Resolve(request)
@@ -61,6 +77,14 @@ func (k *Keyed) Do(request Addressable, work func(resolved interface{}) error) e
 	return work(resolved)
 }
 
+// DoContext performs work like Do but the object resolved is used in the context of the call, e.g. the
+// caller and the trace of the request, if the object is spi.Contextual
+func (k *Keyed) DoContext(ctx context.Context, request Addressable, work func(resolved interface{}) error) error {
+	return k.Do(request, func(resolved interface{}) error {
+		return work(spi.WithContext(resolved, ctx))
+	})
+}
+
 // Resolve resolves input (a request object for example) that implements the Addressable interface into a plugin
 func (k *Keyed) Resolve(request Addressable) (interface{}, error) {
 	to, err := request.Plugin()
@@ -42,6 +42,16 @@ func (c client) LeaderLocation() (*url.URL, error) {
 	return resp.Location, err
 }
 
+// StepDown makes the leader give up the leadership
+func (c client) StepDown(successor string) error {
+	req := StepDownRequest{
+		Successor: successor,
+	}
+	resp := StepDownResponse{}
+	err := c.client.Call("Manager.StepDown", req, &resp)
+	return err
+}
+
 // Enforce enforces infrastructure state to match that of the specs
 func (c client) Enforce(specs []types.Spec) error {
 	req := EnforceRequest{
@@ -77,3 +87,31 @@ func (c client) Terminate(specs []types.Spec) error {
 	err := c.client.Call("Manager.Terminate", req, &resp)
 	return err
 }
+
+// History returns the revisions of the specs
+func (c client) History() ([]stack.Revision, error) {
+	req := HistoryRequest{}
+	resp := HistoryResponse{}
+	err := c.client.Call("Manager.History", req, &resp)
+	return resp.Revisions, err
+}
+
+// Revision returns the revision with its specs
+func (c client) Revision(rev int) (stack.Revision, error) {
+	req := RevisionRequest{
+		Revision: rev,
+	}
+	resp := RevisionResponse{}
+	err := c.client.Call("Manager.Revision", req, &resp)
+	return resp.Revision, err
+}
+
+// Rollback enforces the specs of an earlier revision
+func (c client) Rollback(rev int) error {
+	req := RollbackRequest{
+		Revision: rev,
+	}
+	resp := RollbackResponse{}
+	err := c.client.Call("Manager.Rollback", req, &resp)
+	return err
+}
@@ -1,11 +1,14 @@
 package manager // import "github.com/docker/infrakit/pkg/rpc/manager"
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"path"
 	"testing"
+	"time"
 
+	"github.com/docker/infrakit/pkg/rpc/auth"
 	"github.com/docker/infrakit/pkg/rpc/server"
 	"github.com/docker/infrakit/pkg/spi/stack"
 	testing_manager "github.com/docker/infrakit/pkg/testing/manager"
@@ -227,3 +230,94 @@ func TestManagerInspect(t *testing.T) {
 	server.Stop()
 
 }
+
+func TestManagerHistory(t *testing.T) {
+	socketPath := tempSocket()
+
+	now := time.Now().Round(time.Second)
+	spec := types.Spec{
+		Kind: "group",
+		Metadata: types.Metadata{
+			Name: "workers",
+		},
+		Properties: types.AnyValueMust(map[string]interface{}{"a": 1}),
+	}
+
+	rollbacks := make(chan int, 1)
+	callers := make(chan bool, 1)
+	m := &testing_manager.Plugin{
+		DoHistory: func() ([]stack.Revision, error) {
+			return []stack.Revision{
+				{Revision: 1, Author: "cn=admin", Timestamp: now, Fingerprint: "f1"},
+				{Revision: 2, Author: "local", Timestamp: now, Fingerprint: "f2"},
+			}, nil
+		},
+		DoRevision: func(rev int) (stack.Revision, error) {
+			if rev != 1 {
+				return stack.Revision{}, errors.New("not found")
+			}
+			return stack.Revision{Revision: 1, Fingerprint: "f1", Specs: []types.Spec{spec}}, nil
+		},
+		DoRollback: func(rev int) error {
+			rollbacks <- rev
+			return nil
+		},
+	}
+	server, err := server.StartPluginAtPath(socketPath, PluginServer(&inContext{Plugin: m, callers: callers}))
+	require.NoError(t, err)
+	defer server.Stop()
+
+	revisions, err := must(NewClient(socketPath)).History()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(revisions))
+	require.Equal(t, "cn=admin", revisions[0].Author)
+	require.True(t, now.Equal(revisions[1].Timestamp))
+
+	revision, err := must(NewClient(socketPath)).Revision(1)
+	require.NoError(t, err)
+	require.Equal(t, "f1", revision.Fingerprint)
+	require.EqualValues(t, types.AnyValueMust([]types.Spec{spec}), types.AnyValueMust(revision.Specs))
+
+	_, err = must(NewClient(socketPath)).Revision(3)
+	require.Error(t, err)
+
+	require.NoError(t, must(NewClient(socketPath)).Rollback(1))
+	require.True(t, <-callers)
+	require.Equal(t, 1, <-rollbacks)
+}
+
+// inContext checks that the context of the call is passed to the manager
+type inContext struct {
+	*testing_manager.Plugin
+	callers chan bool
+}
+
+func (m *inContext) WithContext(ctx context.Context) interface{} {
+	_, has := auth.FromContext(ctx)
+	m.callers <- has
+	return m.Plugin
+}
+
+func TestManagerStepDown(t *testing.T) {
+	socketPath := tempSocket()
+
+	successors := make(chan string, 1)
+	m := &testing_manager.Plugin{
+		DoStepDown: func(successor string) error {
+			successors <- successor
+			if successor == "m3" {
+				return errors.New("not a manager")
+			}
+			return nil
+		},
+	}
+	server, err := server.StartPluginAtPath(socketPath, PluginServer(m))
+	require.NoError(t, err)
+	defer server.Stop()
+
+	require.NoError(t, must(NewClient(socketPath)).StepDown("m2"))
+	require.Equal(t, "m2", <-successors)
+
+	require.Error(t, must(NewClient(socketPath)).StepDown("m3"))
+	require.Equal(t, "m3", <-successors)
+}
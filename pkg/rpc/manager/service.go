@@ -20,6 +20,11 @@ type Manager struct {
 	manager stack.Interface
 }
 
+// in returns the manager to use in the context of the request, e.g. the caller and the trace of the call
+func (p *Manager) in(r *http.Request) stack.Interface {
+	return spi.WithContext(p.manager, r.Context()).(stack.Interface)
+}
+
 // ImplementedInterface returns the interface implemented by this RPC service.
 func (p *Manager) ImplementedInterface() spi.InterfaceSpec {
 	return stack.InterfaceSpec
@@ -66,6 +71,20 @@ func (p *Manager) LeaderLocation(_ *http.Request, req *LeaderLocationRequest, re
 	return err
 }
 
+// StepDownRequest is the rpc request
+type StepDownRequest struct {
+	Successor string
+}
+
+// StepDownResponse is the rpc response
+type StepDownResponse struct {
+}
+
+// StepDown makes the leader give up the leadership
+func (p *Manager) StepDown(r *http.Request, req *StepDownRequest, resp *StepDownResponse) error {
+	return p.in(r).StepDown(req.Successor)
+}
+
 // EnforceRequest is the rpc request
 type EnforceRequest struct {
 	Specs []types.Spec
@@ -76,8 +95,8 @@ type EnforceResponse struct {
 }
 
 // Enforce is the rpc method for Manager.Enforce
-func (p *Manager) Enforce(_ *http.Request, req *EnforceRequest, resp *EnforceResponse) error {
-	return p.manager.Enforce(req.Specs)
+func (p *Manager) Enforce(r *http.Request, req *EnforceRequest, resp *EnforceResponse) error {
+	return p.in(r).Enforce(req.Specs)
 }
 
 // SpecsRequest is the rpc request
@@ -128,6 +147,59 @@ type TerminateResponse struct {
 }
 
 // Terminate is the rpc method for Manager.Terminate
-func (p *Manager) Terminate(_ *http.Request, req *TerminateRequest, resp *TerminateResponse) error {
-	return p.manager.Terminate(req.Specs)
+func (p *Manager) Terminate(r *http.Request, req *TerminateRequest, resp *TerminateResponse) error {
+	return p.in(r).Terminate(req.Specs)
+}
+
+// HistoryRequest is the rpc request
+type HistoryRequest struct {
+}
+
+// HistoryResponse is the rpc response
+type HistoryResponse struct {
+	Revisions []stack.Revision
+}
+
+// History is the rpc method for Manager.History
+func (p *Manager) History(_ *http.Request, req *HistoryRequest, resp *HistoryResponse) error {
+	revisions, err := p.manager.History()
+	if err != nil {
+		return err
+	}
+	resp.Revisions = revisions
+	return nil
+}
+
+// RevisionRequest is the rpc request
+type RevisionRequest struct {
+	Revision int
+}
+
+// RevisionResponse is the rpc response
+type RevisionResponse struct {
+	Revision stack.Revision
+}
+
+// Revision is the rpc method for Manager.Revision
+func (p *Manager) Revision(_ *http.Request, req *RevisionRequest, resp *RevisionResponse) error {
+	revision, err := p.manager.Revision(req.Revision)
+	if err != nil {
+		return err
+	}
+	resp.Revision = revision
+	return nil
+}
+
+// RollbackRequest is the rpc request
+type RollbackRequest struct {
+	Revision int
+}
+
+// RollbackResponse is the rpc response
+type RollbackResponse struct {
+}
+
+// Rollback is the rpc method for Manager.Rollback
+func (p *Manager) Rollback(r *http.Request, req *RollbackRequest, resp *RollbackResponse) error {
+	return p.in(r).Rollback(req.Revision)
 }
@@ -60,3 +60,8 @@ func (c client) Keys(path types.Path) ([]string, error) {
 func (c client) Get(path types.Path) (*types.Any, error) {
 	return get(c.name, c.client, "Metadata.Get", path)
 }
+
+// Watch returns the updates of the value at the path.
+func (c client) Watch(path types.Path) (<-chan metadata.Update, chan<- struct{}, error) {
+	return watch(c.name, c.client, "Metadata.Watch", "Metadata.Get", path)
+}
@@ -1,9 +1,12 @@
 package metadata // import "github.com/docker/infrakit/pkg/rpc/metadata"
 
 import (
+	"time"
+
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/plugin"
 	rpc "github.com/docker/infrakit/pkg/rpc/client"
+	"github.com/docker/infrakit/pkg/spi/metadata"
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -25,3 +28,58 @@ func get(name plugin.Name, client rpc.Client, method string, path types.Path) (*
 	}
 	return resp.Value, err
 }
+
+var (
+	// WatchTimeout is the time each Watch call waits on the server for a change
+	WatchTimeout = 5 * time.Second
+
+	// WatchRetry is the time to wait before calling again after a failed Watch call
+	WatchRetry = 1 * time.Second
+)
+
+// watch long-polls the server for the changes of the value at the path until done is closed.
+func watch(name plugin.Name, client rpc.Client, method, getMethod string,
+	path types.Path) (<-chan metadata.Update, chan<- struct{}, error) {
+
+	last, err := get(name, client, getMethod, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updates := make(chan metadata.Update)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(updates)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			req := WatchRequest{Name: name, Path: path, Value: last, Timeout: types.FromDuration(WatchTimeout)}
+			resp := WatchResponse{}
+			if err := client.Call(method, req, &resp); err != nil {
+				log.Warn("Cannot watch", "name", name, "path", path, "err", err)
+				select {
+				case <-done:
+					return
+				case <-time.After(WatchRetry):
+				}
+				continue
+			}
+			if !resp.Changed {
+				continue
+			}
+
+			select {
+			case updates <- metadata.Update{Path: path, Old: last, New: resp.Value}:
+				last = resp.Value
+			case <-done:
+				return
+			}
+		}
+	}()
+	return updates, done, nil
+}
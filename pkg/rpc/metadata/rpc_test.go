@@ -6,8 +6,10 @@ import (
 	"path/filepath"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/docker/infrakit/pkg/plugin"
+	metadata_plugin "github.com/docker/infrakit/pkg/plugin/metadata"
 	rpc_client "github.com/docker/infrakit/pkg/rpc/client"
 	rpc_server "github.com/docker/infrakit/pkg/rpc/server"
 	"github.com/docker/infrakit/pkg/spi/metadata"
@@ -319,3 +321,44 @@ func TestMetadataMultiPlugin4(t *testing.T) {
 	require.Nil(t, firstAny(must(NewClient(nameFromPath(socketPath), socketPath)).Get(types.PathFromString("aws/none"))))
 	server.Stop()
 }
+
+func TestMetadataWatch(t *testing.T) {
+	socketPath := tempSocket()
+
+	writes := make(chan func(map[string]interface{}))
+
+	server, err := rpc_server.StartPluginAtPath(socketPath, Server(metadata_plugin.NewPluginFromChannel(writes)))
+	require.NoError(t, err)
+	defer server.Stop()
+
+	writes <- func(m map[string]interface{}) {
+		types.Put(types.PathFromString("metrics/instances/count"), 100, m)
+	}
+
+	WatchTimeout = 100 * time.Millisecond // exercise the calls ending without changes
+
+	watcher, is := must(NewClient(nameFromPath(socketPath), socketPath)).(metadata.Watcher)
+	require.True(t, is)
+
+	updates, done, err := watcher.Watch(types.PathFromString("metrics/instances/count"))
+	require.NoError(t, err)
+	defer close(done)
+
+	time.Sleep(300 * time.Millisecond)
+
+	writes <- func(m map[string]interface{}) {
+		types.Put(types.PathFromString("metrics/networks/count"), 10, m) // not watched
+	}
+	writes <- func(m map[string]interface{}) {
+		types.Put(types.PathFromString("metrics/instances/count"), 200, m)
+	}
+
+	select {
+	case update := <-updates:
+		require.Equal(t, types.PathFromString("metrics/instances/count"), update.Path)
+		require.Equal(t, "100", update.Old.String())
+		require.Equal(t, "200", update.New.String())
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "no update")
+	}
+}
@@ -3,14 +3,21 @@ package metadata // import "github.com/docker/infrakit/pkg/rpc/metadata"
 import (
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/docker/infrakit/pkg/plugin"
+	metadata_plugin "github.com/docker/infrakit/pkg/plugin/metadata"
 	"github.com/docker/infrakit/pkg/rpc"
 	"github.com/docker/infrakit/pkg/rpc/internal"
 	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/spi/metadata"
+	"github.com/docker/infrakit/pkg/types"
 )
 
+// MaxWatchTimeout is the longest a Watch call waits for a change.  It is kept under the
+// timeout of the rpc clients.
+var MaxWatchTimeout = 10 * time.Second
+
 // ServerWithNames which supports namespaced plugins
 func ServerWithNames(subplugins func() (map[string]metadata.Plugin, error)) *Metadata {
 
@@ -87,3 +94,48 @@ func (p *Metadata) Get(_ *http.Request, req *GetRequest, resp *GetResponse) erro
 		return err
 	})
 }
+
+// Watch returns the value at the path given when it differs from the value in the request, or
+// when the timeout expires.
+func (p *Metadata) Watch(_ *http.Request, req *WatchRequest, resp *WatchResponse) error {
+
+	return p.keyed.Do(req, func(v interface{}) error {
+		resp.Name = req.Name
+		return waitForChange(v.(metadata.Plugin), req, resp)
+	})
+}
+
+// waitForChange blocks until the value at the path of the request changes
+func waitForChange(p metadata.Plugin, req *WatchRequest, resp *WatchResponse) error {
+	timeout := req.Timeout.AtMost(MaxWatchTimeout)
+	if timeout <= 0 {
+		timeout = MaxWatchTimeout
+	}
+
+	updates, done, err := metadata_plugin.Watch(p, req.Path, metadata_plugin.DefaultPollInterval)
+	if err != nil {
+		return err
+	}
+	defer close(done)
+
+	// changed before the watch started
+	current, err := p.Get(req.Path)
+	if err != nil {
+		return err
+	}
+	resp.Value = current
+	if types.Fingerprint(current) != types.Fingerprint(req.Value) {
+		resp.Changed = true
+		return nil
+	}
+
+	select {
+	case update, ok := <-updates:
+		if ok {
+			resp.Value = update.New
+			resp.Changed = types.Fingerprint(update.New) != types.Fingerprint(req.Value)
+		}
+	case <-time.After(timeout):
+	}
+	return nil
+}
@@ -75,3 +75,24 @@ func (r CommitRequest) Plugin() (plugin.Name, error) {
 type CommitResponse struct {
 	Name plugin.Name
 }
+
+// WatchRequest is the rpc wrapper of the params to Watch.  The call returns when the value at
+// the path differs from the value given or when the timeout expires.
+type WatchRequest struct {
+	Name    plugin.Name
+	Path    types.Path
+	Value   *types.Any
+	Timeout types.Duration
+}
+
+// Plugin implements pkg/rpc/internal/Addressable
+func (r WatchRequest) Plugin() (plugin.Name, error) {
+	return r.Name, nil
+}
+
+// WatchResponse is the rpc wrapper of the result of Watch
+type WatchResponse struct {
+	Name    plugin.Name
+	Value   *types.Any
+	Changed bool
+}
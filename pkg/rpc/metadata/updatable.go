@@ -53,6 +53,15 @@ func (u *Updatable) VendorInfo() *spi.VendorInfo {
 	return nil
 }
 
+// Schema returns the JSON schema of the metadata, if the plugin implements it.  See metadata.Schema
+func (u *Updatable) Schema() *types.Any {
+	base, _ := u.keyed.Keyed(plugin.Name("."))
+	if s, is := base.(metadata.Schema); is {
+		return s.Schema()
+	}
+	return nil
+}
+
 // ImplementedInterface returns the interface implemented by this RPC service.
 func (u *Updatable) ImplementedInterface() spi.InterfaceSpec {
 	return metadata.UpdatableInterfaceSpec
@@ -124,6 +133,16 @@ func (u *Updatable) Commit(_ *http.Request, req *CommitRequest, resp *CommitResp
 	})
 }
 
+// Watch returns the value at the path given when it differs from the value in the request, or
+// when the timeout expires.
+func (u *Updatable) Watch(_ *http.Request, req *WatchRequest, resp *WatchResponse) error {
+
+	return u.keyed.Do(req, func(v interface{}) error {
+		resp.Name = req.Name
+		return waitForChange(v.(metadata.Plugin), req, resp)
+	})
+}
+
 ///////
 type updatable struct {
 	name   plugin.Name
@@ -169,3 +188,8 @@ func (u updatable) Commit(proposed *types.Any, cas string) error {
 	resp := CommitResponse{}
 	return u.client.Call("Updatable.Commit", req, &resp)
 }
+
+// Watch returns the updates of the value at the path.
+func (u updatable) Watch(path types.Path) (<-chan metadata.Update, chan<- struct{}, error) {
+	return watch(u.name, u.client, "Updatable.Watch", "Updatable.Get", path)
+}
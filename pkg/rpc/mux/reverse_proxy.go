@@ -15,7 +15,9 @@ import (
 	logutil "github.com/docker/infrakit/pkg/log"
 	manager_discovery "github.com/docker/infrakit/pkg/manager/discovery"
 	"github.com/docker/infrakit/pkg/plugin"
+	rpc_client "github.com/docker/infrakit/pkg/rpc/client"
 	"github.com/docker/infrakit/pkg/rpc/event"
+	"github.com/docker/infrakit/pkg/rpc/trace"
 	event_spi "github.com/docker/infrakit/pkg/spi/event"
 	"github.com/docker/infrakit/pkg/types"
 )
@@ -72,6 +74,16 @@ func (rp *ReverseProxy) listPlugins(resp http.ResponseWriter, req *http.Request,
 
 // ServeHTTP implements HTTP handler
 func (rp *ReverseProxy) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost {
+		// record the hop and make it the parent of the span of the plugin called
+		if parent := trace.Extract(req.Header); parent != nil || trace.Enabled() {
+			span := trace.Start("mux", req.URL.Path, trace.KindServer, parent)
+			span.SetTag("peer.address", req.RemoteAddr)
+			span.SetTag("forward", fmt.Sprintf("%v", rp.forward != nil))
+			trace.Inject(span, req.Header)
+			defer span.Finish(nil)
+		}
+	}
 	if rp.forward != nil {
 		rp.forwardHTTP(resp, req)
 		return
@@ -106,6 +118,14 @@ func (rp *ReverseProxy) forwardHTTP(resp http.ResponseWriter, req *http.Request)
 	log.Debug("forwarding traffic", "url", rp.forward, "V", logutil.V(100), "req", req)
 	reversep := httputil.NewSingleHostReverseProxy(rp.forward)
 	reversep.Director = defaultDirector(rp.forward)
+	if rp.forward.Scheme == "https" {
+		config, err := rpc_client.TLSConfig(rp.forward.Host)
+		if err != nil {
+			http.Error(resp, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		reversep.Transport = &http.Transport{TLSClientConfig: config}
+	}
 	handler := &loggingHandler{handler: reversep}
 	handler.ServeHTTP(resp, req)
 	return
@@ -172,13 +192,19 @@ func (rp *ReverseProxy) serveHTTPLocal(resp http.ResponseWriter, req *http.Reque
 			http.Error(resp, "cannot connect to events", http.StatusInternalServerError)
 			return
 		}
-		subscriber, is := ep.(event_spi.Subscriber)
+		subscriber, is := ep.(event_spi.FilteredSubscriber)
 		if !is {
 			http.Error(resp, "no subscriber implementation", http.StatusInternalServerError)
 			return
 		}
 
-		events, stop, err := subscriber.SubscribeOn(topicPath)
+		filter, err := event.FilterFromQuery(req.URL.Query())
+		if err != nil {
+			http.Error(resp, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		events, stop, err := subscriber.SubscribeWithFilter(topicPath, filter)
 		if err != nil {
 			http.Error(resp, "cannot conne", http.StatusInternalServerError)
 			return
@@ -271,7 +297,16 @@ func (rp *ReverseProxy) reverseProxyHandler(u *url.URL) (proxy http.Handler, pre
 			u.Host = uu.Host
 
 		case "http", "https":
-			reversep.Transport = &http.Transport{}
+			transport := &http.Transport{}
+			if uu.Scheme == "https" {
+				config, err := rpc_client.TLSConfig(uu.Host)
+				if err != nil {
+					log.Error("cannot configure tls", "socketPath", socketPath, "err", err)
+					return
+				}
+				transport.TLSClientConfig = config
+			}
+			reversep.Transport = transport
 			u.Scheme = uu.Scheme
 			u.Host = uu.Host
 
@@ -1,6 +1,7 @@
 package mux // import "github.com/docker/infrakit/pkg/rpc/mux"
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -12,11 +13,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/go-connections/tlsconfig"
+	broker "github.com/docker/infrakit/pkg/broker/server"
 	"github.com/docker/infrakit/pkg/discovery"
 	"github.com/docker/infrakit/pkg/discovery/local"
 	"github.com/docker/infrakit/pkg/leader"
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/rpc"
+	"github.com/docker/infrakit/pkg/rpc/auth"
 	rpc_server "github.com/docker/infrakit/pkg/rpc/server"
 	"gopkg.in/tylerb/graceful.v1"
 )
@@ -25,6 +29,16 @@ import (
 type Options struct {
 	Leadership <-chan leader.Leadership
 	Registry   leader.Store
+
+	// TLS is the optional TLS configuration.  If set, the mux serves https and, if the
+	// CAFile is set, requires clients to present a certificate signed by the CA.
+	TLS *tlsconfig.Options
+
+	// Policy is the optional authorization policy enforced on the rpc calls proxied by the mux.
+	// Because the mux does not know the types of the plugins behind it, the rpc service name
+	// (e.g. Instance, Group, Manager) is matched as the interface name.  The calls denied are
+	// published, as by the plugins, on the topic auth/denied of the events of the mux at /events.
+	Policy *auth.Policy
 }
 
 // SavePID makes sure the directory exists and writes the pid to a file
@@ -55,11 +69,18 @@ func NewServer(listen string, advertiseHostPort string,
 	plugins func() discovery.Plugins, options Options) (rpc_server.Stoppable, error) {
 
 	advertise := &url.URL{Host: advertiseHostPort, Scheme: "http"}
+	if options.TLS != nil {
+		advertise.Scheme = "https"
+	}
 
 	proxy := NewReverseProxy(plugins)
+	events := broker.NewBroker()
+	handler := http.NewServeMux()
+	handler.Handle(rpc.URLEventsPrefix, events)
+	handler.Handle("/", auth.Handler(options.Policy, nil, rpc_server.PublishDenials(events), proxy))
 	server := &graceful.Server{
 		Timeout: 10 * time.Second,
-		Server:  &http.Server{Addr: listen, Handler: proxy},
+		Server:  &http.Server{Addr: listen, Handler: handler},
 	}
 
 	var advertiseURL *url.URL
@@ -111,21 +132,37 @@ func NewServer(listen string, advertiseHostPort string,
 
 	pidPath, err := SavePID(listen)
 	if err != nil {
+		events.Stop()
 		return nil, err
 	}
 
 	listener, err := net.Listen("tcp", listen)
 	if err != nil {
+		events.Stop()
 		return nil, err
 	}
 
-	log.Info("Listening", "listen", listen)
+	if options.TLS != nil {
+		config, err := rpc.ServerTLSConfig(*options.TLS)
+		if err != nil {
+			listener.Close()
+			events.Stop()
+			return nil, err
+		}
+		server.Server.TLSConfig = config
+		listener = tls.NewListener(listener, config)
+	}
+
+	log.Info("Listening", "listen", listen, "tls", options.TLS != nil)
 
+	stopped := make(chan struct{})
 	go func() {
 		defer func() {
 			close(leaderStop)
+			events.Stop()
 			log.Info("listener stopped")
 			os.Remove(pidPath)
+			close(stopped)
 		}()
 
 		err := server.Serve(listener)
@@ -134,11 +171,12 @@ func NewServer(listen string, advertiseHostPort string,
 			return
 		}
 	}()
-	return &stoppableServer{server: server}, nil
+	return &stoppableServer{server: server, stopped: stopped}, nil
 }
 
 type stoppableServer struct {
-	server *graceful.Server
+	server  *graceful.Server
+	stopped chan struct{} // closed when the pid file is removed
 }
 
 func (s *stoppableServer) Stop() {
@@ -146,11 +184,11 @@ func (s *stoppableServer) Stop() {
 }
 
 func (s *stoppableServer) AwaitStopped() {
-	<-s.server.StopChan()
+	<-s.stopped
 }
 
 func (s *stoppableServer) Wait() <-chan struct{} {
-	return s.server.StopChan()
+	return s.stopped
 }
 
 type loggingHandler struct {
@@ -1,17 +1,24 @@
 package mux // import "github.com/docker/infrakit/pkg/rpc/mux"
 
 import (
+	"bytes"
 	"io/ioutil"
 	"net/http"
 	"path/filepath"
 	"testing"
+	"time"
 
+	broker "github.com/docker/infrakit/pkg/broker/client"
 	"github.com/docker/infrakit/pkg/discovery"
 	"github.com/docker/infrakit/pkg/discovery/local"
 	"github.com/docker/infrakit/pkg/rpc"
+	"github.com/docker/infrakit/pkg/rpc/auth"
 	"github.com/docker/infrakit/pkg/rpc/client"
 	rpc_metadata "github.com/docker/infrakit/pkg/rpc/metadata"
+	rpc_server "github.com/docker/infrakit/pkg/rpc/server"
+	"github.com/docker/infrakit/pkg/spi/event"
 	"github.com/docker/infrakit/pkg/types"
+	"github.com/gorilla/rpc/v2/json2"
 	"github.com/stretchr/testify/require"
 
 	. "github.com/docker/infrakit/pkg/testing"
@@ -45,12 +52,12 @@ func TestMuxServer(t *testing.T) {
 	T(100).Infoln("info=", info)
 
 	T(100).Infoln("Starting mux server")
-	server, err = NewServer(":9090", "127.0.0.1:9090", func() discovery.Plugins {
+	mux, err := NewServer(":9090", "127.0.0.1:9090", func() discovery.Plugins {
 		return lookup
 	}, Options{})
 	require.NoError(t, err)
 
-	defer server.Stop()
+	defer stopServer(mux)
 
 	get := "http://localhost:9090/" + pluginName + rpc.URLAPI
 
@@ -70,3 +77,56 @@ func TestMuxServer(t *testing.T) {
 	require.Equal(t, "Metadata", m["Implements"].([]interface{})[0].(map[string]interface{})["Name"])
 	T(100).Infoln("body=", string(body))
 }
+
+// stopServer stops the server and waits for it to remove its pid file, so the next run can listen again
+func stopServer(server rpc_server.Stoppable) {
+	server.Stop()
+	server.AwaitStopped()
+}
+
+func TestMuxServerPublishesDenials(t *testing.T) {
+
+	pluginName := "metadata"
+	socketPath, server := startPlugin(t, pluginName)
+	defer server.Stop()
+
+	lookup, err := local.NewPluginDiscoveryWithDir(filepath.Dir(socketPath))
+	require.NoError(t, err)
+
+	mux, err := NewServer(":9091", "127.0.0.1:9091", func() discovery.Plugins {
+		return lookup
+	}, Options{Policy: &auth.Policy{}})
+	require.NoError(t, err)
+	defer stopServer(mux)
+
+	denials, _, done, err := broker.Subscribe("http://localhost:9091", rpc_server.TopicDenied,
+		broker.Options{Path: rpc.URLEventsPrefix})
+	require.NoError(t, err)
+	defer close(done) // before the server is stopped so it doesn't wait for the subscription
+
+	call := func() int {
+		body, err := json2.EncodeClientRequest("Metadata.Keys", rpc_metadata.KeysRequest{})
+		require.NoError(t, err)
+		resp, err := http.Post("http://localhost:9091/"+pluginName+"/", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// the call is retried until the subscription is connected
+	deadline := time.After(5 * time.Second)
+	for {
+		require.Equal(t, http.StatusForbidden, call())
+		select {
+		case any := <-denials:
+			e := event.Event{}
+			require.NoError(t, any.Decode(&e))
+			require.Equal(t, rpc_server.EventTypeDenied, e.Type)
+			require.Equal(t, "Metadata.Keys", e.Message)
+			return
+		case <-deadline:
+			require.Fail(t, "no denial published")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
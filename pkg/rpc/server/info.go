@@ -121,6 +121,7 @@ func (m *PluginInfo) getInfo() *plugin.Info {
 			plugin.InterfaceDescription{
 				InterfaceSpec: iface,
 				Methods:       descriptions,
+				Schema:        r.schema(),
 			})
 	}
 
@@ -6,12 +6,15 @@ import (
 	"path"
 	"testing"
 
+	metadata_plugin "github.com/docker/infrakit/pkg/plugin/metadata"
 	rpc_flavor "github.com/docker/infrakit/pkg/rpc/flavor"
 	rpc_instance "github.com/docker/infrakit/pkg/rpc/instance"
+	rpc_metadata "github.com/docker/infrakit/pkg/rpc/metadata"
 	"github.com/docker/infrakit/pkg/spi/flavor"
 	"github.com/docker/infrakit/pkg/template"
 	testing_flavor "github.com/docker/infrakit/pkg/testing/flavor"
 	testing_instance "github.com/docker/infrakit/pkg/testing/instance"
+	"github.com/docker/infrakit/pkg/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -59,6 +62,36 @@ func TestFetchAPIInfoFromPlugin(t *testing.T) {
 	server.Stop()
 }
 
+func TestFetchAPISchemaFromPlugin(t *testing.T) {
+	socketPath := tempSocket()
+
+	url := "unix://" + socketPath
+
+	schema := `{"type":"object","properties":{"size":{"type":"integer","maximum":10}}}`
+	server, err := StartPluginAtPath(socketPath, rpc_metadata.UpdatableServer(
+		metadata_plugin.NewUpdatablePluginWithSchema(
+			metadata_plugin.NewPluginFromData(map[string]interface{}{"size": 1}),
+			func(proposed *types.Any) error { return nil },
+			types.AnyString(schema))))
+	require.NoError(t, err)
+	defer server.Stop()
+
+	buff, err := template.Fetch(url, template.Options{
+		CustomizeFetch: func(req *http.Request) {
+			req.URL.Path = "/info/api.json"
+			req.URL.Host = "h"
+		},
+	})
+	require.NoError(t, err)
+
+	decoded, err := template.FromJSON(buff)
+	require.NoError(t, err)
+
+	result, err := template.QueryObject("Interfaces[0].Schema.properties.size.maximum", decoded)
+	require.NoError(t, err)
+	require.Equal(t, float64(10), result)
+}
+
 type exporter struct {
 	flavor.Plugin
 }
@@ -0,0 +1,31 @@
+package server // import "github.com/docker/infrakit/pkg/rpc/server"
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/docker/infrakit/pkg/metrics"
+	"github.com/docker/infrakit/pkg/rpc/audit"
+	"github.com/docker/infrakit/pkg/rpc/auth"
+)
+
+// metricsHandler counts the json-rpc calls served by the next handler and observes their latencies and errors.
+func metricsHandler(plugin string, resolve auth.Resolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		iface, method, ok := parseCall(req, resolve)
+		if !ok {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		start := time.Now()
+		recorder := record(next, req)
+
+		metrics.RPCDuration.WithLabelValues(plugin, iface, method).Observe(time.Since(start).Seconds())
+		metrics.RPCCalls.WithLabelValues(plugin, iface, method).Inc()
+		if audit.CallError(recorder) != "" {
+			metrics.RPCErrors.WithLabelValues(plugin, iface, method).Inc()
+		}
+		replay(recorder, resp)
+	})
+}
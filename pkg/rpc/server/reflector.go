@@ -71,6 +71,13 @@ func (r *reflector) getPluginTypeName() string {
 	return r.targetType().Name()
 }
 
+func (r *reflector) schema() *types.Any {
+	if s, is := r.target.(rpc.Schema); is {
+		return s.Schema()
+	}
+	return nil
+}
+
 func (r *reflector) setExampleProperties(param interface{}) {
 	if example, is := r.target.(rpc.InputExample); is {
 		example.SetExampleProperties(param)
@@ -1,18 +1,27 @@
 package server // import "github.com/docker/infrakit/pkg/rpc/server"
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"time"
 
+	"github.com/docker/go-connections/tlsconfig"
 	broker "github.com/docker/infrakit/pkg/broker/server"
 	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/metrics"
 	rpc_base "github.com/docker/infrakit/pkg/rpc"
 	rpc_server "github.com/docker/infrakit/pkg/rpc"
+	"github.com/docker/infrakit/pkg/rpc/audit"
+	"github.com/docker/infrakit/pkg/rpc/auth"
+	rpc_event "github.com/docker/infrakit/pkg/rpc/event"
 	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/spi/event"
 	"github.com/docker/infrakit/pkg/types"
@@ -22,6 +31,20 @@ import (
 	"gopkg.in/tylerb/graceful.v1"
 )
 
+const (
+	// TopicDenied is the topic of the events published when calls are denied by the authorization policy
+	TopicDenied = "auth/denied"
+
+	// EventTypeDenied is the type of the events published when calls are denied
+	EventTypeDenied = event.Type("Denied")
+
+	// TopicAudit is the topic of the events published for the audit records of mutating calls
+	TopicAudit = "audit"
+
+	// EventTypeAudit is the type of the events published for the audit records
+	EventTypeAudit = event.Type("Audit")
+)
+
 var (
 	log    = logutil.New("module", "rpc/server")
 	debugV = logutil.V(1000)
@@ -34,6 +57,18 @@ type Stoppable interface {
 	Wait() <-chan struct{}
 }
 
+// PublishDenials returns the callback of the authorization handler that publishes the calls denied
+// to the broker on the topic TopicDenied.
+func PublishDenials(events *broker.Broker) func(auth.Denial) {
+	return func(denial auth.Denial) {
+		events.Publish(TopicDenied, event.Event{
+			Type:    EventTypeDenied,
+			ID:      denial.Identity.String(),
+			Message: fmt.Sprintf("%s.%s", denial.Interface.Name, denial.Method),
+		}.Init().WithTopic(TopicDenied).WithDataMust(denial), 1*time.Second)
+	}
+}
+
 type stoppableServer struct {
 	server *graceful.Server
 }
@@ -90,20 +125,63 @@ type VersionedInterface interface {
 	Objects() []rpc_base.Object
 }
 
+// Options are the options for a tcp listener
+type Options struct {
+
+	// TLS is the optional TLS configuration. If the CAFile is set, clients must present a certificate
+	// signed by the CA.
+	TLS *tlsconfig.Options
+
+	// Policy is the optional authorization policy enforced on the rpc methods.
+	Policy *auth.Policy
+
+	// Audit is the optional sink of the audit records of mutating calls.
+	Audit audit.Sink
+
+	// Events are the options of the retention of the events published, for the clients that resume
+	// their streams.  Events are kept in memory by default.
+	Events broker.Options
+}
+
 // StartListenerAtPath starts an HTTP server listening on tcp port with discovery entry at specified path.
 // Returns a Stoppable that can be used to stop or block on the server.
 func StartListenerAtPath(listen []string, discoverPath string,
 	receiver VersionedInterface, more ...VersionedInterface) (Stoppable, error) {
-	return startAtPath(listen, discoverPath, receiver, more...)
+	return startAtPath(listen, discoverPath, Options{}, receiver, more...)
+}
+
+// StartTLSListenerAtPath starts an HTTPS server listening on tcp port with discovery entry at specified path.
+// If the options include a CA file, clients are required to present a certificate signed by the CA.
+// Returns a Stoppable that can be used to stop or block on the server.
+func StartTLSListenerAtPath(listen []string, discoverPath string, options tlsconfig.Options,
+	receiver VersionedInterface, more ...VersionedInterface) (Stoppable, error) {
+	return startAtPath(listen, discoverPath, Options{TLS: &options}, receiver, more...)
+}
+
+// StartListenerAtPathWithOptions starts a server listening on tcp port with discovery entry at specified path,
+// with the given TLS and authorization options.
+// Returns a Stoppable that can be used to stop or block on the server.
+func StartListenerAtPathWithOptions(listen []string, discoverPath string, options Options,
+	receiver VersionedInterface, more ...VersionedInterface) (Stoppable, error) {
+	return startAtPath(listen, discoverPath, options, receiver, more...)
 }
 
 // StartPluginAtPath starts an HTTP server listening on a unix socket at the specified path.
 // Returns a Stoppable that can be used to stop or block on the server.
 func StartPluginAtPath(socketPath string, receiver VersionedInterface, more ...VersionedInterface) (Stoppable, error) {
-	return startAtPath(nil, socketPath, receiver, more...)
+	return startAtPath(nil, socketPath, Options{}, receiver, more...)
 }
 
-func startAtPath(listen []string, discoverPath string,
+// StartPluginAtPathWithOptions starts an HTTP server listening on a unix socket at the specified path,
+// with the given options.  TLS options do not apply to unix sockets and are ignored.
+// Returns a Stoppable that can be used to stop or block on the server.
+func StartPluginAtPathWithOptions(socketPath string, options Options,
+	receiver VersionedInterface, more ...VersionedInterface) (Stoppable, error) {
+	options.TLS = nil
+	return startAtPath(nil, socketPath, options, receiver, more...)
+}
+
+func startAtPath(listen []string, discoverPath string, options Options,
 	receiver VersionedInterface, more ...VersionedInterface) (Stoppable, error) {
 
 	df, err := os.Stat(discoverPath)
@@ -119,9 +197,12 @@ func startAtPath(listen []string, discoverPath string,
 	targets := append([]VersionedInterface{receiver}, more...)
 
 	objects := map[spi.InterfaceSpec]func() []rpc_base.Object{}
+	services := map[string]spi.InterfaceSpec{}
 	for _, t := range targets {
 
 		objects[t.ImplementedInterface()] = t.Objects
+		// the service name is the name of the receiver type -- see rpc.Server.RegisterService
+		services[reflect.Indirect(reflect.ValueOf(t)).Type().Name()] = t.ImplementedInterface()
 
 		if err := server.RegisterService(t, ""); err != nil {
 			return nil, err
@@ -149,7 +230,13 @@ func startAtPath(listen []string, discoverPath string,
 	stops := []chan struct{}{}
 
 	// events handler
-	events := broker.NewBroker()
+	if options.Events.Filters == nil {
+		options.Events.Filters = rpc_event.Filters
+	}
+	events, err := broker.NewBrokerWithOptions(options.Events)
+	if err != nil {
+		return nil, err
+	}
 
 	// wire up the publish event source channel to the plugin implementations
 	for _, t := range targets {
@@ -196,6 +283,7 @@ func startAtPath(listen []string, discoverPath string,
 	router := mux.NewRouter()
 	router.HandleFunc(rpc_server.URLAPI, info.ShowAPI)
 	router.HandleFunc(rpc_server.URLFunctions, info.ShowTemplateFunctions)
+	router.Handle(metrics.URL, metrics.Handler())
 
 	// Disable this so that clients can connect/subscribe to streams before the topics
 	// actually become available (dynamically added topics)
@@ -224,8 +312,29 @@ func startAtPath(listen []string, discoverPath string,
 	}
 	router.HandleFunc(rpc_server.URLEventsPrefix, intercept.ServeHTTP)
 
-	logger := loggingHandler{handler: server, listen: listen, discoverPath: discoverPath}
-	router.Handle("/", logger)
+	resolve := func(service string) (spi.InterfaceSpec, bool) {
+		spec, has := services[service]
+		return spec, has
+	}
+
+	pluginName := strings.TrimSuffix(filepath.Base(discoverPath), filepath.Ext(discoverPath))
+
+	var handler http.Handler = loggingHandler{handler: server, listen: listen, discoverPath: discoverPath}
+	handler = auth.CallerHandler(handler)
+	handler = metricsHandler(pluginName, resolve, handler)
+	if options.Audit != nil {
+		handler = audit.Handler(audit.SinkFunc(func(record audit.Record) error {
+			events.Publish(TopicAudit, event.Event{
+				Type:    EventTypeAudit,
+				ID:      record.ID,
+				Message: fmt.Sprintf("%s.%s", record.Interface.Name, record.Method),
+			}.Init().WithTopic(TopicAudit).WithDataMust(record), 1*time.Second)
+			return options.Audit.Record(record)
+		}), pluginName, resolve, handler)
+	}
+
+	router.Handle("/", traceHandler(pluginName, resolve, auth.Handler(options.Policy, resolve,
+		PublishDenials(events), handler)))
 
 	gracefulServer := graceful.Server{
 		Timeout: 10 * time.Second,
@@ -248,15 +357,29 @@ func startAtPath(listen []string, discoverPath string,
 		}
 		listener = l
 
+		scheme := "tcp"
+		if options.TLS != nil {
+			config, err := rpc_server.ServerTLSConfig(*options.TLS)
+			if err != nil {
+				l.Close()
+				log.Error("error configuring tls", "err", err)
+				return nil, err
+			}
+			gracefulServer.Server.TLSConfig = config
+			listener = tls.NewListener(l, config)
+			scheme = "https"
+		}
+
 		advertise := listen[0]
 		if len(listen) > 1 {
 			advertise = listen[1]
 		}
-		if err := ioutil.WriteFile(discoverPath, []byte(fmt.Sprintf("tcp://%s", advertise)), 0644); err != nil {
+		if err := ioutil.WriteFile(discoverPath, []byte(fmt.Sprintf("%s://%s", scheme, advertise)), 0644); err != nil {
 			return nil, err
 		}
 
-		log.Info("Listening", "listen", listen, "discover", discoverPath)
+		log.Info("Listening", "listen", listen, "discover", discoverPath, "tls", options.TLS != nil,
+			"policy", options.Policy != nil)
 
 	} else {
 
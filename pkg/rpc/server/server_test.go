@@ -1,17 +1,37 @@
 package server // import "github.com/docker/infrakit/pkg/rpc/server"
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/docker/infrakit/pkg/metrics"
 	plugin_mock "github.com/docker/infrakit/pkg/mock/spi/instance"
 	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/rpc/audit"
+	"github.com/docker/infrakit/pkg/rpc/auth"
+	rpc_client "github.com/docker/infrakit/pkg/rpc/client"
 	plugin_rpc "github.com/docker/infrakit/pkg/rpc/instance"
+	"github.com/docker/infrakit/pkg/rpc/trace"
+	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/spi/instance"
+	"github.com/docker/infrakit/pkg/store/mem"
 	"github.com/docker/infrakit/pkg/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -102,3 +122,275 @@ func TestTCPServer(t *testing.T) {
 
 	server.Stop()
 }
+
+// writeCert signs a certificate with the parent (or self-signs if parent is nil) and writes
+// the PEM encoded cert and key to dir.
+func writeCert(t *testing.T, dir, name string, template *x509.Certificate,
+	parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+".pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name+"-key.pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+	return cert, key
+}
+
+func TestTLSServerRequiresClientCert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "infrakit-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	notAfter := time.Now().Add(1 * time.Hour)
+	ca, caKey := writeCert(t, dir, "ca", &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}, nil, nil)
+	writeCert(t, dir, "server", &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     notAfter,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+	writeCert(t, dir, "client", &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	mock := plugin_mock.NewMockPlugin(ctrl)
+	instanceID := instance.ID("id")
+	spec := instance.Spec{Init: "init"}
+	mock.EXPECT().Provision(spec).Return(&instanceID, nil)
+
+	service := plugin_rpc.PluginServer(mock)
+
+	discover := filepath.Join(dir, "tls.listen")
+	server, err := StartTLSListenerAtPath([]string{"localhost:7779"}, discover,
+		tlsconfig.Options{
+			CAFile:   filepath.Join(dir, "ca.pem"),
+			CertFile: filepath.Join(dir, "server.pem"),
+			KeyFile:  filepath.Join(dir, "server-key.pem"),
+		}, service)
+	require.NoError(t, err)
+	defer server.Stop()
+
+	buff, err := ioutil.ReadFile(discover)
+	require.NoError(t, err)
+	require.Equal(t, "https://localhost:7779", string(buff))
+
+	// Without a client certificate the handshake fails
+	rpc_client.RegisterTLS("localhost:7779", tlsconfig.Options{
+		CAFile: filepath.Join(dir, "ca.pem"),
+	})
+	_, err = plugin_rpc.NewClient(plugin.Name("tls"), "https://localhost:7779")
+	require.Error(t, err)
+
+	// With the client certificate the call succeeds.  Note the http clients are cached
+	// by address so a different address string is used here.
+	rpc_client.RegisterTLS("localhost:7779", tlsconfig.Options{
+		CAFile:   filepath.Join(dir, "ca.pem"),
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client-key.pem"),
+	})
+	c, err := plugin_rpc.NewClient(plugin.Name("tls"), "https://localhost:7779/")
+	require.NoError(t, err)
+	id, err := c.Provision(spec)
+	require.NoError(t, err)
+	require.Equal(t, instanceID, *id)
+}
+
+func TestTCPServerWithPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := plugin_mock.NewMockPlugin(ctrl)
+	mock.EXPECT().DescribeInstances(map[string]string{"a": "b"}, false).Return([]instance.Description{}, nil)
+
+	service := plugin_rpc.PluginServer(mock)
+
+	policy := &auth.Policy{
+		Roles: map[string][]auth.Rule{
+			"readonly": {
+				{Interface: instance.InterfaceSpec.Name, Methods: []string{"DescribeInstances"}},
+			},
+		},
+		Bindings: []auth.Binding{
+			{Token: "s3cr3t", Roles: []string{"readonly"}},
+		},
+	}
+
+	discover := filepath.Join(os.TempDir(), fmt.Sprintf("%d-policy.listen", time.Now().Unix()))
+	server, err := StartListenerAtPathWithOptions([]string{"localhost:7780"}, discover,
+		Options{Policy: policy}, service)
+	require.NoError(t, err)
+	defer server.Stop()
+
+	rpc_client.RegisterToken("localhost:7780", "s3cr3t")
+
+	c, err := plugin_rpc.NewClient(plugin.Name("policy"), discover)
+	require.NoError(t, err)
+
+	_, err = c.DescribeInstances(map[string]string{"a": "b"}, false)
+	require.NoError(t, err)
+
+	err = c.Destroy(instance.ID("id"), instance.Termination)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "permission denied")
+}
+
+func TestUnixSocketServerWithAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := plugin_mock.NewMockPlugin(ctrl)
+	instanceID := instance.ID("id")
+	spec := instance.Spec{Init: "init"}
+	gomock.InOrder(
+		mock.EXPECT().DescribeInstances(map[string]string{}, false).Return([]instance.Description{}, nil),
+		mock.EXPECT().Provision(spec).Return(&instanceID, nil),
+	)
+
+	kv := mem.NewStore("audit")
+	socket := filepath.Join(os.TempDir(), fmt.Sprintf("%d-audit", time.Now().Unix()))
+	server, err := StartPluginAtPathWithOptions(socket, Options{Audit: audit.KVSink(kv)},
+		plugin_rpc.PluginServer(mock))
+	require.NoError(t, err)
+	defer server.Stop()
+
+	c, err := plugin_rpc.NewClient(plugin.Name(filepath.Base(socket)), socket)
+	require.NoError(t, err)
+
+	_, err = c.DescribeInstances(map[string]string{}, false)
+	require.NoError(t, err)
+	_, err = c.Provision(spec)
+	require.NoError(t, err)
+
+	records, err := audit.List(kv)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(records))
+	require.Equal(t, instance.InterfaceSpec, records[0].Interface)
+	require.Equal(t, "Provision", records[0].Method)
+	require.Equal(t, filepath.Base(socket), records[0].Plugin)
+	require.True(t, records[0].Succeeded())
+}
+
+func TestTCPServerMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := plugin_mock.NewMockPlugin(ctrl)
+	properties := types.AnyString(`{"foo":"bar"}`)
+	gomock.InOrder(
+		mock.EXPECT().Validate(properties).Return(nil),
+		mock.EXPECT().Validate(properties).Return(errors.New("validate-error")),
+	)
+
+	discover := filepath.Join(os.TempDir(), fmt.Sprintf("%d-metrics.listen", time.Now().Unix()))
+	server, err := StartListenerAtPath([]string{"localhost:7781"}, discover, plugin_rpc.PluginServer(mock))
+	require.NoError(t, err)
+	defer server.Stop()
+
+	c, err := plugin_rpc.NewClient(plugin.Name(filepath.Base(discover)), discover)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Validate(properties))
+	require.Error(t, c.Validate(properties))
+
+	resp, err := http.Get("http://localhost:7781" + metrics.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	pluginName := strings.TrimSuffix(filepath.Base(discover), ".listen")
+	labels := fmt.Sprintf(`{interface="Instance",method="Validate",plugin="%s"}`, pluginName)
+	require.Contains(t, string(body), "infrakit_rpc_calls_total"+labels+" 2")
+	require.Contains(t, string(body), "infrakit_rpc_errors_total"+labels+" 1")
+	require.Contains(t, string(body), "infrakit_rpc_duration_seconds_count"+labels+" 2")
+}
+
+type spans struct {
+	list []*trace.Span
+	lock sync.Mutex
+}
+
+func (s *spans) Export(span *trace.Span) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.list = append(s.list, span)
+	return nil
+}
+
+func TestUnixSocketServerTracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := plugin_mock.NewMockPlugin(ctrl)
+	instanceID := instance.ID("id")
+	spec := instance.Spec{Init: "init"}
+	mock.EXPECT().Provision(spec).Return(&instanceID, nil)
+
+	exported := &spans{}
+	trace.SetExporter(exported)
+	defer trace.SetExporter(nil)
+
+	socket := filepath.Join(os.TempDir(), fmt.Sprintf("%d-trace", time.Now().Unix()))
+	server, err := StartPluginAtPath(socket, plugin_rpc.PluginServer(mock))
+	require.NoError(t, err)
+	defer server.Stop()
+
+	c, err := plugin_rpc.NewClient(plugin.Name(filepath.Base(socket)), socket)
+	require.NoError(t, err)
+
+	root := trace.Start("cli", "provision", trace.KindClient, nil)
+	_, err = spi.WithContext(c, trace.NewContext(context.Background(), root)).(instance.Plugin).Provision(spec)
+	require.NoError(t, err)
+
+	exported.lock.Lock()
+	defer exported.lock.Unlock()
+
+	var client, served *trace.Span
+	for _, s := range exported.list {
+		if s.Name != "Instance.Provision" {
+			continue
+		}
+		switch s.Kind {
+		case trace.KindClient:
+			client = s
+		case trace.KindServer:
+			served = s
+		}
+	}
+	require.NotNil(t, client)
+	require.NotNil(t, served)
+	require.Equal(t, root.TraceID, client.TraceID)
+	require.Equal(t, root.ID, client.ParentID)
+	require.Equal(t, root.TraceID, served.TraceID)
+	require.Equal(t, client.ID, served.ParentID)
+	require.Equal(t, filepath.Base(socket), served.Service())
+}
@@ -0,0 +1,90 @@
+package server // import "github.com/docker/infrakit/pkg/rpc/server"
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/docker/infrakit/pkg/rpc"
+	"github.com/docker/infrakit/pkg/rpc/audit"
+	"github.com/docker/infrakit/pkg/rpc/auth"
+	"github.com/docker/infrakit/pkg/rpc/trace"
+)
+
+// parseCall returns the interface and method of the json-rpc call in the request.  The body of the
+// request is restored so it can be read again.  It returns false if the request is not a json-rpc call.
+func parseCall(req *http.Request, resolve auth.Resolver) (iface, method string, ok bool) {
+	if req.Method != http.MethodPost {
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	call := struct {
+		Method string `json:"method"`
+	}{}
+	if err := json.Unmarshal(body, &call); err != nil || call.Method == "" {
+		return
+	}
+
+	iface = call.Method
+	if i := strings.LastIndex(call.Method, "."); i > 0 {
+		iface, method = call.Method[:i], call.Method[i+1:]
+	}
+	if spec, has := resolve(iface); has {
+		iface = spec.Name
+	}
+	return iface, method, true
+}
+
+// record serves the request with the next handler and returns the recorded response
+func record(next http.Handler, req *http.Request) *rpc.ResponseRecorder {
+	recorder := rpc.NewRecorder()
+	next.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// replay writes out the recorded response
+func replay(recorder *rpc.ResponseRecorder, resp http.ResponseWriter) {
+	for k, v := range recorder.HeaderMap {
+		resp.Header()[k] = v
+	}
+	resp.WriteHeader(recorder.Code)
+	recorder.Body.WriteTo(resp)
+}
+
+// traceHandler records a server span for each json-rpc call, continuing the trace of the caller if any.
+// The span is added to the context of the request so that the calls the plugin makes with the context
+// are part of the trace.
+func traceHandler(plugin string, resolve auth.Resolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		parent := trace.Extract(req.Header)
+		if parent == nil && !trace.Enabled() {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		iface, method, ok := parseCall(req, resolve)
+		if !ok {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		span := trace.Start(plugin, iface+"."+method, trace.KindServer, parent)
+		span.SetTag("peer.address", req.RemoteAddr)
+		recorder := record(next, req.WithContext(trace.NewContext(req.Context(), span)))
+
+		var err error
+		if message := audit.CallError(recorder); message != "" {
+			err = errors.New(message)
+		}
+		span.Finish(err)
+		replay(recorder, resp)
+	})
+}
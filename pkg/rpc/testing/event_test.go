@@ -266,3 +266,82 @@ loop:
 	require.Equal(t, events, len(storageEvents))
 	require.Equal(t, len(allEvents), len(computeEvents)+len(storageEvents))
 }
+
+func TestEventPluginFilteredSubscribe(t *testing.T) {
+	socketPath := tempSocket()
+
+	published := make(chan chan<- *event.Event, 1)
+	plugin := &testing_event.Plugin{
+		DoList: func(topic types.Path) ([]string, error) {
+			return []string{"instance"}, nil
+		},
+		Publisher: &testing_event.Publisher{
+			DoPublishOn: func(c chan<- *event.Event) {
+				published <- c
+			},
+		},
+	}
+
+	server, err := rpc_server.StartPluginAtPath(socketPath, rpc_event.PluginServerWithNames(
+		func() (map[string]event.Plugin, error) {
+			return map[string]event.Plugin{
+				"compute": plugin,
+			}, nil
+		}))
+	require.NoError(t, err)
+	defer server.Stop()
+
+	publish := <-published
+
+	client := must(rpc_event.NewClient(socketPath)).(event.FilteredSubscriber)
+
+	large, doneLarge, err := client.SubscribeWithFilter(types.PathFromString("compute/"), event.Filter{
+		Types:      []event.Type{"Create"},
+		Expression: "{{ ge .Data.Size 4.0 }}",
+	})
+	require.NoError(t, err)
+	defer close(doneLarge)
+
+	five, doneFive, err := client.SubscribeWithFilter(types.PathFromString("compute/"), event.Filter{
+		Data: map[string]interface{}{"/Size": 5},
+	})
+	require.NoError(t, err)
+	defer close(doneFive)
+
+	<-time.After(100 * time.Millisecond) // the subscriptions start from now
+
+	for i := 0; i < 10; i++ {
+		eventType := event.Type("Create")
+		if i%2 == 1 {
+			eventType = event.Type("Delete")
+		}
+		publish <- event.Event{
+			Topic: types.PathFromString("instance/create"),
+			Type:  eventType,
+			ID:    fmt.Sprintf("host-%d", i),
+		}.Init().WithDataMust(map[string]int{"Size": i})
+	}
+
+	ids := []string{}
+	for len(ids) < 3 {
+		select {
+		case e := <-large:
+			ids = append(ids, e.ID)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout")
+		}
+	}
+	require.Equal(t, []string{"host-4", "host-6", "host-8"}, ids)
+
+	e := <-five
+	require.Equal(t, "host-5", e.ID)
+
+	// nothing else selected
+	select {
+	case e := <-large:
+		require.Fail(t, "unexpected", e)
+	case e := <-five:
+		require.Fail(t, "unexpected", e)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,24 @@
+package rpc // import "github.com/docker/infrakit/pkg/rpc"
+
+import (
+	"crypto/tls"
+
+	"github.com/docker/go-connections/tlsconfig"
+)
+
+// ServerTLSConfig returns the tls config for a server listening on tcp.  If a CA file is given
+// and no client auth type is specified, the server will require and verify client certificates
+// signed by that CA (mutual TLS).
+func ServerTLSConfig(options tlsconfig.Options) (*tls.Config, error) {
+	if options.CAFile != "" && options.ClientAuth == tls.NoClientCert {
+		options.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsconfig.Server(options)
+}
+
+// ClientTLSConfig returns the tls config for a client connecting to a server over https.
+// The CA file is used to verify the server, and the cert / key pair, if provided, is
+// presented to the server for client authentication.
+func ClientTLSConfig(options tlsconfig.Options) (*tls.Config, error) {
+	return tlsconfig.Client(options)
+}
@@ -0,0 +1,31 @@
+package trace // import "github.com/docker/infrakit/pkg/rpc/trace"
+
+import (
+	"context"
+)
+
+// The SPI methods do not take a context, so the span of the call being served is carried in the
+// context of the request and handed explicitly to the plugins that want it (see spi.Contextual).
+// Clients given the context continue the trace.
+
+type contextKey int
+
+const spanKey contextKey = 0
+
+// NewContext returns a copy of the context that carries the span.  The context is returned as
+// is if the span is nil.
+func NewContext(ctx context.Context, s *Span) context.Context {
+	if s == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey, s)
+}
+
+// FromContext returns the span carried by the context, or nil
+func FromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(spanKey).(*Span)
+	return s
+}
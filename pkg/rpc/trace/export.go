@@ -0,0 +1,190 @@
+package trace // import "github.com/docker/infrakit/pkg/rpc/trace"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+type fileExporter struct {
+	file *os.File
+	lock sync.Mutex
+}
+
+// FileExporter returns an exporter that appends the spans to the file, one JSON object per line
+func FileExporter(path string) (Exporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileExporter{file: f}, nil
+}
+
+// Export implements Exporter
+func (e *fileExporter) Export(s *Span) error {
+	buff, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	_, err = e.file.Write(append(buff, '\n'))
+	return err
+}
+
+const (
+	collectorBatchSize     = 100
+	collectorFlushInterval = 1 * time.Second
+)
+
+type collectorExporter struct {
+	url    string
+	client *http.Client
+	spans  chan *Span
+	flush  chan chan struct{}
+}
+
+// CollectorExporter returns an exporter that posts the spans in batches to a collector that accepts
+// the Zipkin v2 JSON format, e.g. http://localhost:9411/api/v2/spans for Zipkin or for Jaeger with the
+// Zipkin collector enabled.  Spans are dropped if the collector cannot keep up.
+func CollectorExporter(url string) Exporter {
+	e := &collectorExporter{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		spans:  make(chan *Span, 10*collectorBatchSize),
+		flush:  make(chan chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// Export implements Exporter
+func (e *collectorExporter) Export(s *Span) error {
+	select {
+	case e.spans <- s:
+		return nil
+	default:
+		return fmt.Errorf("collector queue full")
+	}
+}
+
+// Flush posts the queued spans and blocks until done
+func (e *collectorExporter) Flush() {
+	done := make(chan struct{})
+	e.flush <- done
+	<-done
+}
+
+func (e *collectorExporter) run() {
+	batch := []*Span{}
+	ticker := time.NewTicker(collectorFlushInterval)
+	defer ticker.Stop()
+	for {
+		var done chan struct{}
+		select {
+		case s := <-e.spans:
+			batch = append(batch, s)
+			if len(batch) < collectorBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case done = <-e.flush:
+		drain:
+			for {
+				select {
+				case s := <-e.spans:
+					batch = append(batch, s)
+				default:
+					break drain
+				}
+			}
+		}
+		if len(batch) > 0 {
+			if err := e.post(batch); err != nil {
+				log.Warn("cannot post spans", "url", e.url, "count", len(batch), "err", err)
+			}
+			batch = []*Span{}
+		}
+		if done != nil {
+			close(done)
+		}
+	}
+}
+
+func (e *collectorExporter) post(batch []*Span) error {
+	buff, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(buff))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// flusher is implemented by exporters that buffer spans
+type flusher interface {
+	Flush()
+}
+
+// Flush blocks until the spans buffered by the exporter are exported.  Processes should call this before exiting.
+func Flush() {
+	if f, is := currentExporter().(flusher); is {
+		f.Flush()
+	}
+}
+
+type exporters []Exporter
+
+// Flush implements flusher
+func (e exporters) Flush() {
+	for _, exporter := range e {
+		if f, is := exporter.(flusher); is {
+			f.Flush()
+		}
+	}
+}
+
+// Export implements Exporter
+func (e exporters) Export(s *Span) error {
+	for _, exporter := range e {
+		if err := exporter.Export(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Configure sets the exporter to write the spans to the file and/ or post them to the collector url.
+// Tracing is disabled if both are empty.
+func Configure(file, collector string) error {
+	all := exporters{}
+	if file != "" {
+		e, err := FileExporter(file)
+		if err != nil {
+			return err
+		}
+		all = append(all, e)
+	}
+	if collector != "" {
+		all = append(all, CollectorExporter(collector))
+	}
+	switch len(all) {
+	case 0:
+		SetExporter(nil)
+	case 1:
+		SetExporter(all[0])
+	default:
+		SetExporter(all)
+	}
+	return nil
+}
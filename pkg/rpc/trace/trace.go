@@ -0,0 +1,203 @@
+package trace // import "github.com/docker/infrakit/pkg/rpc/trace"
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	logutil "github.com/docker/infrakit/pkg/log"
+)
+
+var log = logutil.New("module", "rpc/trace")
+
+const (
+	// KindClient is the kind of the spans recorded by rpc clients
+	KindClient = "CLIENT"
+
+	// KindServer is the kind of the spans recorded by rpc servers
+	KindServer = "SERVER"
+
+	// The trace context is propagated in the B3 headers understood by Zipkin and Jaeger.
+	headerTraceID  = "X-B3-TraceId"
+	headerSpanID   = "X-B3-SpanId"
+	headerParentID = "X-B3-ParentSpanId"
+	headerSampled  = "X-B3-Sampled"
+)
+
+// Context identifies a span across process boundaries
+type Context struct {
+
+	// TraceID is the id of the trace that the span belongs to
+	TraceID string
+
+	// SpanID is the id of the span
+	SpanID string
+}
+
+// Endpoint names the service that recorded a span
+type Endpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+// Span is a timed operation in a trace.  The JSON encoding is the Zipkin v2 span format, which
+// is also accepted by Jaeger.
+type Span struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind,omitempty"`
+	Timestamp     int64             `json:"timestamp"` // microseconds since epoch
+	Duration      int64             `json:"duration"`  // microseconds
+	LocalEndpoint *Endpoint         `json:"localEndpoint,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
+
+	start time.Time
+	lock  sync.Mutex
+}
+
+// Exporter receives the finished spans
+type Exporter interface {
+
+	// Export exports the span
+	Export(*Span) error
+}
+
+var (
+	exporter     Exporter
+	exporterLock sync.RWMutex
+
+	// process is the default service name of the spans recorded outside of a server
+	process = filepath.Base(os.Args[0])
+)
+
+// SetExporter sets the exporter of the finished spans.  Tracing is disabled if nil.
+func SetExporter(e Exporter) {
+	exporterLock.Lock()
+	defer exporterLock.Unlock()
+	exporter = e
+}
+
+func currentExporter() Exporter {
+	exporterLock.RLock()
+	defer exporterLock.RUnlock()
+	return exporter
+}
+
+// Enabled returns true if an exporter is set
+func Enabled() bool {
+	return currentExporter() != nil
+}
+
+// Start starts a span of the service, as a child of parent.  A new trace is started if parent is nil.
+// It returns nil if there is no parent and tracing is not enabled, so that untraced calls cost nothing.
+// Spans are still created for traced calls when tracing is not enabled so the trace context
+// propagates through this process.
+func Start(service, name, kind string, parent *Context) *Span {
+	if parent == nil && !Enabled() {
+		return nil
+	}
+	if service == "" {
+		service = process
+	}
+	now := time.Now()
+	span := &Span{
+		ID:            newID(8),
+		Name:          name,
+		Kind:          kind,
+		Timestamp:     now.UnixNano() / int64(time.Microsecond),
+		LocalEndpoint: &Endpoint{ServiceName: service},
+		start:         now,
+	}
+	if parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		span.TraceID = newID(16)
+	}
+	return span
+}
+
+// Context returns the context of the span for propagation, or nil if the span is nil
+func (s *Span) Context() *Context {
+	if s == nil {
+		return nil
+	}
+	return &Context{TraceID: s.TraceID, SpanID: s.ID}
+}
+
+// Service returns the name of the service that recorded the span
+func (s *Span) Service() string {
+	if s == nil || s.LocalEndpoint == nil {
+		return ""
+	}
+	return s.LocalEndpoint.ServiceName
+}
+
+// SetTag sets a tag on the span
+func (s *Span) SetTag(key, value string) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.Tags == nil {
+		s.Tags = map[string]string{}
+	}
+	s.Tags[key] = value
+}
+
+// Finish ends the span and exports it.  A non-nil error is recorded as the error tag.
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+	if err != nil {
+		s.SetTag("error", err.Error())
+	}
+	s.Duration = int64(time.Since(s.start) / time.Microsecond)
+
+	e := currentExporter()
+	if e == nil {
+		return
+	}
+	if err := e.Export(s); err != nil {
+		log.Warn("cannot export span", "span", s.Name, "err", err)
+	}
+}
+
+// Inject sets the headers that propagate the span's context to the callee
+func Inject(s *Span, header http.Header) {
+	if s == nil {
+		return
+	}
+	header.Set(headerTraceID, s.TraceID)
+	header.Set(headerSpanID, s.ID)
+	if s.ParentID != "" {
+		header.Set(headerParentID, s.ParentID)
+	} else {
+		header.Del(headerParentID)
+	}
+	header.Set(headerSampled, "1")
+}
+
+// Extract returns the context propagated by the caller in the headers, or nil if the call is not traced
+func Extract(header http.Header) *Context {
+	traceID, spanID := header.Get(headerTraceID), header.Get(headerSpanID)
+	if traceID == "" || spanID == "" || header.Get(headerSampled) == "0" {
+		return nil
+	}
+	return &Context{TraceID: traceID, SpanID: spanID}
+}
+
+func newID(size int) string {
+	buff := make([]byte, size)
+	if _, err := rand.Read(buff); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buff)
+}
@@ -0,0 +1,112 @@
+package trace // import "github.com/docker/infrakit/pkg/rpc/trace"
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartDisabled(t *testing.T) {
+	SetExporter(nil)
+	require.Nil(t, Start("test", "Instance.Provision", KindClient, nil))
+
+	// traced calls are continued even when this process does not export
+	span := Start("test", "Instance.Provision", KindServer, &Context{TraceID: "t", SpanID: "s"})
+	require.NotNil(t, span)
+	require.Equal(t, "t", span.TraceID)
+	require.Equal(t, "s", span.ParentID)
+}
+
+func TestInjectExtract(t *testing.T) {
+	SetExporter(nil)
+	require.Nil(t, Extract(http.Header{}))
+
+	parent := &Context{TraceID: "0123456789abcdef0123456789abcdef", SpanID: "0123456789abcdef"}
+	span := Start("test", "Group.CommitGroup", KindClient, parent)
+
+	header := http.Header{}
+	Inject(span, header)
+	require.Equal(t, span.Context(), Extract(header))
+	require.Equal(t, parent.SpanID, header.Get(headerParentID))
+
+	header.Set(headerSampled, "0")
+	require.Nil(t, Extract(header))
+}
+
+func TestContext(t *testing.T) {
+	require.Nil(t, FromContext(nil))
+	require.Nil(t, FromContext(context.Background()))
+
+	outer := &Span{ID: "outer"}
+	inner := &Span{ID: "inner"}
+
+	ctx := NewContext(context.Background(), outer)
+	require.Equal(t, outer, FromContext(ctx))
+	require.Equal(t, inner, FromContext(NewContext(ctx, inner)))
+	require.Equal(t, outer, FromContext(ctx))
+	require.Equal(t, outer, FromContext(NewContext(ctx, nil)))
+}
+
+func TestFileExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "infrakit-trace")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "spans.json")
+	require.NoError(t, Configure(path, ""))
+	defer SetExporter(nil)
+
+	parent := Start("cli", "Group.CommitGroup", KindClient, nil)
+	child := Start("group", "Group.CommitGroup", KindServer, parent.Context())
+	child.Finish(nil)
+	parent.Finish(nil)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	spans := []*Span{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		s := &Span{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), s))
+		spans = append(spans, s)
+	}
+	require.Equal(t, 2, len(spans))
+	require.Equal(t, "group", spans[0].LocalEndpoint.ServiceName)
+	require.Equal(t, parent.ID, spans[0].ParentID)
+	require.Equal(t, parent.TraceID, spans[0].TraceID)
+	require.Equal(t, "", spans[1].ParentID)
+}
+
+func TestCollectorExporter(t *testing.T) {
+	posted := make(chan []*Span, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		spans := []*Span{}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&spans))
+		posted <- spans
+		resp.WriteHeader(http.StatusAccepted)
+	}))
+	defer collector.Close()
+
+	require.NoError(t, Configure("", collector.URL))
+	defer SetExporter(nil)
+
+	span := Start("cli", "Instance.Destroy", KindClient, nil)
+	span.SetTag("peer.address", "localhost")
+	span.Finish(nil)
+	Flush()
+
+	spans := <-posted
+	require.Equal(t, 1, len(spans))
+	require.Equal(t, span.ID, spans[0].ID)
+	require.Equal(t, "localhost", spans[0].Tags["peer.address"])
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/docker/go-connections/tlsconfig"
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -20,6 +21,39 @@ const (
 
 	// EnvClientTimeout is the timeout used by the rpc client
 	EnvClientTimeout = "INFRAKIT_CLIENT_TIMEOUT"
+
+	// EnvTLSCAFile is the CA certificate used to verify peers over TLS
+	EnvTLSCAFile = "INFRAKIT_TLS_CA_FILE"
+
+	// EnvTLSCertFile is the certificate presented to peers over TLS
+	EnvTLSCertFile = "INFRAKIT_TLS_CERT_FILE"
+
+	// EnvTLSKeyFile is the private key of the certificate presented to peers over TLS
+	EnvTLSKeyFile = "INFRAKIT_TLS_KEY_FILE"
+
+	// EnvAuthPolicyFile is the authorization policy file enforced by rpc servers listening on tcp
+	EnvAuthPolicyFile = "INFRAKIT_AUTH_POLICY_FILE"
+
+	// EnvAuthToken is the bearer token presented by rpc clients
+	EnvAuthToken = "INFRAKIT_AUTH_TOKEN"
+
+	// EnvAuditDir is the directory where rpc servers write the audit records of mutating calls.
+	// Auditing is disabled if not set.
+	EnvAuditDir = "INFRAKIT_AUDIT_DIR"
+
+	// EnvEventsDir is the directory where rpc servers persist the events published, so clients resume
+	// their streams across restarts.  Events are kept in memory if not set.
+	EnvEventsDir = "INFRAKIT_EVENTS_DIR"
+
+	// EnvEventsRetain is the number of events retained per topic for the clients resuming their streams.
+	EnvEventsRetain = "INFRAKIT_EVENTS_RETAIN"
+
+	// EnvTraceFile is the file where the spans of traced rpc calls are appended
+	EnvTraceFile = "INFRAKIT_TRACE_FILE"
+
+	// EnvTraceCollector is the url of a Zipkin-compatible collector of the spans, e.g.
+	// http://localhost:9411/api/v2/spans.
+	EnvTraceCollector = "INFRAKIT_TRACE_COLLECTOR"
 )
 
 // ClientTimeout returns the client timeout
@@ -27,6 +61,29 @@ func ClientTimeout() time.Duration {
 	return types.MustParseDuration(Getenv(EnvClientTimeout, "15s")).Duration()
 }
 
+// TLSOptions returns the TLS options from the environment, or nil if TLS is not configured.
+func TLSOptions() *tlsconfig.Options {
+	options := tlsconfig.Options{
+		CAFile:   os.Getenv(EnvTLSCAFile),
+		CertFile: os.Getenv(EnvTLSCertFile),
+		KeyFile:  os.Getenv(EnvTLSKeyFile),
+	}
+	if options.CAFile == "" && options.CertFile == "" && options.KeyFile == "" {
+		return nil
+	}
+	return &options
+}
+
+// ServerTLSOptions returns the TLS options from the environment for serving, or nil if a server
+// certificate is not configured.
+func ServerTLSOptions() *tlsconfig.Options {
+	options := TLSOptions()
+	if options == nil || options.CertFile == "" {
+		return nil
+	}
+	return options
+}
+
 // InfrakitHome returns the directory of INFRAKIT_HOME if specified. Otherwise, it will return
 // the user's home directory.  If that cannot be determined, then it returns the current working
 // directory.  If that still cannot be determined, a temporary directory is returned.
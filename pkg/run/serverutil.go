@@ -5,10 +5,15 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strconv"
 
+	broker "github.com/docker/infrakit/pkg/broker/server"
 	"github.com/docker/infrakit/pkg/discovery/local"
 	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/rpc/audit"
+	"github.com/docker/infrakit/pkg/rpc/auth"
 	"github.com/docker/infrakit/pkg/rpc/server"
+	run_local "github.com/docker/infrakit/pkg/run/local"
 )
 
 // EnsureDirExists makes sure the directory where the socket file will be placed exists.
@@ -39,7 +44,8 @@ func BackgroundPlugin(transport plugin.Transport, onStop func(), plugin server.V
 
 	socketPath := path.Join(dir, name)
 	pidPath := path.Join(dir, name+".pid")
-	return run(nil, socketPath, pidPath, onStop, plugin, more...)
+	options := server.Options{Audit: auditSink(), Events: eventsOptions(name)}
+	return run(nil, socketPath, pidPath, options, onStop, plugin, more...)
 }
 
 // Listener runs a plugin server, listening at listen address, and
@@ -68,10 +74,54 @@ func BackgroundListener(transport plugin.Transport, onStop func(), plugin server
 
 	discoverPath := path.Join(dir, name+".listen")
 	pidPath := path.Join(dir, name+".pid")
-	return run([]string{transport.Listen, transport.Advertise}, discoverPath, pidPath, onStop, plugin, more...)
+	options := server.Options{TLS: transport.TLS, Audit: auditSink(), Events: eventsOptions(name)}
+	if options.TLS == nil {
+		options.TLS = run_local.ServerTLSOptions()
+	}
+	policyFile := transport.AuthPolicy
+	if policyFile == "" {
+		policyFile = os.Getenv(run_local.EnvAuthPolicyFile)
+	}
+	if policyFile != "" {
+		policy, err := auth.LoadPolicy(policyFile)
+		if err != nil {
+			log.Error("cannot load authorization policy", "path", policyFile, "err", err)
+			panic(err)
+		}
+		options.Policy = policy
+	}
+	return run([]string{transport.Listen, transport.Advertise}, discoverPath, pidPath, options,
+		onStop, plugin, more...)
+}
+
+// auditSink returns the audit sink configured in the environment, or nil if auditing is disabled
+func auditSink() audit.Sink {
+	dir := os.Getenv(run_local.EnvAuditDir)
+	if dir == "" {
+		return nil
+	}
+	EnsureDirExists(dir)
+	return audit.FileSink(dir)
+}
+
+// eventsOptions returns the retention of the events of the named server configured in the environment
+func eventsOptions(name string) broker.Options {
+	options := broker.Options{}
+	if retain := os.Getenv(run_local.EnvEventsRetain); retain != "" {
+		n, err := strconv.Atoi(retain)
+		if err != nil {
+			log.Warn("bad number of events to retain", "retain", retain, "err", err)
+		} else {
+			options.Retain = n
+		}
+	}
+	if dir := os.Getenv(run_local.EnvEventsDir); dir != "" {
+		options.Dir = path.Join(dir, name)
+	}
+	return options
 }
 
-func run(listen []string, discoverPath, pidPath string, onStop func(),
+func run(listen []string, discoverPath, pidPath string, options server.Options, onStop func(),
 	plugin server.VersionedInterface, more ...server.VersionedInterface) (server.Stoppable, <-chan struct{}) {
 
 	// make sure the pid file doesn't already exist.
@@ -89,14 +139,14 @@ func run(listen []string, discoverPath, pidPath string, onStop func(),
 	var stoppable server.Stoppable
 
 	if len(listen) > 0 {
-		s, err := server.StartListenerAtPath(listen, discoverPath, plugin, more...)
+		s, err := server.StartListenerAtPathWithOptions(listen, discoverPath, options, plugin, more...)
 		if err != nil {
 			log.Error("error starting listener", "err", err)
 			panic(err)
 		}
 		stoppable = s
 	} else {
-		s, err := server.StartPluginAtPath(discoverPath, plugin, more...)
+		s, err := server.StartPluginAtPathWithOptions(discoverPath, options, plugin, more...)
 		if err != nil {
 			log.Error("error starting plugin", "err", err)
 			panic(err)
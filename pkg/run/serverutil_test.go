@@ -0,0 +1,98 @@
+package run // import "github.com/docker/infrakit/pkg/run"
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/docker/infrakit/pkg/plugin"
+	rpc_client "github.com/docker/infrakit/pkg/rpc/client"
+	instance_rpc "github.com/docker/infrakit/pkg/rpc/instance"
+	run_local "github.com/docker/infrakit/pkg/run/local"
+	"github.com/docker/infrakit/pkg/spi/instance"
+	testing_instance "github.com/docker/infrakit/pkg/testing/instance"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSelfSignedCert writes a self-signed certificate for localhost and its key to dir
+func writeSelfSignedCert(t *testing.T, dir string) (certFile, keyFile string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().Add(1 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "server.pem")
+	keyFile = filepath.Join(dir, "server-key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+	return
+}
+
+func TestBackgroundListenerTLSFromEnv(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "infrakit-run-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := writeSelfSignedCert(t, dir)
+	os.Setenv(run_local.EnvTLSCertFile, certFile)
+	os.Setenv(run_local.EnvTLSKeyFile, keyFile)
+	defer os.Unsetenv(run_local.EnvTLSCertFile)
+	defer os.Unsetenv(run_local.EnvTLSKeyFile)
+
+	instanceID := instance.ID("id")
+	spec := instance.Spec{Init: "init"}
+	impl := &testing_instance.Plugin{
+		DoProvision: func(s instance.Spec) (*instance.ID, error) {
+			require.Equal(t, spec, s)
+			return &instanceID, nil
+		},
+	}
+
+	stoppable, running := BackgroundListener(plugin.Transport{
+		Name:      plugin.Name("tls"),
+		Listen:    "localhost:7791",
+		Advertise: "localhost:7791",
+		Dir:       dir,
+	}, nil, instance_rpc.PluginServer(impl))
+	defer func() {
+		stoppable.Stop()
+		<-running
+	}()
+
+	buff, err := ioutil.ReadFile(filepath.Join(dir, "tls.listen"))
+	require.NoError(t, err)
+	require.Equal(t, "https://localhost:7791", string(buff))
+
+	rpc_client.RegisterTLS("localhost:7791", tlsconfig.Options{CAFile: certFile})
+	c, err := instance_rpc.NewClient(plugin.Name("tls"), string(buff))
+	require.NoError(t, err)
+	id, err := c.Provision(spec)
+	require.NoError(t, err)
+	require.Equal(t, instanceID, *id)
+}
@@ -0,0 +1,82 @@
+package forwarder // import "github.com/docker/infrakit/pkg/run/v0/forwarder"
+
+import (
+	"github.com/docker/infrakit/pkg/launch/inproc"
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/plugin/event/forward"
+	metadata_plugin "github.com/docker/infrakit/pkg/plugin/metadata"
+	"github.com/docker/infrakit/pkg/run"
+	"github.com/docker/infrakit/pkg/run/local"
+	"github.com/docker/infrakit/pkg/run/scope"
+	"github.com/docker/infrakit/pkg/types"
+)
+
+const (
+	// Kind is the canonical name of the plugin for starting up, etc.
+	Kind = "forwarder"
+
+	// EnvWebhookURL is the environment variable of a webhook to forward events to when no
+	// additional configs are used.
+	EnvWebhookURL = "INFRAKIT_FORWARDER_WEBHOOK_URL"
+
+	// EnvWebhookSecret is the environment variable of the secret to sign the requests to the webhook
+	EnvWebhookSecret = "INFRAKIT_FORWARDER_WEBHOOK_SECRET"
+
+	// EnvPlugin is the environment variable of the event plugin forwarded to the webhook
+	EnvPlugin = "INFRAKIT_FORWARDER_PLUGIN"
+)
+
+var (
+	log = logutil.New("module", "run/v0/forwarder")
+
+	defaultOptions = forward.DefaultOptions
+)
+
+func init() {
+	if url := local.Getenv(EnvWebhookURL, ""); url != "" {
+		defaultOptions.Routes = []forward.Route{
+			{
+				Name:   "webhook",
+				Plugin: plugin.Name(local.Getenv(EnvPlugin, "group")),
+				Sink: forward.SinkSpec{
+					Kind: forward.WebhookKind,
+					Properties: types.AnyValueMust(forward.WebhookProperties{
+						URL:    url,
+						Secret: local.Getenv(EnvWebhookSecret, ""),
+					}),
+				},
+			},
+		}
+	}
+
+	inproc.Register(Kind, Run, defaultOptions)
+}
+
+// Run runs the plugin, blocking the current thread.  Error is returned immediately
+// if the plugin cannot be started.
+func Run(scope scope.Scope, name plugin.Name,
+	config *types.Any) (transport plugin.Transport, impls map[run.PluginCode]interface{}, onStop func(), err error) {
+
+	options := defaultOptions // decode into a copy of the updated defaults
+	err = config.Decode(&options)
+	if err != nil {
+		return
+	}
+
+	log.Info("Decoded input", "config", options)
+
+	var forwarder *forward.Forwarder
+	forwarder, err = forward.NewForwarder(scope, options)
+	if err != nil {
+		return
+	}
+	forwarder.Start()
+
+	transport.Name = name
+	impls = map[run.PluginCode]interface{}{
+		run.Metadata: metadata_plugin.NewPluginFromData(forwarder.Data()),
+	}
+	onStop = func() { forwarder.Stop() }
+	return
+}
@@ -9,18 +9,24 @@ import (
 	"github.com/docker/infrakit/pkg/rpc/client"
 	manager_rpc "github.com/docker/infrakit/pkg/rpc/manager"
 	"github.com/docker/infrakit/pkg/run"
+	"github.com/docker/infrakit/pkg/run/local"
 	"github.com/docker/infrakit/pkg/run/scope"
 	"github.com/docker/infrakit/pkg/spi/controller"
 	"github.com/docker/infrakit/pkg/spi/stack"
+	file_store "github.com/docker/infrakit/pkg/store/file"
 	"github.com/docker/infrakit/pkg/types"
 
 	// builtin models for gc
+	_ "github.com/docker/infrakit/pkg/controller/gc/model/kubernetes"
 	_ "github.com/docker/infrakit/pkg/controller/gc/model/swarm"
 )
 
 const (
 	// Kind is the canonical name of the plugin for starting up, etc.
 	Kind = "gc"
+
+	// EnvStateDir is the environment variable for the directory where the state of the collections is saved
+	EnvStateDir = "INFRAKIT_GC_STATE_DIR"
 )
 
 var (
@@ -30,7 +36,6 @@ var (
 )
 
 func init() {
-
 	inproc.Register(Kind, Run, defaultOptions)
 }
 
@@ -69,6 +74,13 @@ func Run(scope scope.Scope, name plugin.Name,
 
 	log.Info("Decoded input", "config", options)
 
+	if dir := local.Getenv(EnvStateDir, ""); dir != "" {
+		options.StateStore, err = file_store.NewSnapshot(dir, Kind+".state")
+		if err != nil {
+			return
+		}
+	}
+
 	transport.Name = name
 
 	leader := func() stack.Leadership {
@@ -9,15 +9,20 @@ import (
 	"github.com/docker/infrakit/pkg/rpc/client"
 	manager_rpc "github.com/docker/infrakit/pkg/rpc/manager"
 	"github.com/docker/infrakit/pkg/run"
+	"github.com/docker/infrakit/pkg/run/local"
 	"github.com/docker/infrakit/pkg/run/scope"
 	"github.com/docker/infrakit/pkg/spi/controller"
 	"github.com/docker/infrakit/pkg/spi/stack"
+	file_store "github.com/docker/infrakit/pkg/store/file"
 	"github.com/docker/infrakit/pkg/types"
 )
 
 const (
 	// Kind is the canonical name of the plugin for starting up, etc.
 	Kind = "inventory"
+
+	// EnvStateDir is the environment variable for the directory where the state of the collections is saved
+	EnvStateDir = "INFRAKIT_INVENTORY_STATE_DIR"
 )
 
 var (
@@ -27,7 +32,6 @@ var (
 )
 
 func init() {
-
 	inproc.Register(Kind, Run, defaultOptions)
 }
 
@@ -66,6 +70,13 @@ func Run(scope scope.Scope, name plugin.Name,
 
 	log.Info("Decoded input", "config", options)
 
+	if dir := local.Getenv(EnvStateDir, ""); dir != "" {
+		options.StateStore, err = file_store.NewSnapshot(dir, Kind+".state")
+		if err != nil {
+			return
+		}
+	}
+
 	transport.Name = name
 
 	inventory := inventory.NewComponents(scope, options)
@@ -13,6 +13,8 @@ import (
 	"github.com/docker/infrakit/pkg/spi/flavor"
 	"github.com/docker/infrakit/pkg/spi/metadata"
 	"github.com/docker/infrakit/pkg/types"
+
+	k8s "k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -21,6 +23,9 @@ const (
 
 	// EnvConfigDir is the environment variable to set the config directory
 	EnvConfigDir = "INFRAKIT_FLAVOR_KUBERNETES_CONFIG_DIR"
+
+	// EnvKubeconfig is the environment variable to set the kubeconfig used to observe the Nodes
+	EnvKubeconfig = "KUBECONFIG"
 )
 
 var (
@@ -47,7 +52,8 @@ func mustBool(b bool, err error) bool {
 
 // DefaultOptions return an Options with default values filled in.
 var DefaultOptions = kubernetes.Options{
-	ConfigDir: local.Getenv(EnvConfigDir, getWd()),
+	ConfigDir:  local.Getenv(EnvConfigDir, getWd()),
+	Kubeconfig: local.Getenv(EnvKubeconfig, ""),
 }
 
 // Run runs the plugin, blocking the current thread.  Error is returned immediately
@@ -81,12 +87,17 @@ func Run(scope scope.Scope, name plugin.Name,
 		return
 	}
 
+	instancePlugin := kubernetes.NewInstancePlugin(func() (k8s.Interface, error) {
+		return kubernetes.KubeClient(options.Kubeconfig)
+	})
+
 	transport.Name = name
 	impls = map[run.PluginCode]interface{}{
 		run.Flavor: map[string]flavor.Plugin{
 			"manager": managerFlavor,
 			"worker":  workerFlavor,
 		},
+		run.Instance: instancePlugin,
 		run.Metadata: func() (map[string]metadata.Plugin, error) {
 			return map[string]metadata.Plugin{
 				"manager": managerFlavor,
@@ -0,0 +1,98 @@
+package manager // import "github.com/docker/infrakit/pkg/run/v0/manager"
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/docker/go-connections/tlsconfig"
+	consul_leader "github.com/docker/infrakit/pkg/leader/consul"
+	"github.com/docker/infrakit/pkg/run/local"
+	consul_store "github.com/docker/infrakit/pkg/store/consul"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/docker/infrakit/pkg/util/consul"
+)
+
+const (
+	// EnvConsulAddress is the address of the consul agent
+	EnvConsulAddress = "CONSUL_HTTP_ADDR"
+
+	// EnvConsulToken is the ACL token used for the consul requests
+	EnvConsulToken = "CONSUL_HTTP_TOKEN"
+)
+
+// BackendConsulOptions contain the options for the consul backend
+type BackendConsulOptions struct {
+	// PollInterval is how often to check
+	PollInterval types.Duration
+
+	// ID is the id of the node.  It is written as the value of the leader lock.
+	ID string
+
+	consul.Options `json:",inline" yaml:",inline"`
+
+	// TLS config
+	TLS *tlsconfig.Options
+}
+
+// DefaultBackendConsulOptions contains the defaults for running consul as backend
+var DefaultBackendConsulOptions = BackendConsulOptions{
+	PollInterval: types.FromDuration(5 * time.Second),
+	ID:           local.Getenv(EnvID, "manager1"),
+	Options: consul.Options{
+		Address:        local.Getenv(EnvConsulAddress, "127.0.0.1:8500"),
+		Token:          local.Getenv(EnvConsulToken, ""),
+		RequestTimeout: 1 * time.Second,
+	},
+}
+
+func configConsulBackends(options BackendConsulOptions, managerConfig *Options) error {
+	if managerConfig == nil {
+		return nil
+	}
+
+	if options.TLS != nil {
+		config, err := tlsconfig.Client(*options.TLS)
+		if err != nil {
+			return err
+		}
+		options.Options.TLS = config
+		options.Options.Scheme = "https"
+	}
+
+	consulClient, err := consul.NewClient(options.Options)
+	log.Info("Connect to consul", "address", options.Options.Address, "err", err)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := consul_store.NewSnapshot(consulClient, "specs")
+	if err != nil {
+		return err
+	}
+
+	history, err := consul_store.NewSnapshot(consulClient, "specs.history")
+	if err != nil {
+		return err
+	}
+
+	detector := consul_leader.NewDetector(options.PollInterval.Duration(), consulClient, options.ID)
+
+	managerConfig.Leader = detector
+	managerConfig.LeaderStore = consul_leader.NewStore(consulClient)
+	managerConfig.SpecStore = snapshot
+	managerConfig.HistoryStore = history
+	managerConfig.HistorySizeLimit = historySizeLimit
+	managerConfig.cleanUpFunc = func() { consulClient.Close() }
+
+	key := "global.vars"
+	if !managerConfig.Metadata.IsEmpty() {
+		key = fmt.Sprintf("%s.vars", managerConfig.Metadata.Lookup())
+	}
+
+	metadataSnapshot, err := consul_store.NewSnapshot(consulClient, key)
+	if err != nil {
+		return err
+	}
+	managerConfig.MetadataStore = metadataSnapshot
+	return nil
+}
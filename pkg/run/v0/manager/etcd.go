@@ -60,9 +60,15 @@ func configEtcdBackends(options BackendEtcdOptions, managerConfig *Options) erro
 		return err
 	}
 
+	history, err := etcd_store.NewSnapshot(etcdClient, "specs.history")
+	if err != nil {
+		return err
+	}
+
 	managerConfig.Leader = leader
 	managerConfig.LeaderStore = leaderStore
 	managerConfig.SpecStore = snapshot
+	managerConfig.HistoryStore = history
 	managerConfig.cleanUpFunc = func() { etcdClient.Close() }
 
 	key := "global.vars"
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/docker/infrakit/pkg/leader"
 	file_leader "github.com/docker/infrakit/pkg/leader/file"
 	"github.com/docker/infrakit/pkg/run/local"
 	file_store "github.com/docker/infrakit/pkg/store/file"
@@ -20,6 +21,9 @@ const (
 
 	// EnvID is the id for the manager node (for file backend only)
 	EnvID = "INFRAKIT_ID"
+
+	// EnvLeaderLeaseTTL is the ttl of the lease when the leader is elected with a lease on the leader file
+	EnvLeaderLeaseTTL = "INFRAKIT_LEADER_LEASE_TTL"
 )
 
 // BackendFileOptions contain the options for the file backend
@@ -30,6 +34,11 @@ type BackendFileOptions struct {
 	// LeaderFile is the location of the leader file
 	LeaderFile string
 
+	// LeaseTTL enables the election of the leader with a lease in the leader file when set.  Writes to the
+	// stores are fenced so that a deposed leader cannot overwrite them.  Otherwise the leader is the node whose
+	// id matches the content of the leader file.
+	LeaseTTL types.Duration
+
 	// StoreDir is the path to the directory where state is stored
 	StoreDir string
 
@@ -42,6 +51,7 @@ var DefaultBackendFileOptions = BackendFileOptions{
 	ID:           local.Getenv(EnvID, "manager1"),
 	PollInterval: types.FromDuration(5 * time.Second),
 	LeaderFile:   local.Getenv(EnvLeaderFile, filepath.Join(local.InfrakitHome(), "leader")),
+	LeaseTTL:     types.MustParseDuration(local.Getenv(EnvLeaderLeaseTTL, "0s")),
 	StoreDir:     local.Getenv(EnvStoreDir, filepath.Join(local.InfrakitHome(), "configs")),
 }
 
@@ -50,7 +60,14 @@ func configFileBackends(options BackendFileOptions, managerConfig *Options) erro
 		return nil
 	}
 
-	leader, err := file_leader.NewDetector(options.PollInterval.Duration(), options.LeaderFile, options.ID)
+	var detector leader.Detector
+	var err error
+	if options.LeaseTTL > 0 {
+		detector, err = file_leader.NewLeaseDetector(options.PollInterval.Duration(), options.LeaseTTL.Duration(),
+			options.LeaderFile, options.ID)
+	} else {
+		detector, err = file_leader.NewDetector(options.PollInterval.Duration(), options.LeaderFile, options.ID)
+	}
 	if err != nil {
 		return err
 	}
@@ -61,9 +78,15 @@ func configFileBackends(options BackendFileOptions, managerConfig *Options) erro
 		return err
 	}
 
-	managerConfig.Leader = leader
+	history, err := file_store.NewSnapshot(options.StoreDir, "global.history")
+	if err != nil {
+		return err
+	}
+
+	managerConfig.Leader = detector
 	managerConfig.LeaderStore = leaderStore
 	managerConfig.SpecStore = snapshot
+	managerConfig.HistoryStore = history
 
 	key := "global.vars"
 	if !managerConfig.Metadata.IsEmpty() {
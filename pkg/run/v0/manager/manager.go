@@ -3,12 +3,15 @@ package manager // import "github.com/docker/infrakit/pkg/run/v0/manager"
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/docker/go-connections/tlsconfig"
 	"github.com/docker/infrakit/pkg/launch/inproc"
 	logutil "github.com/docker/infrakit/pkg/log"
 	"github.com/docker/infrakit/pkg/manager"
 	"github.com/docker/infrakit/pkg/plugin"
+	"github.com/docker/infrakit/pkg/rpc/auth"
 	"github.com/docker/infrakit/pkg/rpc/mux"
 	rpc "github.com/docker/infrakit/pkg/rpc/server"
 	"github.com/docker/infrakit/pkg/run"
@@ -42,9 +45,16 @@ const (
 	// EnvControllers is a list of comma-delimited controller names
 	EnvControllers = "INFRAKIT_MANAGER_CONTROLLERS"
 
+	// EnvHistoryLimit is the number of revisions of the specs to keep
+	EnvHistoryLimit = "INFRAKIT_MANAGER_HISTORY_LIMIT"
+
 	// EnvLeaderCommitSpecsRetryInterval is the interval to wait between retries when
 	// the manager becomes the leader and fails to commit the replicated specs.
 	EnvLeaderCommitSpecsRetryInterval = "INFRAKIT_MANAGER_COMMIT_SPECS_RETRY_INTERVAL"
+
+	// EnvSwarmDemoteOnStepDown is set to true to allow the leader to step down by demoting its node to
+	// a worker with the swarm backend.
+	EnvSwarmDemoteOnStepDown = "INFRAKIT_MANAGER_SWARM_DEMOTE_ON_STEP_DOWN"
 )
 
 var (
@@ -61,7 +71,7 @@ type Options struct {
 	manager.Options
 
 	// Backend is the backend used for leadership, persistence, etc.
-	// Possible values are file, etcd, and swarm
+	// Possible values are file, etcd, consul, and swarm
 	Backend string
 
 	// Settings is the configuration of the backend
@@ -80,6 +90,13 @@ type MuxConfig struct {
 
 	// Advertise is the public listen string e.g. public_ip:24864
 	Advertise string
+
+	// TLS is the optional TLS configuration for serving https.  If the CAFile is set,
+	// clients must present a certificate signed by the CA.
+	TLS *tlsconfig.Options
+
+	// AuthPolicy is the path of the optional authorization policy file enforced on the rpc calls.
+	AuthPolicy string
 }
 
 // DefaultOptions return an Options with default values filled in.
@@ -94,10 +111,13 @@ func defaultOptions() (options Options) {
 			LeaderCommitSpecsRetries:       10,
 			LeaderCommitSpecsRetryInterval: types.MustParseDuration(local.Getenv(EnvLeaderCommitSpecsRetryInterval, "2s")),
 			Controllers:                    plugin.NamesFrom(strings.Split(local.Getenv(EnvControllers, ""), ",")),
+			HistoryLimit:                   historyLimit(),
 		},
 		Mux: &MuxConfig{
-			Listen:    local.Getenv(EnvMuxListen, ":24864"),
-			Advertise: local.Getenv(EnvAdvertise, "localhost:24864"),
+			Listen:     local.Getenv(EnvMuxListen, ":24864"),
+			Advertise:  local.Getenv(EnvAdvertise, "localhost:24864"),
+			TLS:        local.ServerTLSOptions(),
+			AuthPolicy: local.Getenv(local.EnvAuthPolicyFile, ""),
 		},
 	}
 
@@ -109,6 +129,9 @@ func defaultOptions() (options Options) {
 	case "etcd":
 		options.Backend = "etcd"
 		options.Settings = types.AnyValueMust(DefaultBackendEtcdOptions)
+	case "consul":
+		options.Backend = "consul"
+		options.Settings = types.AnyValueMust(DefaultBackendConsulOptions)
 	case "file":
 		options.Backend = "file"
 		options.Settings = types.AnyValueMust(DefaultBackendFileOptions)
@@ -120,6 +143,21 @@ func defaultOptions() (options Options) {
 	return
 }
 
+// historySizeLimit is the size in bytes the history is kept under by the backends that store it in a single
+// value of bounded size: a key in consul, which holds up to 512KB, and a label of the swarm spec, which
+// shares the size of the spec with the specs and the vars.
+const historySizeLimit = 128 * 1024
+
+// historyLimit returns the number of revisions to keep from the environment
+func historyLimit() int {
+	limit, err := strconv.Atoi(local.Getenv(EnvHistoryLimit, "100"))
+	if err != nil {
+		log.Warn("Bad history limit", "err", err)
+		return 100
+	}
+	return limit
+}
+
 // Run runs the plugin, blocking the current thread.  Error is returned immediately
 // if the plugin cannot be started.
 func Run(scope scope.Scope, name plugin.Name,
@@ -149,6 +187,18 @@ func Run(scope scope.Scope, name plugin.Name,
 			return
 		}
 		log.Info("etcd backend", "leader", options.Leader, "store", options.SpecStore, "cleanup", options.cleanUpFunc)
+	case "consul":
+		backendOptions := DefaultBackendConsulOptions
+		err = options.Settings.Decode(&backendOptions)
+		if err != nil {
+			return
+		}
+		log.Info("starting up consul backend", "address", backendOptions.Address, "id", backendOptions.ID)
+		err = configConsulBackends(backendOptions, &options)
+		if err != nil {
+			return
+		}
+		log.Info("consul backend", "leader", options.Leader, "store", options.SpecStore, "cleanup", options.cleanUpFunc)
 	case "file":
 		backendOptions := DefaultBackendFileOptions
 		err = options.Settings.Decode(&backendOptions)
@@ -201,11 +251,22 @@ func Run(scope scope.Scope, name plugin.Name,
 
 	if options.Mux != nil {
 
-		log.Info("Starting mux server", "listen", options.Mux.Listen, "advertise", options.Mux.Advertise)
+		var policy *auth.Policy
+		if options.Mux.AuthPolicy != "" {
+			policy, err = auth.LoadPolicy(options.Mux.AuthPolicy)
+			if err != nil {
+				return
+			}
+		}
+
+		log.Info("Starting mux server", "listen", options.Mux.Listen, "advertise", options.Mux.Advertise,
+			"tls", options.Mux.TLS != nil, "policy", options.Mux.AuthPolicy)
 		muxServer, err = mux.NewServer(options.Mux.Listen, options.Mux.Advertise, scope.Plugins,
 			mux.Options{
 				Leadership: options.Leader.Receive(),
 				Registry:   options.LeaderStore,
+				TLS:        options.Mux.TLS,
+				Policy:     policy,
 			})
 		if err != nil {
 			fmt.Printf("Cannot start up mux server.  Error: %v\n", err)
@@ -6,6 +6,7 @@ import (
 	"github.com/docker/go-connections/tlsconfig"
 	swarm_leader "github.com/docker/infrakit/pkg/leader/swarm"
 	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/run/local"
 	swarm_store "github.com/docker/infrakit/pkg/store/swarm"
 	"github.com/docker/infrakit/pkg/types"
 	"github.com/docker/infrakit/pkg/util/docker"
@@ -17,6 +18,9 @@ type BackendSwarmOptions struct {
 	PollInterval types.Duration
 	// Docker holds the connection params to the Docker engine for join tokens, etc.
 	Docker docker.ConnectInfo `json:",inline" yaml:",inline"`
+	// DemoteOnStepDown allows the leader to step down by demoting its node to a worker.  The node
+	// has to be promoted again to rejoin the managers.
+	DemoteOnStepDown bool
 }
 
 // DefaultBackendSwarmOptions is the Options for using the swarm backend.
@@ -26,6 +30,7 @@ var DefaultBackendSwarmOptions = BackendSwarmOptions{
 		Host: "unix:///var/run/docker.sock",
 		TLS:  &tlsconfig.Options{},
 	},
+	DemoteOnStepDown: local.Getenv(EnvSwarmDemoteOnStepDown, "false") == "true",
 }
 
 func configSwarmBackends(options BackendSwarmOptions, managerConfig *Options) error {
@@ -45,12 +50,21 @@ func configSwarmBackends(options BackendSwarmOptions, managerConfig *Options) er
 		return err
 	}
 
+	history, err := swarm_store.NewSnapshot(dockerClient, "infrakit.specs.history")
+	if err != nil {
+		dockerClient.Close()
+		return err
+	}
+
 	leader := swarm_leader.NewDetector(options.PollInterval.Duration(), dockerClient)
+	leader.Demote = options.DemoteOnStepDown
 	leaderStore := swarm_leader.NewStore(dockerClient)
 
 	managerConfig.Leader = leader
 	managerConfig.LeaderStore = leaderStore
 	managerConfig.SpecStore = snapshot
+	managerConfig.HistoryStore = history
+	managerConfig.HistorySizeLimit = historySizeLimit
 	managerConfig.cleanUpFunc = func() {
 		dockerClient.Close()
 		log.Debug("closed docker connection", "client", dockerClient, "V", logutil.V(100))
@@ -34,6 +34,9 @@ type Options struct {
 	// InitialTemplate is the url or literal (with str://) of the template to evaluate to initialize the values.
 	// The template must evaluate to a map.  Slice is not supported
 	InitialTemplate *string
+
+	// Schema is the JSON schema of the vars.  Changes that don't conform to the schema are rejected.
+	Schema *types.Any
 }
 
 func ptr(s string) *string {
@@ -103,10 +106,11 @@ func Run(scope scope.Scope, name plugin.Name,
 
 	transport.Name = name
 	impls = map[run.PluginCode]interface{}{
-		run.MetadataUpdatable: metadata_plugin.NewUpdatablePlugin(metadata_plugin.NewPluginFromData(data),
+		run.MetadataUpdatable: metadata_plugin.NewUpdatablePluginWithSchema(metadata_plugin.NewPluginFromData(data),
 			func(proposed *types.Any) error {
 				return proposed.Decode(&data)
 			},
+			options.Schema,
 		),
 	}
 
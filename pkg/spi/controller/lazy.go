@@ -1,10 +1,12 @@
 package controller // import "github.com/docker/infrakit/pkg/spi/controller"
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -68,6 +70,19 @@ func (c *lazyConnect) do(f func(p Controller) error) (err error) {
 	return f(c.client)
 }
 
+// WithContext implements spi.Contextual.  The controller connected is used in the context if it is Contextual.
+func (c *lazyConnect) WithContext(ctx context.Context) interface{} {
+	return &lazyConnect{
+		finder: func() (p Controller, err error) {
+			err = c.do(func(connected Controller) error {
+				p = spi.WithContext(connected, ctx).(Controller)
+				return nil
+			})
+			return
+		},
+	}
+}
+
 func (c *lazyConnect) Plan(op Operation, spec types.Spec) (object types.Object, plan Plan, err error) {
 	err = c.do(func(p Controller) error {
 		object, plan, err = p.Plan(op, spec)
@@ -99,3 +114,37 @@ func (c *lazyConnect) Free(search *types.Metadata) (objects []types.Object, err
 	})
 	return
 }
+
+func (c *lazyConnect) Claim(search types.Metadata, consumer string, ttl time.Duration) (lease Lease, err error) {
+	err = c.do(func(p Controller) error {
+		leasing, is := p.(Leasing)
+		if !is {
+			return ErrLeasingNotSupported
+		}
+		lease, err = leasing.Claim(search, consumer, ttl)
+		return err
+	})
+	return
+}
+
+func (c *lazyConnect) Release(search types.Metadata, id string) error {
+	return c.do(func(p Controller) error {
+		leasing, is := p.(Leasing)
+		if !is {
+			return ErrLeasingNotSupported
+		}
+		return leasing.Release(search, id)
+	})
+}
+
+func (c *lazyConnect) Leases(search types.Metadata) (leases []Lease, err error) {
+	err = c.do(func(p Controller) error {
+		leasing, is := p.(Leasing)
+		if !is {
+			return ErrLeasingNotSupported
+		}
+		leases, err = leasing.Leases(search)
+		return err
+	})
+	return
+}
@@ -2,6 +2,7 @@ package controller // import "github.com/docker/infrakit/pkg/spi/controller"
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/docker/infrakit/pkg/spi/stack"
 	"github.com/docker/infrakit/pkg/types"
@@ -76,3 +77,40 @@ func (s *singleton) Free(search *types.Metadata) (objects []types.Object, err er
 	})
 	return
 }
+
+// Claim leases an idle resource, if the controller supports leasing
+func (s *singleton) Claim(search types.Metadata, consumer string, ttl time.Duration) (lease Lease, err error) {
+	err = s.do(func() error {
+		leasing, is := s.Controller.(Leasing)
+		if !is {
+			return ErrLeasingNotSupported
+		}
+		lease, err = leasing.Claim(search, consumer, ttl)
+		return err
+	})
+	return
+}
+
+// Release ends a lease, if the controller supports leasing
+func (s *singleton) Release(search types.Metadata, id string) error {
+	return s.do(func() error {
+		leasing, is := s.Controller.(Leasing)
+		if !is {
+			return ErrLeasingNotSupported
+		}
+		return leasing.Release(search, id)
+	})
+}
+
+// Leases returns the current leases, if the controller supports leasing
+func (s *singleton) Leases(search types.Metadata) (leases []Lease, err error) {
+	err = s.do(func() error {
+		leasing, is := s.Controller.(Leasing)
+		if !is {
+			return ErrLeasingNotSupported
+		}
+		leases, err = leasing.Leases(search)
+		return err
+	})
+	return
+}
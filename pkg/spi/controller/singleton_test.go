@@ -23,6 +23,10 @@ func (f fakeLeaderT) LeaderLocation() (*url.URL, error) {
 	return nil, nil
 }
 
+func (f fakeLeaderT) StepDown(successor string) error {
+	return nil
+}
+
 func TestSingleton(t *testing.T) {
 
 	call1 := make(chan int, 1)
@@ -1,7 +1,11 @@
 package controller // import "github.com/docker/infrakit/pkg/spi/controller"
 
 import (
+	"errors"
+	"time"
+
 	"github.com/docker/infrakit/pkg/spi"
+	"github.com/docker/infrakit/pkg/spi/instance"
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -9,6 +13,52 @@ import (
 type Plan struct {
 	// Message contains human-friendly message
 	Message []string
+
+	// Changes are the changes to the resources under management, in the order of their Step
+	Changes []Change `json:",omitempty" yaml:",omitempty"`
+
+	// Before is the spec currently enforced.  It is nil if the object is not yet managed.
+	Before *types.Spec `json:",omitempty" yaml:",omitempty"`
+
+	// After is the spec proposed
+	After *types.Spec `json:",omitempty" yaml:",omitempty"`
+}
+
+// ChangeType is the type of change made to a resource
+type ChangeType string
+
+const (
+	// ChangeCreate creates a new resource
+	ChangeCreate ChangeType = "create"
+
+	// ChangeDestroy destroys a resource
+	ChangeDestroy ChangeType = "destroy"
+
+	// ChangeUpdate replaces a resource with one that matches the new spec
+	ChangeUpdate ChangeType = "update"
+
+	// ChangeLabel labels a resource so that it is tracked as part of the object
+	ChangeLabel ChangeType = "label"
+)
+
+// Change is a change made to a resource when a spec is committed
+type Change struct {
+
+	// Type is the type of change
+	Type ChangeType
+
+	// Step orders the changes.  Changes of the same step, e.g. a batch of a rolling update, may be made
+	// concurrently and before changes of later steps.
+	Step int
+
+	// ID is the id of the resource changed.  It is empty for resources that are yet to be created.
+	ID string `json:",omitempty" yaml:",omitempty"`
+
+	// LogicalID is the logical id of the resource changed, if any
+	LogicalID string `json:",omitempty" yaml:",omitempty"`
+
+	// Message is a human-friendly description of the change
+	Message string `json:",omitempty" yaml:",omitempty"`
 }
 
 // Operation is the action to be taken for a commit
@@ -52,3 +102,37 @@ type Controller interface {
 	// Free tells the controller to pause management of objects matching.  To resume, commit again.
 	Free(*types.Metadata) ([]types.Object, error)
 }
+
+// Lease is a resource of an object that is handed out to a consumer for a period of time
+type Lease struct {
+
+	// ID is the id of the lease.  It's the key of the resource in the object.
+	ID string
+
+	// Consumer is the consumer holding the lease
+	Consumer string
+
+	// Expires is when the lease expires
+	Expires time.Time
+
+	// Instance is the leased resource
+	Instance instance.Description
+}
+
+// ErrLeasingNotSupported is returned by controllers that don't hand out resources
+var ErrLeasingNotSupported = errors.New("leasing not supported")
+
+// Leasing is implemented by controllers that can hand out the resources of the objects they manage, e.g. a pool
+// of warm instances.
+type Leasing interface {
+
+	// Claim leases an idle resource of the object matching the metadata to the consumer, for the given duration.
+	// The default duration of the object is used if the duration is 0.
+	Claim(search types.Metadata, consumer string, ttl time.Duration) (Lease, error)
+
+	// Release ends the lease of the given id before it expires
+	Release(search types.Metadata, id string) error
+
+	// Leases returns the current leases of the object matching the metadata
+	Leases(search types.Metadata) ([]Lease, error)
+}
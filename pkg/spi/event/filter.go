@@ -0,0 +1,144 @@
+package event // import "github.com/docker/infrakit/pkg/spi/event"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/docker/infrakit/pkg/types"
+)
+
+// Filter selects the events of a subscription.  The filter is evaluated by the server, before the
+// events are written to the stream of the subscriber.
+type Filter struct {
+
+	// Types are the types of the events selected.  All types if empty.
+	Types []Type `json:",omitempty"`
+
+	// Data are the values of fields in the data of the events selected, by their pointers,
+	// e.g. {"/Status": "running"}
+	Data map[string]interface{} `json:",omitempty"`
+
+	// Expression is a template that must render to true for the events selected, e.g.
+	// {{ gt .Data.Size 10.0 }}.  The template is evaluated with the fields of the event and only
+	// the builtin functions of templates.
+	Expression string `json:",omitempty"`
+
+	// Rate is the maximum number of events per second.  Unlimited if 0.
+	Rate float64 `json:",omitempty"`
+
+	// Sample selects one event in every Sample events.  All events if 0 or 1.
+	Sample int `json:",omitempty"`
+}
+
+// Empty returns true if the filter selects all events
+func (f Filter) Empty() bool {
+	return len(f.Types) == 0 && len(f.Data) == 0 && f.Expression == "" && f.Rate <= 0 && f.Sample <= 1
+}
+
+// Selector returns the function that selects the events by the filter.  Each selector keeps its
+// own count of the events for the rate limit and sampling, so a selector is used for one subscription.
+func (f Filter) Selector() (func(*Event) bool, error) {
+	if f.Rate < 0 || f.Sample < 0 {
+		return nil, fmt.Errorf("negative rate or sample")
+	}
+
+	// the values are compared as decoded from json
+	var data map[string]interface{}
+	if len(f.Data) > 0 {
+		buff, err := json.Marshal(f.Data)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(buff, &data); err != nil {
+			return nil, err
+		}
+	}
+
+	// Not the template engine of infrakit: the expressions come from the subscribers and
+	// must not fetch or include anything on the server.
+	var expression *template.Template
+	if f.Expression != "" {
+		t, err := template.New("filter").Option("missingkey=zero").Parse(f.Expression)
+		if err != nil {
+			return nil, err
+		}
+		expression = t
+	}
+
+	var interval time.Duration
+	if f.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / f.Rate)
+	}
+
+	var (
+		lock  sync.Mutex
+		count int
+		last  time.Time
+	)
+
+	return func(event *Event) bool {
+		if len(f.Types) > 0 && !hasType(f.Types, event.Type) {
+			return false
+		}
+
+		if data != nil || expression != nil {
+			var doc interface{}
+			if event.Data != nil {
+				if err := event.Data.Decode(&doc); err != nil {
+					return false
+				}
+			}
+			for p, v := range data {
+				if !reflect.DeepEqual(v, types.PointerFromString(p).Get(doc)) {
+					return false
+				}
+			}
+			if expression != nil {
+				var buff bytes.Buffer
+				err := expression.Execute(&buff, map[string]interface{}{
+					"Topic":   event.Topic.String(),
+					"Type":    string(event.Type),
+					"ID":      event.ID,
+					"Message": event.Message,
+					"Data":    doc,
+				})
+				if err != nil || strings.TrimSpace(buff.String()) != "true" {
+					return false
+				}
+			}
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		if f.Sample > 1 {
+			count++
+			if (count-1)%f.Sample != 0 {
+				return false
+			}
+		}
+		if interval > 0 {
+			now := time.Now()
+			if !last.IsZero() && now.Sub(last) < interval {
+				return false
+			}
+			last = now
+		}
+		return true
+	}, nil
+}
+
+func hasType(list []Type, t Type) bool {
+	for _, v := range list {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package event // import "github.com/docker/infrakit/pkg/spi/event"
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+
+	require.True(t, Filter{}.Empty())
+	require.True(t, Filter{Sample: 1}.Empty())
+	require.False(t, Filter{Types: []Type{"Create"}}.Empty())
+
+	_, err := Filter{Expression: "{{ bad"}.Selector()
+	require.Error(t, err)
+
+	_, err = Filter{Rate: -1}.Selector()
+	require.Error(t, err)
+
+	events := []*Event{}
+	for i := 0; i < 10; i++ {
+		events = append(events, Event{
+			Type: Type(fmt.Sprintf("T%d", i%2)),
+			ID:   fmt.Sprintf("e%d", i),
+		}.Init().WithDataMust(map[string]interface{}{
+			"Size":   i,
+			"Labels": map[string]string{"zone": fmt.Sprintf("z%d", i%3)},
+		}))
+	}
+
+	selected := func(f Filter) []string {
+		selector, err := f.Selector()
+		require.NoError(t, err)
+		ids := []string{}
+		for _, e := range events {
+			if selector(e) {
+				ids = append(ids, e.ID)
+			}
+		}
+		return ids
+	}
+
+	require.Equal(t, 10, len(selected(Filter{})))
+	require.Equal(t, []string{"e1", "e3", "e5", "e7", "e9"}, selected(Filter{Types: []Type{"T1"}}))
+	require.Equal(t, []string{"e2", "e5", "e8"}, selected(Filter{Data: map[string]interface{}{"/Labels/zone": "z2"}}))
+	require.Equal(t, []string{"e5"}, selected(Filter{
+		Types: []Type{"T1"},
+		Data:  map[string]interface{}{"/Labels/zone": "z2"},
+	}))
+	require.Equal(t, []string{"e7", "e8", "e9"}, selected(Filter{Expression: "{{ gt .Data.Size 6.0 }}"}))
+	require.Equal(t, []string{"e0", "e3", "e6", "e9"}, selected(Filter{Sample: 3}))
+
+	// only the first event within the interval
+	require.Equal(t, []string{"e0"}, selected(Filter{Rate: 1}))
+
+	selector, err := Filter{Rate: 50}.Selector()
+	require.NoError(t, err)
+	require.True(t, selector(events[0]))
+	require.False(t, selector(events[1]))
+	time.Sleep(30 * time.Millisecond)
+	require.True(t, selector(events[2]))
+}
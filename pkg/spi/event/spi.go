@@ -42,3 +42,10 @@ type Subscriber interface {
 	// SubscribeOn returns the channel for the topic
 	SubscribeOn(topic types.Path) (<-chan *Event, chan<- struct{}, error)
 }
+
+// FilteredSubscriber is the interface of subscribers that have the events filtered by the server
+type FilteredSubscriber interface {
+
+	// SubscribeWithFilter returns the channel for the events of the topic selected by the filter
+	SubscribeWithFilter(topic types.Path, filter Filter) (<-chan *Event, chan<- struct{}, error)
+}
@@ -1,10 +1,12 @@
 package group // import "github.com/docker/infrakit/pkg/spi/group"
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/spi/instance"
 )
 
@@ -68,6 +70,19 @@ func (c *lazyConnect) do(f func(p Plugin) error) (err error) {
 	return f(c.client)
 }
 
+// WithContext implements spi.Contextual.  The plugin connected is used in the context if it is Contextual.
+func (c *lazyConnect) WithContext(ctx context.Context) interface{} {
+	return &lazyConnect{
+		finder: func() (p Plugin, err error) {
+			err = c.do(func(connected Plugin) error {
+				p = spi.WithContext(connected, ctx).(Plugin)
+				return nil
+			})
+			return
+		},
+	}
+}
+
 func (c *lazyConnect) CommitGroup(grp Spec, pretend bool) (resp string, err error) {
 	err = c.do(func(p Plugin) error {
 		resp, err = p.CommitGroup(grp, pretend)
@@ -131,3 +146,19 @@ func (c *lazyConnect) SetSize(id ID, size int) (err error) {
 	})
 	return
 }
+
+func (c *lazyConnect) PromoteUpdate(id ID) (err error) {
+	err = c.do(func(p Plugin) error {
+		err = p.PromoteUpdate(id)
+		return err
+	})
+	return
+}
+
+func (c *lazyConnect) AbortUpdate(id ID) (err error) {
+	err = c.do(func(p Plugin) error {
+		err = p.AbortUpdate(id)
+		return err
+	})
+	return
+}
@@ -95,6 +95,16 @@ func (f fake) SetSize(id ID, sz int) error {
 	return nil
 }
 
+func (f fake) PromoteUpdate(id ID) error {
+	f <- []interface{}{Plugin.PromoteUpdate, id}
+	return nil
+}
+
+func (f fake) AbortUpdate(id ID) error {
+	f <- []interface{}{Plugin.AbortUpdate, id}
+	return nil
+}
+
 func checkCalls(t *testing.T, ch chan []interface{}, args ...interface{}) {
 	found := <-ch
 	for i, a := range args {
@@ -1,6 +1,8 @@
 package group // import "github.com/docker/infrakit/pkg/spi/group"
 
 import (
+	"time"
+
 	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/spi/instance"
 	"github.com/docker/infrakit/pkg/types"
@@ -16,7 +18,7 @@ const (
 // InterfaceSpec is the current name and version of the Group API.
 var InterfaceSpec = spi.InterfaceSpec{
 	Name:    "Group",
-	Version: "0.1.1",
+	Version: "0.1.2",
 }
 
 // AllocationMethod defines the type of allocation and supervision needed by a flavor's Group.
@@ -59,6 +61,13 @@ type Plugin interface {
 	// SetSize sets the size.
 	// This function should block until completion.
 	SetSize(ID, int) error
+
+	// PromoteUpdate resumes an update of the group that is paused after its canary phase.
+	PromoteUpdate(ID) error
+
+	// AbortUpdate aborts an update of the group that is paused after its canary phase,
+	// rolling back the canary instances.
+	AbortUpdate(ID) error
 }
 
 // ID is the unique identifier for a Group.
@@ -81,4 +90,46 @@ type Spec struct {
 type Description struct {
 	Instances []instance.Description
 	Converged bool
+
+	// Rollback is set when the last update of the group was automatically rolled back.
+	Rollback *Rollback `json:",omitempty"`
+
+	// Canary is set when the group is being updated with a canary phase.
+	Canary *Canary `json:",omitempty"`
+}
+
+// Canary describes the canary phase of an update of a group.
+type Canary struct {
+	// ConfigSHA is the config hash of the update
+	ConfigSHA string
+
+	// Size is the number of instances updated in the canary phase
+	Size int
+
+	// Paused is true when the canary instances are in place and the update waits to be promoted or aborted
+	Paused bool
+
+	// Healthy are the instances with the new config that are healthy
+	Healthy []instance.ID
+
+	// Unhealthy are the instances with the new config that are unhealthy
+	Unhealthy []instance.ID
+
+	// Unknown are the instances with the new config whose health is unknown
+	Unknown []instance.ID
+}
+
+// Rollback describes an update of a group that was automatically rolled back.
+type Rollback struct {
+	// From is the config hash of the update that failed
+	From string
+
+	// To is the config hash that the group is rolled back to
+	To string
+
+	// Reason is the cause of the rollback
+	Reason string
+
+	// Timestamp is when the rollback started
+	Timestamp time.Time
 }
@@ -48,3 +48,26 @@ type Updatable interface {
 	// optimistic concurrency control.
 	Commit(proposed *types.Any, cas string) error
 }
+
+// Update is a change of the value at a path, sent to the watchers of the path
+type Update struct {
+	Path types.Path
+	Old  *types.Any
+	New  *types.Any
+}
+
+// Watcher is the interface of plugins that notify of the changes of their metadata
+type Watcher interface {
+
+	// Watch returns the channel of the updates of the value at the path.  Closing the done
+	// channel stops the watch.
+	Watch(path types.Path) (updates <-chan Update, done chan<- struct{}, err error)
+}
+
+// Schema is the optional interface of updatable plugins that declare the schema of their metadata.
+// Changes that don't conform to the schema are rejected.
+type Schema interface {
+
+	// Schema returns the JSON schema of the metadata tree
+	Schema() *types.Any
+}
@@ -1,6 +1,8 @@
 package spi // import "github.com/docker/infrakit/pkg/spi"
 
 import (
+	"context"
+
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -41,3 +43,21 @@ type InputExample interface {
 	// blob in all the plugin methods where raw JSON messages are referenced.
 	ExampleProperties() *types.Any
 }
+
+// Contextual is an optional interface implemented by the plugins that use the context of the call
+// they serve, e.g. the identity of the caller or the trace of the call, and by the clients that pass
+// it on to the plugins they call.  The methods of the object returned use the context given.
+type Contextual interface {
+
+	// WithContext returns the object, implementing the same interfaces, to use in the context
+	WithContext(ctx context.Context) interface{}
+}
+
+// WithContext returns the object to use in the context if the object is Contextual.  Otherwise the
+// object is returned as is.
+func WithContext(object interface{}, ctx context.Context) interface{} {
+	if c, is := object.(Contextual); is && ctx != nil {
+		return c.WithContext(ctx)
+	}
+	return object
+}
@@ -2,6 +2,7 @@ package stack // import "github.com/docker/infrakit/pkg/spi/stack"
 
 import (
 	"net/url"
+	"time"
 
 	"github.com/docker/infrakit/pkg/spi"
 	"github.com/docker/infrakit/pkg/types"
@@ -29,6 +30,34 @@ type Interface interface {
 
 	// Terminate destroys all resources associated with the specs
 	Terminate(specs []types.Spec) error
+
+	// History returns the revisions of the specs, oldest first.  The specs of each revision are not included.
+	History() ([]Revision, error)
+
+	// Revision returns the revision, including its specs.
+	Revision(rev int) (Revision, error)
+
+	// Rollback enforces the specs of an earlier revision.  The rollback is recorded as a new revision.
+	Rollback(rev int) error
+}
+
+// Revision is a version of the specs being enforced.  A revision is recorded each time the specs change.
+type Revision struct {
+
+	// Revision is the sequence number of the revision, starting at 1
+	Revision int
+
+	// Author is the caller who made the change
+	Author string
+
+	// Timestamp is when the revision was recorded
+	Timestamp time.Time
+
+	// Fingerprint is the fingerprint of the specs
+	Fingerprint string
+
+	// Specs are the specs of the revision
+	Specs []types.Spec `json:",omitempty" yaml:",omitempty"`
 }
 
 // Leadership is the interface for getting information about the current leader node
@@ -38,4 +67,8 @@ type Leadership interface {
 
 	// LeaderLocation returns the location of the leader
 	LeaderLocation() (*url.URL, error)
+
+	// StepDown makes the leader stop its controllers and give up the leadership.  If the successor is
+	// not empty, the leadership is handed to the node of that id.
+	StepDown(successor string) error
 }
@@ -0,0 +1,156 @@
+package consul // import "github.com/docker/infrakit/pkg/store/consul"
+
+import (
+	"fmt"
+	"math/rand"
+	"path"
+	"strings"
+
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/store"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/docker/infrakit/pkg/util/consul"
+)
+
+const (
+	namespace = "infrakit/configs"
+
+	// kvNamespace is where the kv stores keep their entries
+	kvNamespace = "infrakit/store"
+)
+
+var (
+	log = logutil.New("module", "consul/store")
+
+	logV = logutil.V(300)
+)
+
+// NewSnapshot returns a snapshot given the client
+func NewSnapshot(client *consul.Client, key string) (store.Snapshot, error) {
+	return &snapshot{
+		client: client,
+		key:    path.Join(namespace, key),
+	}, nil
+}
+
+type snapshot struct {
+	client *consul.Client
+	key    string
+}
+
+// Save marshals (encodes) and saves a snapshot of the given object.
+func (s *snapshot) Save(obj interface{}) error {
+	any, err := types.AnyValue(obj)
+	if err != nil {
+		return err
+	}
+	err = s.client.Put(s.key, any.Bytes())
+	if err != nil {
+		log.Warn("Cannot save snapshot", "key", s.key, "err", err)
+	}
+	return err
+}
+
+// Load loads a snapshot and marshals (decodes) into the given reference.
+// If no data is available to unmarshal into the given struct, the fuction returns nil.
+func (s *snapshot) Load(output interface{}) error {
+	pair, err := s.client.Get(s.key)
+	if err != nil {
+		log.Warn("Cannot load snapshot", "key", s.key, "err", err)
+		return err
+	}
+	if pair == nil || len(pair.Value) == 0 {
+		// no data. therefore no effect on the input
+		return nil
+	}
+	return types.AnyBytes(pair.Value).Decode(output)
+}
+
+// Close releases the resources and closes the connection to consul
+func (s *snapshot) Close() error {
+	return s.client.Close()
+}
+
+// Store stores the entries of a type under a key prefix in consul
+type Store struct {
+	client *consul.Client
+	prefix string
+}
+
+// NewStore returns a kv store of the entries of the type name
+func NewStore(client *consul.Client, typeName string) store.KV {
+	return &Store{
+		client: client,
+		prefix: path.Join(kvNamespace, typeName) + "/",
+	}
+}
+
+// Close implements io.Closer
+func (s *Store) Close() error {
+	return s.client.Close()
+}
+
+// Write writes the object and returns an id or error.
+func (s *Store) Write(key interface{}, value []byte) error {
+	if key == nil {
+		key = rand.Int63()
+	}
+	log.Debug("Write", "key", s.Key(key), "V", logV)
+	return s.client.Put(s.Key(key), value)
+}
+
+// Key returns an id given the key. The id contains type, etc.
+func (s *Store) Key(key interface{}) string {
+	return fmt.Sprintf("%s%v", s.prefix, key)
+}
+
+// Read loads the object
+func (s *Store) Read(key interface{}) ([]byte, error) {
+	pair, err := s.client.Get(s.Key(key))
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, fmt.Errorf("not found %v", key)
+	}
+	return pair.Value, nil
+}
+
+// Exists checks for existence
+func (s *Store) Exists(key interface{}) (bool, error) {
+	pair, err := s.client.Get(s.Key(key))
+	return pair != nil, err
+}
+
+// Delete deletes the object by id
+func (s *Store) Delete(key interface{}) error {
+	exists, err := s.Exists(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("not found %v", key)
+	}
+	log.Debug("Delete", "key", s.Key(key), "V", logV)
+	return s.client.Delete(s.Key(key))
+}
+
+// Entries returns the entries, unsorted
+func (s *Store) Entries() (<-chan store.Pair, error) {
+	pairs, err := s.client.List(s.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan store.Pair)
+	go func() {
+		defer close(out)
+		for _, pair := range pairs {
+			out <- store.Pair{
+				Key:   strings.TrimPrefix(pair.Key, s.prefix),
+				Value: pair.Value,
+			}
+		}
+	}()
+	return out, nil
+}
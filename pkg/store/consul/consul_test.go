@@ -0,0 +1,103 @@
+package consul // import "github.com/docker/infrakit/pkg/store/consul"
+
+import (
+	"testing"
+	"time"
+
+	testutil "github.com/docker/infrakit/pkg/testing"
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/docker/infrakit/pkg/util/consul"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRealConsul(t *testing.T) {
+
+	if testutil.SkipTests("consul") {
+		t.SkipNow()
+	}
+
+	containerName := "test-consul-store"
+
+	err := consul.RunContainer.Start(containerName)
+	require.NoError(t, err)
+
+	defer consul.StopContainer.Start(containerName)
+
+	client := testClient(t)
+
+	// wait until ready
+	for {
+		<-time.After(1 * time.Second)
+		if _, err := client.Get(namespace); err == nil {
+			log.Info("consul running")
+			break
+		}
+	}
+
+	t.Run("SaveLoad", testSaveLoad)
+
+	t.Run("KV", testKV)
+}
+
+func testClient(t *testing.T) *consul.Client {
+	client, err := consul.NewClient(consul.Options{
+		Address:        "127.0.0.1:8500",
+		RequestTimeout: 1 * time.Second,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func testSaveLoad(t *testing.T) {
+
+	snapshot, err := NewSnapshot(testClient(t), "groups.json")
+	require.NoError(t, err)
+	defer snapshot.Close()
+
+	// nothing saved yet
+	loaded := map[string]interface{}{}
+	require.NoError(t, snapshot.Load(&loaded))
+	require.Equal(t, 0, len(loaded))
+
+	config := types.AnyYAMLMust([]byte(`
+workers:
+  Allocation:
+    Size: 3
+`))
+	require.NoError(t, snapshot.Save(config))
+
+	loaded2 := types.Any{}
+	require.NoError(t, snapshot.Load(&loaded2))
+	require.Equal(t, types.Fingerprint(config), types.Fingerprint(&loaded2))
+}
+
+func testKV(t *testing.T) {
+
+	kv := NewStore(testClient(t), "instances")
+	defer kv.Close()
+
+	exists, err := kv.Exists("i-1")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, kv.Write("i-1", []byte("one")))
+	require.NoError(t, kv.Write("i-2", []byte("two")))
+
+	v, err := kv.Read("i-1")
+	require.NoError(t, err)
+	require.Equal(t, "one", string(v))
+
+	entries, err := kv.Entries()
+	require.NoError(t, err)
+	found := map[interface{}]string{}
+	for entry := range entries {
+		found[entry.Key] = string(entry.Value)
+	}
+	require.Equal(t, map[interface{}]string{"i-1": "one", "i-2": "two"}, found)
+
+	require.NoError(t, kv.Delete("i-1"))
+	require.Error(t, kv.Delete("i-1"))
+
+	_, err = kv.Read("i-1")
+	require.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package controller // import "github.com/docker/infrakit/pkg/testing/controller"
 
 import (
+	"time"
+
 	"github.com/docker/infrakit/pkg/spi/controller"
 	"github.com/docker/infrakit/pkg/types"
 )
@@ -26,6 +28,15 @@ type Controller struct {
 
 	// Free tells the controller to pause management of objects matching.  To resume, commit again.
 	DoFree func(metadata *types.Metadata) ([]types.Object, error)
+
+	// Claim leases an idle resource of the object matching the metadata to the consumer
+	DoClaim func(metadata types.Metadata, consumer string, ttl time.Duration) (controller.Lease, error)
+
+	// Release ends the lease of the given id
+	DoRelease func(metadata types.Metadata, id string) error
+
+	// Leases returns the current leases of the object matching the metadata
+	DoLeases func(metadata types.Metadata) ([]controller.Lease, error)
 }
 
 // Plan implements pkg/controller/Controller.Plan
@@ -48,3 +59,18 @@ func (t *Controller) Free(metadata *types.Metadata) ([]types.Object, error) {
 	return t.DoFree(metadata)
 
 }
+
+// Claim implements pkg/controller/Leasing.Claim
+func (t *Controller) Claim(metadata types.Metadata, consumer string, ttl time.Duration) (controller.Lease, error) {
+	return t.DoClaim(metadata, consumer, ttl)
+}
+
+// Release implements pkg/controller/Leasing.Release
+func (t *Controller) Release(metadata types.Metadata, id string) error {
+	return t.DoRelease(metadata, id)
+}
+
+// Leases implements pkg/controller/Leasing.Leases
+func (t *Controller) Leases(metadata types.Metadata) ([]controller.Lease, error) {
+	return t.DoLeases(metadata)
+}
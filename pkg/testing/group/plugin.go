@@ -31,6 +31,12 @@ type Plugin struct {
 
 	// DoSetSize implements SetSize
 	DoSetSize func(id group.ID, size int) error
+
+	// DoPromoteUpdate implements PromoteUpdate
+	DoPromoteUpdate func(id group.ID) error
+
+	// DoAbortUpdate implements AbortUpdate
+	DoAbortUpdate func(id group.ID) error
 }
 
 // CommitGroup commits spec for a group
@@ -72,3 +78,13 @@ func (t *Plugin) Size(id group.ID) (int, error) {
 func (t *Plugin) SetSize(id group.ID, size int) error {
 	return t.DoSetSize(id, size)
 }
+
+// PromoteUpdate resumes an update paused after its canary phase
+func (t *Plugin) PromoteUpdate(id group.ID) error {
+	return t.DoPromoteUpdate(id)
+}
+
+// AbortUpdate aborts an update paused after its canary phase
+func (t *Plugin) AbortUpdate(id group.ID) error {
+	return t.DoAbortUpdate(id)
+}
@@ -3,6 +3,7 @@ package manager // import "github.com/docker/infrakit/pkg/testing/manager"
 import (
 	"net/url"
 
+	"github.com/docker/infrakit/pkg/spi/stack"
 	"github.com/docker/infrakit/pkg/types"
 )
 
@@ -15,6 +16,9 @@ type Plugin struct {
 	// DoIsLeader returns true if manager is leader
 	DoIsLeader func() (bool, error)
 
+	// DoStepDown makes the leader give up the leadership
+	DoStepDown func(successor string) error
+
 	// DoEnforce enforces infrastructure state to match that of the specs
 	DoEnforce func(specs []types.Spec) error
 
@@ -26,6 +30,15 @@ type Plugin struct {
 
 	// DoTerminate destroys all resources associated with the specs
 	DoTerminate func(specs []types.Spec) error
+
+	// DoHistory returns the revisions of the specs
+	DoHistory func() ([]stack.Revision, error)
+
+	// DoRevision returns the revision with its specs
+	DoRevision func(rev int) (stack.Revision, error)
+
+	// DoRollback enforces the specs of an earlier revision
+	DoRollback func(rev int) error
 }
 
 // IsLeader returns true if manager is leader
@@ -38,6 +51,11 @@ func (t *Plugin) LeaderLocation() (*url.URL, error) {
 	return t.DoLeaderLocation()
 }
 
+// StepDown makes the leader give up the leadership
+func (t *Plugin) StepDown(successor string) error {
+	return t.DoStepDown(successor)
+}
+
 // Enforce enforces infrastructure state to match that of the specs
 func (t *Plugin) Enforce(specs []types.Spec) error {
 	return t.DoEnforce(specs)
@@ -57,3 +75,18 @@ func (t *Plugin) Inspect() ([]types.Object, error) {
 func (t *Plugin) Terminate(specs []types.Spec) error {
 	return t.DoTerminate(specs)
 }
+
+// History returns the revisions of the specs
+func (t *Plugin) History() ([]stack.Revision, error) {
+	return t.DoHistory()
+}
+
+// Revision returns the revision with its specs
+func (t *Plugin) Revision(rev int) (stack.Revision, error) {
+	return t.DoRevision(rev)
+}
+
+// Rollback enforces the specs of an earlier revision
+func (t *Plugin) Rollback(rev int) error {
+	return t.DoRollback(rev)
+}
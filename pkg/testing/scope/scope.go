@@ -32,6 +32,10 @@ func (f fakeLeaderT) LeaderLocation() (*url.URL, error) {
 	return nil, nil
 }
 
+func (f fakeLeaderT) StepDown(successor string) error {
+	return nil
+}
+
 type fakePlugins map[string]*plugin.Endpoint
 
 // Find implements discovery.Plugins
@@ -0,0 +1,240 @@
+package consul // import "github.com/docker/infrakit/pkg/util/consul"
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	logutil "github.com/docker/infrakit/pkg/log"
+	"github.com/docker/infrakit/pkg/util/exec"
+)
+
+var (
+	log    = logutil.New("module", "util/consul")
+	debugV = logutil.V(500)
+)
+
+// Options is for configuring the consul client
+type Options struct {
+
+	// Address is the host:port of the consul agent, e.g. 127.0.0.1:8500
+	Address string
+
+	// Scheme is http or https
+	Scheme string
+
+	// Datacenter is the optional datacenter.  The datacenter of the agent is used if not set.
+	Datacenter string
+
+	// Token is the optional ACL token
+	Token string
+
+	// RequestTimeout is used for all requests to consul
+	RequestTimeout time.Duration
+
+	// TLS is the tls configuration for https
+	TLS *tls.Config `json:"-" yaml:"-"`
+}
+
+// KVPair is an entry in the consul kv store
+type KVPair struct {
+	Key         string
+	Value       []byte
+	Session     string
+	ModifyIndex uint64
+}
+
+// Client is a client of the consul http api.  Only the kv store and sessions are supported.
+type Client struct {
+	Options Options
+	client  *http.Client
+}
+
+// NewClient returns a client
+func NewClient(options Options) (*Client, error) {
+	if options.Address == "" {
+		return nil, fmt.Errorf("no address")
+	}
+	if options.Scheme == "" {
+		options.Scheme = "http"
+	}
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: options.TLS,
+	}
+	return &Client{
+		Options: options,
+		client:  &http.Client{Transport: transport, Timeout: options.RequestTimeout},
+	}, nil
+}
+
+// Close closes the idle connections
+func (c *Client) Close() error {
+	if t, is := c.client.Transport.(*http.Transport); is {
+		t.CloseIdleConnections()
+	}
+	return nil
+}
+
+// ErrNotFound is returned when the session or key does not exist
+var ErrNotFound = fmt.Errorf("not found")
+
+func (c *Client) do(method, path string, query url.Values, body io.Reader, out interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.Options.Datacenter != "" {
+		query.Set("dc", c.Options.Datacenter)
+	}
+	u := url.URL{
+		Scheme:   c.Options.Scheme,
+		Host:     c.Options.Address,
+		Path:     path,
+		RawQuery: query.Encode(),
+	}
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	if c.Options.Token != "" {
+		req.Header.Set("X-Consul-Token", c.Options.Token)
+	}
+
+	resp, err := c.client.Do(req)
+	log.Debug("consul", "method", method, "path", path, "query", query, "err", err, "V", debugV)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		message, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("consul %s %s: %s %s", method, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func kvPath(key string) string {
+	return "/v1/kv/" + strings.TrimPrefix(key, "/")
+}
+
+// Get returns the pair of the key.  The pair is nil if the key does not exist.
+func (c *Client) Get(key string) (*KVPair, error) {
+	pairs := []*KVPair{}
+	err := c.do(http.MethodGet, kvPath(key), nil, nil, &pairs)
+	if err == ErrNotFound {
+		return nil, nil
+	}
+	if err != nil || len(pairs) == 0 {
+		return nil, err
+	}
+	return pairs[0], nil
+}
+
+// List returns the pairs whose keys have the prefix
+func (c *Client) List(prefix string) ([]*KVPair, error) {
+	pairs := []*KVPair{}
+	err := c.do(http.MethodGet, kvPath(prefix), url.Values{"recurse": {""}}, nil, &pairs)
+	if err == ErrNotFound {
+		return []*KVPair{}, nil
+	}
+	return pairs, err
+}
+
+// Put writes the value of the key
+func (c *Client) Put(key string, value []byte) error {
+	ok := false
+	if err := c.do(http.MethodPut, kvPath(key), nil, bytes.NewReader(value), &ok); err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("cannot write %v", key)
+	}
+	return nil
+}
+
+// Delete deletes the key
+func (c *Client) Delete(key string) error {
+	return c.do(http.MethodDelete, kvPath(key), nil, nil, nil)
+}
+
+// Acquire writes the value and locks the key with the session.  Returns true if the session
+// holds the lock, which can be acquired again by the same session.
+func (c *Client) Acquire(key string, value []byte, session string) (bool, error) {
+	ok := false
+	err := c.do(http.MethodPut, kvPath(key), url.Values{"acquire": {session}}, bytes.NewReader(value), &ok)
+	return ok, err
+}
+
+// Release unlocks the key held by the session
+func (c *Client) Release(key string, session string) (bool, error) {
+	ok := false
+	err := c.do(http.MethodPut, kvPath(key), url.Values{"release": {session}}, nil, &ok)
+	return ok, err
+}
+
+// CreateSession creates a session that expires if not renewed within the ttl.  Locks held by the
+// session are released when the session is invalidated.
+func (c *Client) CreateSession(name string, ttl time.Duration) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"Name":     name,
+		"TTL":      ttl.String(),
+		"Behavior": "release",
+	})
+	if err != nil {
+		return "", err
+	}
+	created := struct {
+		ID string
+	}{}
+	if err := c.do(http.MethodPut, "/v1/session/create", nil, bytes.NewReader(body), &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// RenewSession renews the ttl of the session.  ErrNotFound is returned if the session
+// has been invalidated.
+func (c *Client) RenewSession(id string) error {
+	sessions := []interface{}{}
+	if err := c.do(http.MethodPut, "/v1/session/renew/"+id, nil, nil, &sessions); err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DestroySession invalidates the session and releases its locks
+func (c *Client) DestroySession(id string) error {
+	return c.do(http.MethodPut, "/v1/session/destroy/"+id, nil, nil, nil)
+}
+
+var (
+
+	// RunContainer is a command that shells out to Docker to run a consul agent in dev mode in a container
+	RunContainer = exec.Command(`
+docker run --rm -d \
+       -p 8500:8500 \
+       --name {{ arg 1 }} \
+       consul agent -dev -client 0.0.0.0
+`)
+
+	// StopContainer stops the consul container
+	StopContainer = exec.Command(`docker stop {{ arg 1 }}`)
+)
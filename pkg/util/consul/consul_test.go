@@ -0,0 +1,105 @@
+package consul // import "github.com/docker/infrakit/pkg/util/consul"
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/infrakit/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// Test verifies that the types here are workable with types.Any
+func TestTypeAnyable(t *testing.T) {
+	options := Options{
+		Address:        "127.0.0.1:8500",
+		Datacenter:     "dc1",
+		RequestTimeout: 1 * time.Second,
+	}
+
+	any := types.AnyValueMust(options)
+	require.True(t, len(any.String()) > 0)
+
+	opt2 := Options{}
+	require.NoError(t, any.Decode(&opt2))
+
+	require.Equal(t, options, opt2)
+}
+
+func TestClient(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "s3cr3t", req.Header.Get("X-Consul-Token"))
+		require.Equal(t, "dc1", req.URL.Query().Get("dc"))
+
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+
+		switch {
+		case req.URL.Path == "/v1/kv/missing":
+			resp.WriteHeader(http.StatusNotFound)
+		case req.URL.Path == "/v1/kv/infrakit/key" && req.Method == http.MethodGet:
+			resp.Write([]byte(`[{"Key":"infrakit/key","Value":"aGVsbG8=","Session":"s1","ModifyIndex":7}]`))
+		case req.URL.Path == "/v1/kv/infrakit/lock":
+			require.Equal(t, "s1", req.URL.Query().Get("acquire"))
+			resp.Write([]byte(`false`))
+		case req.URL.Path == "/v1/session/create":
+			resp.Write([]byte(`{"ID":"s1"}`))
+		case req.URL.Path == "/v1/session/renew/s1":
+			resp.Write([]byte(`[]`))
+		case req.Method == http.MethodPut:
+			resp.Write([]byte(`true`))
+		default:
+			resp.WriteHeader(http.StatusInternalServerError)
+			resp.Write([]byte("boom"))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Options{
+		Address:    strings.TrimPrefix(server.URL, "http://"),
+		Datacenter: "dc1",
+		Token:      "s3cr3t",
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	pair, err := client.Get("missing")
+	require.NoError(t, err)
+	require.Nil(t, pair)
+
+	pair, err = client.Get("/infrakit/key")
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(pair.Value))
+	require.Equal(t, "s1", pair.Session)
+
+	require.NoError(t, client.Put("infrakit/key", []byte("world")))
+
+	session, err := client.CreateSession("infrakit-manager1", 10*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "s1", session)
+
+	acquired, err := client.Acquire("infrakit/lock", []byte("manager1"), session)
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	require.Equal(t, ErrNotFound, client.RenewSession(session))
+
+	err = client.Delete("infrakit/key")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "boom")
+
+	require.Equal(t, []string{
+		"GET /v1/kv/missing ",
+		"GET /v1/kv/infrakit/key ",
+		"PUT /v1/kv/infrakit/key world",
+		`PUT /v1/session/create {"Behavior":"release","Name":"infrakit-manager1","TTL":"10s"}`,
+		"PUT /v1/kv/infrakit/lock manager1",
+		"PUT /v1/session/renew/s1 ",
+		"DELETE /v1/kv/infrakit/key ",
+	}, requests)
+}